@@ -92,6 +92,9 @@ You may specify one or more benchmarks or controls to run (separated by a space)
 		AddStringFlag(constants.ArgWhere, "", "SQL 'where' clause, or named query, used to filter controls (cannot be used with '--tag')").
 		AddIntFlag(constants.ArgDatabaseQueryTimeout, constants.DatabaseDefaultCheckQueryTimeout, "The query timeout").
 		AddIntFlag(constants.ArgMaxParallel, constants.DefaultMaxConnections, "The maximum number of concurrent database connections to open").
+		AddIntFlag(constants.ArgControlTimeout, 0, "The maximum time in seconds a single control may run for (0 means no limit)").
+		AddIntFlag(constants.ArgControlRetries, 0, "The number of times to retry a control query which fails with a transient error").
+		AddBoolFlag(constants.ArgMergeChildTags, false, "Merge the tags of child benchmarks into the tags of their parent benchmark").
 		AddBoolFlag(constants.ArgModInstall, true, "Specify whether to install mod dependencies before running the check").
 		AddBoolFlag(constants.ArgInput, true, "Enable interactive prompts").
 		AddBoolFlag(constants.ArgSnapshot, false, "Create snapshot in Turbot Pipes with the default (workspace) visibility").
@@ -269,7 +272,7 @@ func getExecutionTrees(ctx context.Context, initData *control.InitData, args ...
 
 	if initData.ExportManager.HasNamedExport(viper.GetStringSlice(constants.ArgExport)) {
 		// create a single merged execution tree from all arguments
-		executionTree, err := controlexecute.NewExecutionTree(ctx, initData.Workspace, initData.Client, initData.ControlFilterWhereClause, args...)
+		executionTree, err := controlexecute.NewExecutionTree(ctx, initData.Workspace, initData.Client, initData.ControlFilterWhereClause, initData.ControlFilter, args...)
 		if err != nil {
 			return nil, sperr.WrapWithMessage(err, "could not create merged execution tree")
 		}
@@ -280,7 +283,7 @@ func getExecutionTrees(ctx context.Context, initData *control.InitData, args ...
 			if error_helpers.IsContextCanceled(ctx) {
 				return nil, ctx.Err()
 			}
-			executionTree, err := controlexecute.NewExecutionTree(ctx, initData.Workspace, initData.Client, initData.ControlFilterWhereClause, arg)
+			executionTree, err := controlexecute.NewExecutionTree(ctx, initData.Workspace, initData.Client, initData.ControlFilterWhereClause, initData.ControlFilter, arg)
 			if err != nil {
 				return nil, sperr.WrapWithMessage(err, "could not create execution tree for %s", arg)
 			}
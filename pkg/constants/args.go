@@ -40,6 +40,9 @@ const (
 	ArgProgress                = "progress"
 	ArgExport                  = "export"
 	ArgMaxParallel             = "max-parallel"
+	ArgControlTimeout          = "control-timeout"
+	ArgControlRetries          = "control-retries"
+	ArgMergeChildTags          = "merge-child-tags"
 	ArgLogLevel                = "log-level"
 	ArgDryRun                  = "dry-run"
 	ArgWhere                   = "where"
@@ -67,6 +70,7 @@ const (
 	ArgModLocation             = "mod-location"
 	ArgSnapshotLocation        = "snapshot-location"
 	ArgSnapshotTitle           = "snapshot-title"
+	ArgLocale                  = "locale"
 	ArgDatabaseStartTimeout    = "database-start-timeout"
 	ArgDatabaseSSLPassword     = "database-ssl-password"
 	ArgMemoryMaxMb             = "memory-max-mb"
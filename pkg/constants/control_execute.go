@@ -1,9 +1,15 @@
 package constants
 
+import "time"
+
 const (
 	// ControlQueryCancellationTimeoutSecs is maximum number of seconds to wait for control queries to finish cancelling
 	ControlQueryCancellationTimeoutSecs = 30
 	// MaxControlRunAttempts determines how many time should a cotnrol run should be retried
 	// in the case of a GRPC connectivity error
 	MaxControlRunAttempts = 2
+	// ControlRetryBaseBackoff is the delay before the first retry of a control query which failed with a transient error
+	// the delay doubles for each subsequent retry, up to ControlRetryMaxBackoff
+	ControlRetryBaseBackoff = 500 * time.Millisecond
+	ControlRetryMaxBackoff  = 30 * time.Second
 )
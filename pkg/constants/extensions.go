@@ -13,6 +13,7 @@ const (
 	JsonExtension        = ".json"
 	TextExtension        = ".txt"
 	SnapshotExtension    = ".sps"
+	OpenMetricsExtension = ".prom"
 	TokenExtension       = ".tptt"
 	LegacyTokenExtension = ".sptt"
 )
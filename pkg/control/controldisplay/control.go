@@ -95,6 +95,15 @@ func (r ControlRenderer) Render() string {
 		// newline after control heading
 		formattedPreResultIndent)
 
+	// if the control is deprecated, render the deprecation notice
+	if r.run.Deprecated {
+		deprecationRenderer := NewDeprecationRenderer(r.run.DeprecationMessage, r.width, r.parentIndent())
+		controlStrings = append(controlStrings,
+			deprecationRenderer.Render(),
+			// newline after deprecation notice
+			formattedPreResultIndent)
+	}
+
 	// if the control is in error, render an error
 	if r.run.GetError() != nil {
 		errorRenderer := NewErrorRenderer(r.run.GetError(), r.width, r.parentIndent())
@@ -0,0 +1,38 @@
+package controldisplay
+
+import (
+	"fmt"
+
+	"github.com/turbot/go-kit/helpers"
+)
+
+// DeprecationRenderer renders the deprecation notice of a deprecated control
+type DeprecationRenderer struct {
+	message string
+
+	// screen width
+	width  int
+	indent string
+}
+
+func NewDeprecationRenderer(message string, width int, indent string) *DeprecationRenderer {
+	return &DeprecationRenderer{
+		message: message,
+		width:   width,
+		indent:  indent,
+	}
+}
+
+func (r DeprecationRenderer) Render() string {
+	formattedIndent := fmt.Sprintf("%s", ControlColors.Indent(r.indent))
+	indentWidth := helpers.PrintableLength(formattedIndent)
+
+	notice := "Deprecated"
+	if r.message != "" {
+		notice = fmt.Sprintf("Deprecated: %s", r.message)
+	}
+	// truncate the notice to the available width
+	notice = helpers.TruncateString(notice, r.width-indentWidth)
+
+	return fmt.Sprintf("%s%s", formattedIndent, ControlColors.ReasonSkip(notice))
+}
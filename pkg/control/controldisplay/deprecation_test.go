@@ -0,0 +1,44 @@
+package controldisplay
+
+import (
+	"fmt"
+	"testing"
+)
+
+type deprecationTest struct {
+	message  string
+	width    int
+	expected string
+}
+
+func testCasesDeprecation() map[string]deprecationTest {
+	return map[string]deprecationTest{
+		"no message": {
+			width:    100,
+			expected: fmt.Sprintf("%s%s", ControlColors.Indent("| "), ControlColors.ReasonSkip("Deprecated")),
+		},
+		"message": {
+			message:  "use control c2",
+			width:    100,
+			expected: fmt.Sprintf("%s%s", ControlColors.Indent("| "), ControlColors.ReasonSkip("Deprecated: use control c2")),
+		},
+		"message truncate": {
+			message:  "use control c2 which checks the same thing",
+			width:    30,
+			expected: fmt.Sprintf("%s%s", ControlColors.Indent("| "), ControlColors.ReasonSkip("Deprecated: use control c2 …")),
+		},
+	}
+}
+
+func TestDeprecation(t *testing.T) {
+	themeDef := ColorSchemes["plain"]
+	scheme, _ := NewControlColorScheme(themeDef)
+	ControlColors = scheme
+
+	for name, test := range testCasesDeprecation() {
+		output := NewDeprecationRenderer(test.message, test.width, "| ").Render()
+		if output != test.expected {
+			t.Errorf("Test: '%s'' FAILED : \nexpected:\n %v \ngot:\n %v\n", name, test.expected, output)
+		}
+	}
+}
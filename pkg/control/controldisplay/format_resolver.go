@@ -26,6 +26,7 @@ func NewFormatResolver(ctx context.Context) (*FormatResolver, error) {
 		&NullFormatter{},
 		&TextFormatter{},
 		&SnapshotFormatter{},
+		&OpenMetricsFormatter{},
 	}
 
 	res := &FormatResolver{
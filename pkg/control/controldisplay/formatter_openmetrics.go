@@ -0,0 +1,116 @@
+package controldisplay
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/control/controlexecute"
+	"github.com/turbot/steampipe/pkg/control/controlstatus"
+)
+
+// the maximum combined length of the label names and values of an OpenMetrics exemplar
+const openMetricsMaxExemplarLabelLength = 128
+
+var openMetricsStatuses = []string{
+	constants.ControlAlarm,
+	constants.ControlOk,
+	constants.ControlInfo,
+	constants.ControlSkip,
+	constants.ControlError,
+}
+
+// OpenMetricsFormatter renders the control results in the OpenMetrics text format
+// each control run has a counter for each result status, labelled with the control and its parent benchmark
+// the alarm counters have an exemplar with the file and line the control is defined at
+type OpenMetricsFormatter struct {
+	FormatterBase
+}
+
+func (f *OpenMetricsFormatter) Format(_ context.Context, tree *controlexecute.ExecutionTree) (io.Reader, error) {
+	// sort the runs so the output is stable
+	runs := append([]*controlexecute.ControlRun{}, tree.ControlRuns...)
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].FullName < runs[j].FullName
+	})
+
+	var sb strings.Builder
+	for _, status := range openMetricsStatuses {
+		metricName := fmt.Sprintf("steampipe_control_%s", status)
+		sb.WriteString(fmt.Sprintf("# TYPE %s counter\n", metricName))
+		sb.WriteString(fmt.Sprintf("# HELP %s Number of %s results of the control.\n", metricName, status))
+		for _, run := range runs {
+			count := openMetricsStatusCount(run.Summary, status)
+			sb.WriteString(fmt.Sprintf("%s_total{%s} %d", metricName, openMetricsLabels(run), count))
+			if status == constants.ControlAlarm {
+				sb.WriteString(openMetricsExemplar(run, count))
+			}
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString("# EOF\n")
+
+	return strings.NewReader(sb.String()), nil
+}
+
+func (f *OpenMetricsFormatter) FileExtension() string {
+	return constants.OpenMetricsExtension
+}
+
+func (f OpenMetricsFormatter) Name() string {
+	return constants.OutputFormatOpenMetrics
+}
+
+func openMetricsStatusCount(summary *controlstatus.StatusSummary, status string) int {
+	if summary == nil {
+		return 0
+	}
+	switch status {
+	case constants.ControlAlarm:
+		return summary.Alarm
+	case constants.ControlOk:
+		return summary.Ok
+	case constants.ControlInfo:
+		return summary.Info
+	case constants.ControlSkip:
+		return summary.Skip
+	case constants.ControlError:
+		return summary.Error
+	}
+	return 0
+}
+
+func openMetricsLabels(run *controlexecute.ControlRun) string {
+	labels := []string{openMetricsLabel("control", run.FullName)}
+	if run.Group != nil {
+		labels = append(labels, openMetricsLabel("benchmark", run.Group.GroupId))
+	}
+	if run.Severity != "" {
+		labels = append(labels, openMetricsLabel("severity", run.Severity))
+	}
+	return strings.Join(labels, ",")
+}
+
+// openMetricsExemplar returns the exemplar linking the alarm counter of the run to the control definition
+// OpenMetrics limits the length of the exemplar labels, so if required the start of the file path is trimmed
+func openMetricsExemplar(run *controlexecute.ControlRun, count int) string {
+	if run.FileName == "" {
+		return ""
+	}
+	line := strconv.Itoa(run.StartLine)
+	file := []rune(run.FileName)
+	if maxFileLength := openMetricsMaxExemplarLabelLength - len("file") - len("line") - len(line); len(file) > maxFileLength {
+		file = file[len(file)-maxFileLength:]
+	}
+	return fmt.Sprintf(" # {%s,%s} %d", openMetricsLabel("file", string(file)), openMetricsLabel("line", line), count)
+}
+
+var openMetricsLabelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func openMetricsLabel(name, value string) string {
+	return fmt.Sprintf(`%s="%s"`, name, openMetricsLabelValueEscaper.Replace(value))
+}
@@ -0,0 +1,72 @@
+package controldisplay
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/control/controlexecute"
+	"github.com/turbot/steampipe/pkg/control/controlstatus"
+)
+
+func TestOpenMetricsFormatter(t *testing.T) {
+	group := &controlexecute.ResultGroup{GroupId: "local.benchmark.b1"}
+	tree := &controlexecute.ExecutionTree{
+		ControlRuns: []*controlexecute.ControlRun{
+			{
+				FullName:  "local.control.c2",
+				FileName:  "/mods/test/controls.sp",
+				StartLine: 12,
+				Severity:  "high",
+				Summary:   &controlstatus.StatusSummary{Alarm: 3, Ok: 1},
+				Group:     group,
+			},
+			{
+				FullName:  "local.control.c1",
+				FileName:  "/" + strings.Repeat("a", 200) + "/controls.sp",
+				StartLine: 1,
+				Summary:   &controlstatus.StatusSummary{Ok: 2, Skip: 1},
+				Group:     group,
+			},
+		},
+	}
+
+	reader, err := (&OpenMetricsFormatter{}).Format(context.Background(), tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+
+	expectedLines := []string{
+		"# TYPE steampipe_control_alarm counter",
+		`steampipe_control_alarm_total{control="local.control.c1",benchmark="local.benchmark.b1"} 0 # {file="` + strings.Repeat("a", 107) + `/controls.sp",line="1"} 0`,
+		`steampipe_control_alarm_total{control="local.control.c2",benchmark="local.benchmark.b1",severity="high"} 3 # {file="/mods/test/controls.sp",line="12"} 3`,
+		`steampipe_control_ok_total{control="local.control.c1",benchmark="local.benchmark.b1"} 2`,
+		`steampipe_control_skip_total{control="local.control.c1",benchmark="local.benchmark.b1"} 1`,
+		`steampipe_control_error_total{control="local.control.c2",benchmark="local.benchmark.b1",severity="high"} 0`,
+	}
+	for _, expected := range expectedLines {
+		found := false
+		for _, line := range lines {
+			if line == expected {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Expected output to contain line '%s', got:\n%s", expected, output)
+		}
+	}
+	if lines[len(lines)-1] != "# EOF" {
+		t.Errorf("Expected output to end with '# EOF', got '%s'", lines[len(lines)-1])
+	}
+	// only the alarm counters have exemplars
+	if count := strings.Count(string(output), " # {"); count != 2 {
+		t.Errorf("Expected 2 exemplars, got %d", count)
+	}
+}
@@ -44,6 +44,14 @@ var formatterTestCase = []testCase{
 			name:      constants.OutputFormatSnapshot,
 		},
 	},
+	{
+		input: "openmetrics",
+		expected: testFormatter{
+			alias:     "",
+			extension: constants.OpenMetricsExtension,
+			name:      constants.OutputFormatOpenMetrics,
+		},
+	},
 	{
 		input: "csv",
 		expected: testFormatter{
@@ -0,0 +1,88 @@
+package controlexecute
+
+import (
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	typehelpers "github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+	"golang.org/x/exp/maps"
+)
+
+// the formats used to display hinted timestamp and date columns - these match the query output format
+const (
+	hintedTimestampFormat = "2006-01-02 15:04:05"
+	hintedDateFormat      = "2006-01-02"
+)
+
+// the layouts used to parse string values of hinted timestamp and date columns
+var hintedTimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05", hintedDateFormat}
+
+// formatHintedValue formats a column value according to the sql data type given by the column type hint
+// if the value cannot be converted to the hinted type it is formatted as is
+func formatHintedValue(val any, dataType string) string {
+	switch dataType {
+	case "TIMESTAMP", "DATE":
+		t, ok := toTime(val)
+		if !ok {
+			break
+		}
+		if dataType == "DATE" {
+			return t.Format(hintedDateFormat)
+		}
+		return t.Format(hintedTimestampFormat)
+	case "NUMERIC":
+		if s, ok := val.(string); ok {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+				return strconv.FormatFloat(f, 'f', -1, 64)
+			}
+		}
+	case "BOOL":
+		if s, ok := val.(string); ok {
+			if b, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+				return strconv.FormatBool(b)
+			}
+		}
+	case "JSONB":
+		if _, ok := val.(string); !ok {
+			if jsonBytes, err := json.Marshal(val); err == nil {
+				return string(jsonBytes)
+			}
+		}
+	}
+	return typehelpers.ToString(val)
+}
+
+func toTime(val any) (time.Time, bool) {
+	switch v := val.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		for _, layout := range hintedTimeLayouts {
+			if t, err := time.Parse(layout, strings.TrimSpace(v)); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// warnUnknownColumnTypeHints logs a warning for each column type hint of the control
+// which refers to a column not returned by the control query
+func (r *ControlRun) warnUnknownColumnTypeHints(cols []*queryresult.ColumnDef) {
+	if r.Control == nil || len(r.Control.ColumnTypes) == 0 {
+		return
+	}
+	returnedColumns := make(map[string]struct{}, len(cols))
+	for _, c := range cols {
+		returnedColumns[c.Name] = struct{}{}
+	}
+	for _, column := range maps.Keys(r.Control.ColumnTypes) {
+		if _, ok := returnedColumns[column]; !ok {
+			log.Printf("[WARN] %s has a type hint for column '%s' which is not returned by the control query", r.Control.Name(), column)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package controlexecute
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// ControlFilter determines whether a control is included in the execution tree
+type ControlFilter func(control *modconfig.Control) bool
+
+// NewTagControlFilter returns a ControlFilter which includes the controls whose tags match the given 'key=value' pairs
+// a control matches if, for every key, it has a tag with that key set to one of the given values for the key
+// e.g. ["service=s3", "service=ec2", "severity=critical"] matches critical controls for either s3 or ec2
+func NewTagControlFilter(tags []string) (ControlFilter, error) {
+	tagValues := make(map[string][]string)
+	for _, tag := range tags {
+		key, value, ok := strings.Cut(tag, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid tag filter '%s' - tag filters must be of the form 'key=value'", tag)
+		}
+		tagValues[key] = append(tagValues[key], strings.TrimSpace(value))
+	}
+
+	return func(control *modconfig.Control) bool {
+		for key, values := range tagValues {
+			value, ok := control.Tags[key]
+			if !ok || !helpers.StringSliceContains(values, value) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
@@ -0,0 +1,97 @@
+package controlexecute
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/workspace"
+)
+
+type tagControlFilterTest struct {
+	tags             []string
+	expectedControls []string
+	expectedGroups   []string
+	expectedError    string
+}
+
+var testCasesTagControlFilter = map[string]tagControlFilterTest{
+	"single tag": {
+		tags:             []string{"service=s3"},
+		expectedControls: []string{"test.control.c1", "test.control.c2"},
+		expectedGroups:   []string{"test.benchmark.b1"},
+	},
+	"values for the same key are or'd": {
+		tags:             []string{"service=s3", "service=ec2"},
+		expectedControls: []string{"test.control.c1", "test.control.c2", "test.control.c3"},
+		expectedGroups:   []string{"test.benchmark.b1", "test.benchmark.b2"},
+	},
+	"different keys are and'd": {
+		tags:             []string{"service=s3", "severity=critical"},
+		expectedControls: []string{"test.control.c1"},
+		expectedGroups:   []string{"test.benchmark.b1"},
+	},
+	"no match": {
+		tags: []string{"service=lambda"},
+	},
+	"invalid tag": {
+		tags:          []string{"service"},
+		expectedError: "invalid tag filter 'service' - tag filters must be of the form 'key=value'",
+	},
+}
+
+func TestTagControlFilter(t *testing.T) {
+	// b1 contains c1 and c2, b2 contains c3
+	mod := modconfig.NewMod("test", "", hcl.Range{})
+	controlTags := []map[string]string{
+		{"service": "s3", "severity": "critical"},
+		{"service": "s3", "severity": "low"},
+		{"service": "ec2", "severity": "critical"},
+	}
+	controls := make([]modconfig.ModTreeItem, len(controlTags))
+	for i, tags := range controlTags {
+		control := newTestControl(fmt.Sprintf("c%d", i+1))
+		control.Mod = mod
+		control.Tags = tags
+		controls[i] = control
+	}
+	b1 := newTestBenchmark(mod, "b1", controls[0], controls[1])
+	b2 := newTestBenchmark(mod, "b2", controls[2])
+	root := newTestBenchmark(mod, "root", b1, b2)
+
+	for name, test := range testCasesTagControlFilter {
+		filter, err := NewTagControlFilter(test.tags)
+		if test.expectedError != "" {
+			if err == nil || err.Error() != test.expectedError {
+				t.Errorf("Test %s FAILED. Expected error '%s', got %v", name, test.expectedError, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, err)
+			continue
+		}
+
+		tree := &ExecutionTree{Workspace: &workspace.Workspace{Mod: mod}, controlFilter: filter}
+		tree.Root = NewRootResultGroup(context.Background(), tree, root)
+
+		var runs []string
+		for _, run := range tree.ControlRuns {
+			runs = append(runs, run.Control.Name())
+		}
+		if !reflect.DeepEqual(runs, test.expectedControls) {
+			t.Errorf("Test %s FAILED. Expected controls %v, got %v", name, test.expectedControls, runs)
+		}
+		// groups with no matching controls are pruned
+		var groups []string
+		for _, group := range tree.Root.Groups[0].Groups {
+			groups = append(groups, group.GroupId)
+		}
+		if !reflect.DeepEqual(groups, test.expectedGroups) {
+			t.Errorf("Test %s FAILED. Expected groups %v, got %v", name, test.expectedGroups, groups)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package controlexecute
+
+import (
+	"sort"
+
+	"github.com/turbot/steampipe/pkg/control/controlstatus"
+)
+
+// ControlFlakiness describes how often the status of a control changed across a series of runs
+type ControlFlakiness struct {
+	ControlId string `json:"control_id"`
+	// the status of the control in each run it was executed in, in chronological order
+	// the status of a run is its worst result status
+	Statuses []string `json:"statuses"`
+	// the number of times the status changed between consecutive runs of the control
+	StatusChanges int `json:"status_changes"`
+	// the proportion of consecutive runs in which the status changed, in the range 0-1
+	Volatility float64 `json:"volatility"`
+}
+
+// ControlFlakinessReport computes the status volatility of each control across the given result trees,
+// which are expected to be historical runs of the same benchmark, in chronological order
+// controls are matched by name - a control need not be present in every run
+// the results are ranked by volatility, then number of status changes (both descending), then control name
+func ControlFlakinessReport(history []*ResultGroup) []ControlFlakiness {
+	statuses := make(map[string][]string)
+	for _, root := range history {
+		if root == nil {
+			continue
+		}
+		// a control may be a child of more than one benchmark - only use its first run
+		seen := make(map[string]bool)
+		for _, run := range root.allControlRuns() {
+			if seen[run.FullName] {
+				continue
+			}
+			seen[run.FullName] = true
+			statuses[run.FullName] = append(statuses[run.FullName], controlRunStatus(run))
+		}
+	}
+
+	res := make([]ControlFlakiness, 0, len(statuses))
+	for controlId, controlStatuses := range statuses {
+		flakiness := ControlFlakiness{
+			ControlId: controlId,
+			Statuses:  controlStatuses,
+		}
+		for i := 1; i < len(controlStatuses); i++ {
+			if controlStatuses[i] != controlStatuses[i-1] {
+				flakiness.StatusChanges++
+			}
+		}
+		if len(controlStatuses) > 1 {
+			flakiness.Volatility = float64(flakiness.StatusChanges) / float64(len(controlStatuses)-1)
+		}
+		res = append(res, flakiness)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Volatility != res[j].Volatility {
+			return res[i].Volatility > res[j].Volatility
+		}
+		if res[i].StatusChanges != res[j].StatusChanges {
+			return res[i].StatusChanges > res[j].StatusChanges
+		}
+		return res[i].ControlId < res[j].ControlId
+	})
+	return res
+}
+
+// controlRunStatus returns the worst status of the results of the run
+func controlRunStatus(run *ControlRun) string {
+	summary := run.GetStatusSummary()
+	if summary == nil {
+		return worstStatus(controlstatus.StatusSummary{})
+	}
+	return worstStatus(*summary)
+}
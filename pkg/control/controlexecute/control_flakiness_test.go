@@ -0,0 +1,62 @@
+package controlexecute
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/control/controlstatus"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// build a result tree for each run, using the given summary of each control - controls with no summary are not run
+func newTestFlakinessHistory(runs []map[string]controlstatus.StatusSummary) []*ResultGroup {
+	var history []*ResultGroup
+	for _, summaries := range runs {
+		var controls []*modconfig.Control
+		for _, name := range []string{"c1", "c2", "c3"} {
+			if _, ok := summaries[name]; ok {
+				controls = append(controls, newTestControl(name))
+			}
+		}
+		tree := newTestExecutionTree(controls...)
+		for _, run := range tree.ControlRuns {
+			summary := summaries[run.Control.ShortName]
+			run.Summary = &summary
+		}
+		history = append(history, tree.Root)
+	}
+	return history
+}
+
+func TestControlFlakinessReport(t *testing.T) {
+	history := newTestFlakinessHistory([]map[string]controlstatus.StatusSummary{
+		{"c1": {Ok: 1}, "c2": {Ok: 2}, "c3": {Alarm: 1}},
+		{"c1": {Alarm: 1, Ok: 1}, "c2": {Ok: 2}},
+		{"c1": {Ok: 2}, "c2": {Ok: 1, Error: 1}, "c3": {Alarm: 2}},
+		{"c1": {Alarm: 1}, "c2": {Ok: 2}, "c3": {Alarm: 1}},
+	})
+
+	expected := []ControlFlakiness{
+		{
+			ControlId:     "test.control.c1",
+			Statuses:      []string{"ok", "alarm", "ok", "alarm"},
+			StatusChanges: 3,
+			Volatility:    1,
+		},
+		{
+			ControlId:     "test.control.c2",
+			Statuses:      []string{"ok", "ok", "error", "ok"},
+			StatusChanges: 2,
+			Volatility:    2.0 / 3.0,
+		},
+		{
+			ControlId:  "test.control.c3",
+			Statuses:   []string{"alarm", "alarm", "alarm"},
+			Volatility: 0,
+		},
+	}
+
+	if report := ControlFlakinessReport(history); !reflect.DeepEqual(report, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, report)
+	}
+}
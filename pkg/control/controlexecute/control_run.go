@@ -2,8 +2,11 @@ package controlexecute
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,6 +23,9 @@ import (
 	"github.com/turbot/steampipe/pkg/utils"
 )
 
+// matches a leading WITH clause, capturing the optional RECURSIVE modifier
+var withClauseRegex = regexp.MustCompile(`(?i)^\s*with\s+(recursive\s+)?`)
+
 // ControlRun is a struct representing the execution of a control run. It will contain one or more result items (i.e. for one or more resources).
 type ControlRun struct {
 	// properties from control
@@ -31,9 +37,25 @@ type ControlRun struct {
 	Tags          map[string]string `json:"tags,omitempty"`
 	Display       string            `json:"display,omitempty"`
 	Type          string            `json:"display_type,omitempty"`
+	// the file and line the control is defined in
+	FileName  string `json:"file_name,omitempty"`
+	StartLine int    `json:"start_line,omitempty"`
 
 	// this will be serialised under 'properties'
 	Severity string `json:"-"`
+	// the credential hints of the control (serialised under 'properties')
+	// these may be used by the execution layer to run the control query under the appropriate credentials
+	Profile string `json:"-"`
+	RoleArn string `json:"-"`
+	// the page size hint of the control (serialised under 'properties')
+	// this may be used by the execution layer to size the pages of paginated queries - zero means the default
+	BatchSize int `json:"-"`
+	// the output column which identifies a result row across runs (serialised under 'properties')
+	// this is stored on the run so it is available for a baseline restored from its binary form, which has no control
+	PrimaryKey string `json:"-"`
+	// whether the control is deprecated, and the deprecation message, if one was provided (serialised under 'properties')
+	Deprecated         bool   `json:"-"`
+	DeprecationMessage string `json:"-"`
 
 	// "control"
 	NodeType string `json:"panel_type"`
@@ -67,6 +89,8 @@ type ControlRun struct {
 	stateLock   sync.Mutex
 	doneChan    chan bool
 	attempts    int
+	// the maximum duration of the run, if a control timeout is configured
+	timeout time.Duration
 }
 
 func NewControlRun(control *modconfig.Control, group *ResultGroup, executionTree *ExecutionTree) *ControlRun {
@@ -86,17 +110,26 @@ func NewControlRun(control *modconfig.Control, group *ResultGroup, executionTree
 		Tags:          control.GetTags(),
 		Display:       control.GetDisplay(),
 		Type:          control.GetType(),
-
-		Severity:  typehelpers.SafeString(control.Severity),
-		Title:     typehelpers.SafeString(control.Title),
-		rowMap:    make(map[string]ResultRows),
-		Summary:   &controlstatus.StatusSummary{},
-		Tree:      executionTree,
-		RunStatus: dashboardtypes.RunInitialized,
+		FileName:      control.DeclRange.Filename,
+		StartLine:     control.DeclRange.Start.Line,
+
+		Severity:   typehelpers.SafeString(control.Severity),
+		Profile:    typehelpers.SafeString(control.Profile),
+		RoleArn:    typehelpers.SafeString(control.RoleArn),
+		BatchSize:  typehelpers.IntValue(control.BatchSize),
+		PrimaryKey: typehelpers.SafeString(control.PrimaryKey),
+		Title:      typehelpers.SafeString(control.Title),
+		rowMap:     make(map[string]ResultRows),
+		Summary:    &controlstatus.StatusSummary{},
+		Tree:       executionTree,
+		RunStatus:  dashboardtypes.RunInitialized,
 
 		Group:    group,
 		NodeType: modconfig.BlockTypeControl,
 		doneChan: make(chan bool, 1),
+
+		Deprecated:         control.IsDeprecated(),
+		DeprecationMessage: control.GetDeprecationMessage(),
 	}
 	return res
 }
@@ -161,8 +194,8 @@ func (r *ControlRun) setError(ctx context.Context, err error) {
 	if err == nil {
 		return
 	}
-	if r.runError == context.DeadlineExceeded {
-		r.runError = fmt.Errorf("control execution timed out")
+	if errors.Is(err, context.DeadlineExceeded) {
+		r.runError = r.timeoutError()
 	} else {
 		r.runError = error_helpers.TransformErrorToSteampipe(err)
 	}
@@ -176,10 +209,69 @@ func (r *ControlRun) setError(ctx context.Context, err error) {
 	}
 }
 
+func (r *ControlRun) timeoutError() error {
+	if r.timeout > 0 {
+		return fmt.Errorf("control exceeded timeout of %s", r.timeout)
+	}
+	return fmt.Errorf("control execution timed out")
+}
+
+// setQueryError handles a failure of the control query according to the on_error policy of the control
+// for a policy of skip or alarm, a single result row with that status is added, using the error as the reason
+func (r *ControlRun) setQueryError(ctx context.Context, err error) {
+	status := r.Control.GetOnError()
+	// a timeout is always an error, regardless of the on_error policy
+	if status == constants.ControlError || error_helpers.IsContextCancelledError(err) || errors.Is(err, context.DeadlineExceeded) {
+		r.setError(ctx, err)
+		return
+	}
+
+	log.Printf("[TRACE] control %s query failed - setting status '%s' as per on_error: %s", r.Control.Name(), status, err)
+	row := &ResultRow{
+		Reason:  error_helpers.TransformErrorToSteampipe(err).Error(),
+		Status:  status,
+		Run:     r,
+		Control: r.Control,
+	}
+	r.addResultRow(row)
+	r.createdOrderedResultRows()
+	r.Data = r.Rows.ToLeafData(r.getDimensionSchema())
+	r.setRunStatus(ctx, dashboardtypes.RunComplete)
+}
+
 func (r *ControlRun) skip(ctx context.Context) {
 	r.setRunStatus(ctx, dashboardtypes.RunComplete)
 }
 
+// dependencyRuns returns the runs of the controls this control depends on (see depends_on)
+// dependencies which are not part of the execution tree are ignored
+func (r *ControlRun) dependencyRuns() []*ControlRun {
+	if r.Control == nil || r.Tree == nil || len(r.Control.DependsOn) == 0 {
+		return nil
+	}
+	var res []*ControlRun
+	for _, dependency := range r.Control.DependsOn {
+		for _, run := range r.Tree.ControlRuns {
+			if run != r && run.FullName == dependency.Name {
+				res = append(res, run)
+			}
+		}
+	}
+	return res
+}
+
+// waitForRuns waits until all the given runs have finished, or the context is cancelled
+func waitForRuns(ctx context.Context, runs []*ControlRun) error {
+	for _, run := range runs {
+		select {
+		case <-run.doneChan:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
 func (r *ControlRun) execute(ctx context.Context, client db_common.Client) {
 	utils.LogTime("ControlRun.execute start")
 	defer utils.LogTime("ControlRun.execute end")
@@ -195,13 +287,8 @@ func (r *ControlRun) execute(ctx context.Context, client db_common.Client) {
 	defer func() {
 		// update the result group status with our status - this will be passed all the way up the execution tree
 		r.Group.updateSummary(r.Summary)
-		if len(r.Severity) != 0 {
-			r.Group.updateSeverityCounts(r.Severity, r.Summary)
-		}
+		r.updateSeverityCounts()
 		r.Duration = time.Since(startTime)
-		if r.Group != nil {
-			r.Group.onChildDone()
-		}
 		log.Printf("[TRACE] finishing with concurrency, %s, , %d\n", r.Control.Name(), r.Tree.Progress.Executing)
 	}()
 
@@ -210,7 +297,7 @@ func (r *ControlRun) execute(ctx context.Context, client db_common.Client) {
 	if sessionResult.Error != nil {
 		if !error_helpers.IsCancelledError(sessionResult.Error) {
 			log.Printf("[TRACE] controlRun %s execute failed to acquire session: %s", r.ControlId, sessionResult.Error)
-			sessionResult.Error = fmt.Errorf("error acquiring database connection, %s", sessionResult.Error.Error())
+			sessionResult.Error = fmt.Errorf("error acquiring database connection, %w", sessionResult.Error)
 			r.setError(ctx, sessionResult.Error)
 		}
 		return
@@ -245,10 +332,26 @@ func (r *ControlRun) execute(ctx context.Context, client db_common.Client) {
 
 	controlExecutionCtx := r.getControlQueryContext(ctx)
 
+	// if the control sql contains multiple statements, execute all but the last as setup statements
+	controlSQL, err := r.executeSetupStatements(controlExecutionCtx, client, dbSession, resolvedQuery.ExecuteSQL)
+	if err != nil {
+		r.setError(ctx, err)
+		return
+	}
+	// if the mod defines a sql prelude, prepend it to the control query
+	// NOTE: this is done at execution time so the prelude also applies to controls which reference a named query
+	if control.Mod != nil && control.Mod.SqlPrelude != nil {
+		controlSQL, err = applySqlPrelude(*control.Mod.SqlPrelude, controlSQL)
+		if err != nil {
+			r.setError(ctx, fmt.Errorf("cannot run %s - %s", control.Name(), err.Error()))
+			return
+		}
+	}
+
 	// execute the control query
 	// NOTE no need to pass an OnComplete callback - we are already closing our session after waiting for results
 	log.Printf("[TRACE] execute start for, %s\n", control.Name())
-	queryResult, err := client.ExecuteInSession(controlExecutionCtx, dbSession, nil, resolvedQuery.ExecuteSQL, resolvedQuery.Args...)
+	queryResult, err := r.executeQuery(controlExecutionCtx, client, dbSession, controlSQL, resolvedQuery.Args)
 	log.Printf("[TRACE] execute finish for, %s\n", control.Name())
 
 	if err != nil {
@@ -265,7 +368,7 @@ func (r *ControlRun) execute(ctx context.Context, client db_common.Client) {
 				log.Printf("[TRACE] control %s query failed again with plugin connectivity error %s - NOT retrying…", r.Control.Name(), err)
 			}
 		}
-		r.setError(ctx, err)
+		r.setQueryError(ctx, err)
 		return
 	}
 
@@ -277,6 +380,121 @@ func (r *ControlRun) execute(ctx context.Context, client db_common.Client) {
 	log.Printf("[TRACE] finish result for, %s\n", control.Name())
 }
 
+// executeQuery executes the control query, retrying (up to the configured number of retries) if the query fails with a
+// transient error - the delay between retries increases exponentially
+// if the context is cancelled while waiting to retry, the context error is returned
+func (r *ControlRun) executeQuery(ctx context.Context, client db_common.Client, session *db_common.DatabaseSession, sql string, args []any) (*queryresult.Result, error) {
+	maxRetries := controlRetries()
+	for retry := 0; ; retry++ {
+		queryResult, err := client.ExecuteInSession(ctx, session, nil, sql, args...)
+		if err == nil || retry >= maxRetries || !db_common.IsTransientError(err) {
+			return queryResult, err
+		}
+
+		backoff := controlRetryBackoff(retry)
+		log.Printf("[TRACE] control %s query failed with transient error %s - retrying in %s (retry %d of %d)", r.Control.Name(), err, backoff, retry+1, maxRetries)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// controlRetryBackoff returns the delay before the given retry (zero based) of a control query
+func controlRetryBackoff(retry int) time.Duration {
+	backoff := constants.ControlRetryBaseBackoff
+	for i := 0; i < retry && backoff < constants.ControlRetryMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > constants.ControlRetryMaxBackoff {
+		backoff = constants.ControlRetryMaxBackoff
+	}
+	return backoff
+}
+
+// split the control sql into statements and execute all but the last, returning the final statement,
+// which produces the control result rows
+// NOTE: query args are only passed to the final statement
+func (r *ControlRun) executeSetupStatements(ctx context.Context, client db_common.Client, session *db_common.DatabaseSession, sql string) (string, error) {
+	statements, err := utils.SplitSqlStatements(sql)
+	if err != nil {
+		return "", fmt.Errorf("cannot run %s - failed to parse sql: %s", r.Control.Name(), err.Error())
+	}
+	if len(statements) <= 1 {
+		return sql, nil
+	}
+
+	setupStatements := statements[:len(statements)-1]
+	for _, statement := range setupStatements {
+		log.Printf("[TRACE] execute setup statement for %s: %s\n", r.Control.Name(), statement)
+		if _, err := client.ExecuteSyncInSession(ctx, session, statement); err != nil {
+			return "", fmt.Errorf("cannot run %s - setup statement failed: %s", r.Control.Name(), err.Error())
+		}
+	}
+	return statements[len(statements)-1], nil
+}
+
+// applySqlPrelude prepends the prelude to the given sql
+// the prelude must be a single WITH clause defining common table expressions - if the sql also starts with a WITH clause,
+// the common table expressions are combined into a single clause
+// NOTE: leading comments are ignored when determining whether the prelude and sql start with a WITH clause
+func applySqlPrelude(prelude, sql string) (string, error) {
+	prelude = stripLeadingSqlComments(prelude)
+	if prelude == "" {
+		return sql, nil
+	}
+	statements, err := utils.SplitSqlStatements(prelude)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse sql_prelude: %s", err.Error())
+	}
+	preludeWith := withClauseRegex.FindString(prelude)
+	if len(statements) != 1 || preludeWith == "" {
+		return "", fmt.Errorf("sql_prelude must be a single WITH clause defining common table expressions")
+	}
+	// remove any trailing semicolon
+	prelude = statements[0]
+
+	strippedSql := stripLeadingSqlComments(sql)
+	sqlWith := withClauseRegex.FindStringSubmatch(strippedSql)
+	if sqlWith == nil {
+		return fmt.Sprintf("%s\n%s", prelude, sql), nil
+	}
+
+	// NOTE: RECURSIVE must immediately follow the first WITH, so use the modifier of the sql if present
+	prefix := preludeWith
+	if sqlWith[1] != "" {
+		prefix = sqlWith[0]
+	}
+	preludeCTEs := strings.TrimPrefix(prelude, preludeWith)
+	sqlCTEs := strings.TrimPrefix(strippedSql, sqlWith[0])
+	return fmt.Sprintf("%s%s,\n%s", prefix, preludeCTEs, sqlCTEs), nil
+}
+
+// stripLeadingSqlComments returns the sql with any leading whitespace, line comments and block comments removed
+func stripLeadingSqlComments(sql string) string {
+	for {
+		sql = strings.TrimSpace(sql)
+		switch {
+		case strings.HasPrefix(sql, "--"):
+			idx := strings.IndexByte(sql, '\n')
+			if idx == -1 {
+				return ""
+			}
+			sql = sql[idx+1:]
+		case strings.HasPrefix(sql, "/*"):
+			idx := strings.Index(sql, "*/")
+			if idx == -1 {
+				// an unterminated comment - leave it for the database to report
+				return sql
+			}
+			sql = sql[idx+2:]
+		default:
+			return sql
+		}
+	}
+}
+
 // try to acquire a database session - retry up to 4 times if there is an error
 func (r *ControlRun) acquireSession(ctx context.Context, client db_common.Client) *db_common.AcquireSessionResult {
 	var sessionResult *db_common.AcquireSessionResult
@@ -315,6 +533,9 @@ func (r *ControlRun) waitForResults(ctx context.Context) {
 		r.Data = r.Rows.ToLeafData(dimensionsSchema)
 	}()
 
+	r.warnUnknownColumnTypeHints(r.queryResult.Cols)
+	r.warnUnknownPrimaryKey(r.queryResult.Cols)
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -331,7 +552,7 @@ func (r *ControlRun) waitForResults(ctx context.Context) {
 			// if the row is in error then we terminate the run
 			if row.Error != nil {
 				// set error status (parent summary will be set from parent defer)
-				r.setError(ctx, row.Error)
+				r.setQueryError(ctx, row.Error)
 				return
 			}
 
@@ -375,18 +596,54 @@ func (r *ControlRun) addResultRow(row *ResultRow) {
 	r.rowMap[row.Status] = append(r.rowMap[row.Status], row)
 
 	// update summary
-	switch row.Status {
+	addStatusToSummary(r.Summary, row.Status)
+}
+
+func addStatusToSummary(summary *controlstatus.StatusSummary, status string) {
+	switch status {
 	case constants.ControlOk:
-		r.Summary.Ok++
+		summary.Ok++
 	case constants.ControlAlarm:
-		r.Summary.Alarm++
+		summary.Alarm++
 	case constants.ControlSkip:
-		r.Summary.Skip++
+		summary.Skip++
 	case constants.ControlInfo:
-		r.Summary.Info++
+		summary.Info++
 	case constants.ControlError:
-		r.Summary.Error++
+		summary.Error++
+	}
+}
+
+// updateSeverityCounts updates the severity counts of the parent group with the results of this run
+// if the control severity depends on row data, each row is counted against its own severity
+func (r *ControlRun) updateSeverityCounts() {
+	if r.Control == nil || !r.Control.HasRowSeverity() {
+		if len(r.Severity) != 0 {
+			r.Group.updateSeverityCounts(r.Severity, r.Summary)
+		}
+		return
+	}
+
+	for severity, summary := range r.rowSeveritySummaries() {
+		r.Group.updateSeverityCounts(severity, summary)
+	}
+}
+
+// rowSeveritySummaries returns a status summary for each row severity, keyed by severity
+func (r *ControlRun) rowSeveritySummaries() map[string]*controlstatus.StatusSummary {
+	res := make(map[string]*controlstatus.StatusSummary)
+	for _, row := range r.Rows {
+		if len(row.Severity) == 0 {
+			continue
+		}
+		summary, ok := res[row.Severity]
+		if !ok {
+			summary = &controlstatus.StatusSummary{}
+			res[row.Severity] = summary
+		}
+		addStatusToSummary(summary, row.Status)
 	}
+	return res
 }
 
 // populate ordered list of rows
@@ -0,0 +1,338 @@
+package controlexecute
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/dashboard/dashboardtypes"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+type onErrorTest struct {
+	onError           *string
+	expectedRunStatus dashboardtypes.RunStatus
+	// expected status of the single result row - empty if no row is expected
+	expectedRowStatus string
+	expectedErrors    int
+}
+
+var testCasesOnError = map[string]onErrorTest{
+	"default": {
+		expectedRunStatus: dashboardtypes.RunError,
+		expectedErrors:    1,
+	},
+	"error": {
+		onError:           utils.ToStringPointer(constants.ControlError),
+		expectedRunStatus: dashboardtypes.RunError,
+		expectedErrors:    1,
+	},
+	"skip": {
+		onError:           utils.ToStringPointer(constants.ControlSkip),
+		expectedRunStatus: dashboardtypes.RunComplete,
+		expectedRowStatus: constants.ControlSkip,
+	},
+	"alarm": {
+		onError:           utils.ToStringPointer(constants.ControlAlarm),
+		expectedRunStatus: dashboardtypes.RunComplete,
+		expectedRowStatus: constants.ControlAlarm,
+	},
+}
+
+func TestControlRunOnError(t *testing.T) {
+	queryErr := fmt.Errorf("relation \"aws_s3_bucket\" does not exist")
+
+	for name, test := range testCasesOnError {
+		control := newTestControl("c1")
+		control.OnError = test.onError
+		tree := newTestExecutionTree(control)
+		run := tree.ControlRuns[0]
+
+		run.setQueryError(context.Background(), queryErr)
+
+		if status := run.GetRunStatus(); status != test.expectedRunStatus {
+			t.Errorf("Test %s FAILED. Expected run status %v, got %v", name, test.expectedRunStatus, status)
+		}
+		if run.Summary.Error != test.expectedErrors {
+			t.Errorf("Test %s FAILED. Expected %d errors, got %d", name, test.expectedErrors, run.Summary.Error)
+		}
+		if test.expectedRowStatus == "" {
+			if len(run.Rows) != 0 {
+				t.Errorf("Test %s FAILED. Expected no rows, got %d", name, len(run.Rows))
+			}
+			continue
+		}
+		if len(run.Rows) != 1 {
+			t.Errorf("Test %s FAILED. Expected 1 row, got %d", name, len(run.Rows))
+			continue
+		}
+		if run.Rows[0].Status != test.expectedRowStatus {
+			t.Errorf("Test %s FAILED. Expected row status %s, got %s", name, test.expectedRowStatus, run.Rows[0].Status)
+		}
+		if run.Rows[0].Reason == "" {
+			t.Errorf("Test %s FAILED. Expected row reason to be set from the query error", name)
+		}
+	}
+}
+
+type sqlPreludeTest struct {
+	prelude       string
+	sql           string
+	expected      string
+	expectedError string
+}
+
+var testCasesSqlPrelude = map[string]sqlPreludeTest{
+	"no prelude": {
+		sql:      "select 1",
+		expected: "select 1",
+	},
+	"comment only prelude": {
+		prelude:  "-- common prelude",
+		sql:      "select 1",
+		expected: "select 1",
+	},
+	"cte prelude": {
+		prelude:  "with accounts as (select * from aws_account)",
+		sql:      "select * from accounts",
+		expected: "with accounts as (select * from aws_account)\nselect * from accounts",
+	},
+	"cte prelude with trailing semicolon": {
+		prelude:  "with accounts as (select * from aws_account);",
+		sql:      "select * from accounts",
+		expected: "with accounts as (select * from aws_account)\nselect * from accounts",
+	},
+	"cte prelude and cte sql": {
+		prelude:  "with accounts as (select * from aws_account)",
+		sql:      "WITH buckets as (select * from aws_s3_bucket) select * from buckets, accounts",
+		expected: "with accounts as (select * from aws_account),\nbuckets as (select * from aws_s3_bucket) select * from buckets, accounts",
+	},
+	"cte prelude and recursive sql": {
+		prelude:  "with accounts as (select * from aws_account)",
+		sql:      "with recursive t(n) as (select 1 union select n+1 from t where n < 3) select * from t, accounts",
+		expected: "with recursive accounts as (select * from aws_account),\nt(n) as (select 1 union select n+1 from t where n < 3) select * from t, accounts",
+	},
+	"comments before with": {
+		prelude:  "-- shared ctes\n/* accounts */ with accounts as (select * from aws_account)",
+		sql:      "-- check buckets\nwith buckets as (select * from aws_s3_bucket) select * from buckets, accounts",
+		expected: "with accounts as (select * from aws_account),\nbuckets as (select * from aws_s3_bucket) select * from buckets, accounts",
+	},
+	"non cte prelude": {
+		prelude:       "set search_path = aws",
+		sql:           "select 1",
+		expectedError: "sql_prelude must be a single WITH clause",
+	},
+	"multiple statement prelude": {
+		prelude:       "with accounts as (select * from aws_account); select 1",
+		sql:           "select 1",
+		expectedError: "sql_prelude must be a single WITH clause",
+	},
+}
+
+func TestApplySqlPrelude(t *testing.T) {
+	for name, test := range testCasesSqlPrelude {
+		sql, err := applySqlPrelude(test.prelude, test.sql)
+		if test.expectedError != "" {
+			if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+				t.Errorf("Test %s FAILED. Expected error containing '%s', got %v", name, test.expectedError, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, err)
+			continue
+		}
+		if sql != test.expected {
+			t.Errorf("Test %s FAILED. Expected %q, got %q", name, test.expected, sql)
+		}
+	}
+}
+
+func TestControlRunSourceLocation(t *testing.T) {
+	control := newTestControl("c1")
+	control.DeclRange = hcl.Range{
+		Filename: "/mods/aws/cis.sp",
+		Start:    hcl.Pos{Line: 12, Column: 1},
+		End:      hcl.Pos{Line: 20, Column: 2},
+	}
+	tree := newTestExecutionTree(control)
+	run := tree.ControlRuns[0]
+
+	if run.FileName != "/mods/aws/cis.sp" || run.StartLine != 12 {
+		t.Errorf("Expected source location /mods/aws/cis.sp:12, got %s:%d", run.FileName, run.StartLine)
+	}
+
+	data, err := json.Marshal(run)
+	if err != nil {
+		t.Fatalf("failed to marshal control run: %v", err)
+	}
+	var res map[string]any
+	if err := json.Unmarshal(data, &res); err != nil {
+		t.Fatalf("failed to unmarshal control run: %v", err)
+	}
+	if res["file_name"] != "/mods/aws/cis.sp" || res["start_line"] != float64(12) {
+		t.Errorf("Expected source location in JSON, got file_name %v, start_line %v", res["file_name"], res["start_line"])
+	}
+}
+
+type controlRunDeprecatedTest struct {
+	deprecated         *string
+	expectedDeprecated bool
+	expectedMessage    string
+}
+
+var testCasesControlRunDeprecated = map[string]controlRunDeprecatedTest{
+	"not deprecated": {},
+	"deprecated false": {
+		deprecated: utils.ToStringPointer("false"),
+	},
+	"deprecated true": {
+		deprecated:         utils.ToStringPointer("true"),
+		expectedDeprecated: true,
+	},
+	"deprecated with message": {
+		deprecated:         utils.ToStringPointer("use control c2"),
+		expectedDeprecated: true,
+		expectedMessage:    "use control c2",
+	},
+}
+
+func TestControlRunDeprecated(t *testing.T) {
+	for name, test := range testCasesControlRunDeprecated {
+		control := newTestControl("c1")
+		control.Deprecated = test.deprecated
+		tree := newTestExecutionTree(control)
+		run := tree.ControlRuns[0]
+
+		if run.Deprecated != test.expectedDeprecated || run.DeprecationMessage != test.expectedMessage {
+			t.Errorf("Test %s FAILED. Expected deprecated %v with message '%s', got %v with message '%s'", name, test.expectedDeprecated, test.expectedMessage, run.Deprecated, run.DeprecationMessage)
+		}
+	}
+}
+
+// failingClient is a database client whose queries fail with the given error until it has been called failures times
+type failingClient struct {
+	db_common.Client
+	err      error
+	failures int
+	calls    int
+}
+
+func (c *failingClient) ExecuteInSession(context.Context, *db_common.DatabaseSession, func(), string, ...any) (*queryresult.Result, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return nil, c.err
+	}
+	return queryresult.NewResult(nil), nil
+}
+
+type executeQueryRetryTest struct {
+	retries       int
+	err           error
+	failures      int
+	cancelled     bool
+	expectedCalls int
+	expectedError bool
+}
+
+var testCasesExecuteQueryRetry = map[string]executeQueryRetryTest{
+	"no retries by default": {
+		err:           &pgconn.PgError{Code: "40001"},
+		failures:      1,
+		expectedCalls: 1,
+		expectedError: true,
+	},
+	"transient error retried": {
+		retries:       1,
+		err:           &pgconn.PgError{Code: "40P01"},
+		failures:      1,
+		expectedCalls: 2,
+	},
+	"plugin rate limit error retried": {
+		retries:       1,
+		err:           &pgconn.PgError{Code: "HV000", Message: "operation error EC2: DescribeInstances, api error RequestLimitExceeded: Request limit exceeded. Throttling"},
+		failures:      1,
+		expectedCalls: 2,
+	},
+	"plugin error not retried": {
+		retries:       1,
+		err:           &pgconn.PgError{Code: "HV000", Message: "operation error EC2: DescribeInstances, api error UnauthorizedOperation"},
+		failures:      1,
+		expectedCalls: 1,
+		expectedError: true,
+	},
+	"connection error not retried": {
+		retries:       1,
+		err:           &pgconn.PgError{Code: "08006"},
+		failures:      1,
+		expectedCalls: 1,
+		expectedError: true,
+	},
+	"retries exhausted": {
+		retries:       1,
+		err:           &pgconn.PgError{Code: "40001"},
+		failures:      2,
+		expectedCalls: 2,
+		expectedError: true,
+	},
+	"non transient error not retried": {
+		retries:       3,
+		err:           &pgconn.PgError{Code: "42P01"},
+		failures:      1,
+		expectedCalls: 1,
+		expectedError: true,
+	},
+	"cancelled during backoff": {
+		retries:       3,
+		err:           &pgconn.PgError{Code: "40001"},
+		failures:      1,
+		cancelled:     true,
+		expectedCalls: 1,
+		expectedError: true,
+	},
+}
+
+func TestControlRunExecuteQueryRetry(t *testing.T) {
+	defer viper.Set(constants.ArgControlRetries, nil)
+
+	for name, test := range testCasesExecuteQueryRetry {
+		viper.Set(constants.ArgControlRetries, test.retries)
+		run := newTestExecutionTree(newTestControl("c1")).ControlRuns[0]
+		client := &failingClient{err: test.err, failures: test.failures}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		if test.cancelled {
+			cancel()
+		}
+		_, err := run.executeQuery(ctx, client, nil, "select 1", nil)
+		cancel()
+
+		if client.calls != test.expectedCalls {
+			t.Errorf("Test %s FAILED. Expected %d calls, got %d", name, test.expectedCalls, client.calls)
+		}
+		if (err != nil) != test.expectedError {
+			t.Errorf("Test %s FAILED. Expected error: %v, got %v", name, test.expectedError, err)
+		}
+	}
+}
+
+func TestControlRetryBackoff(t *testing.T) {
+	expected := []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second}
+	for retry, e := range expected {
+		if backoff := controlRetryBackoff(retry); backoff != e {
+			t.Errorf("Test TestControlRetryBackoff FAILED. Expected backoff %s for retry %d, got %s", e, retry, backoff)
+		}
+	}
+	if backoff := controlRetryBackoff(20); backoff != constants.ControlRetryMaxBackoff {
+		t.Errorf("Test TestControlRetryBackoff FAILED. Expected backoff to be capped at %s, got %s", constants.ControlRetryMaxBackoff, backoff)
+	}
+}
@@ -0,0 +1,70 @@
+package controlexecute
+
+import (
+	"sort"
+
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"golang.org/x/exp/maps"
+)
+
+// CoverageResult describes which sections of a compliance framework are covered by controls
+type CoverageResult struct {
+	// map of covered section to the names of the controls which cover it
+	Covered   map[string][]string `json:"covered"`
+	Uncovered []string            `json:"uncovered"`
+	Total     int                 `json:"total"`
+}
+
+// CoveredCount returns the number of framework sections covered by at least one control
+func (r CoverageResult) CoveredCount() int {
+	return len(r.Covered)
+}
+
+// CoveragePercent returns the percentage of framework sections covered by at least one control
+func (r CoverageResult) CoveragePercent() float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(len(r.Covered)) * 100 / float64(r.Total)
+}
+
+// CoverageReport determines which of the given framework sections are covered by controls in the mod.
+// A control covers a section if its tag with key frameworkTagKey has the section as its value
+// Each section and each control is counted once, even if listed more than once
+func CoverageReport(mod *modconfig.Mod, frameworkTagKey string, allSections []string) CoverageResult {
+	res := CoverageResult{
+		Covered: make(map[string][]string),
+	}
+
+	// build map of section to the names of the controls which reference it
+	sectionControls := make(map[string]map[string]struct{})
+	if mod != nil && mod.ResourceMaps != nil {
+		for _, control := range mod.ResourceMaps.Controls {
+			if section, ok := control.Tags[frameworkTagKey]; ok {
+				if sectionControls[section] == nil {
+					sectionControls[section] = make(map[string]struct{})
+				}
+				sectionControls[section][control.Name()] = struct{}{}
+			}
+		}
+	}
+
+	seenSections := make(map[string]struct{}, len(allSections))
+	for _, section := range allSections {
+		if _, seen := seenSections[section]; seen {
+			continue
+		}
+		seenSections[section] = struct{}{}
+		res.Total++
+
+		controls, ok := sectionControls[section]
+		if !ok {
+			res.Uncovered = append(res.Uncovered, section)
+			continue
+		}
+		// sort the control names to give a stable result
+		res.Covered[section] = maps.Keys(controls)
+		sort.Strings(res.Covered[section])
+	}
+	return res
+}
@@ -0,0 +1,47 @@
+package controlexecute
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+func TestCoverageReport(t *testing.T) {
+	mod := modconfig.NewMod("test", "", hcl.Range{})
+	controls := map[string]map[string]string{
+		"c1": {"cis_section": "1.1"},
+		"c2": {"cis_section": "1.1"},
+		"c3": {"cis_section": "2.1"},
+		"c4": {"service": "s3"},
+	}
+	for name, tags := range controls {
+		control := newTestControl(name)
+		control.Tags = tags
+		mod.ResourceMaps.Controls[control.Name()] = control
+	}
+	// a control reachable by more than one key must only be counted once
+	mod.ResourceMaps.Controls["alias.control.c3"] = mod.ResourceMaps.Controls["test.control.c3"]
+
+	// a section listed more than once must only be counted once
+	res := CoverageReport(mod, "cis_section", []string{"1.1", "1.2", "2.1", "3.1", "2.1"})
+
+	expectedCovered := map[string][]string{
+		"1.1": {"test.control.c1", "test.control.c2"},
+		"2.1": {"test.control.c3"},
+	}
+	if !reflect.DeepEqual(res.Covered, expectedCovered) {
+		t.Errorf("Test TestCoverageReport FAILED. Expected covered %v, got %v", expectedCovered, res.Covered)
+	}
+	expectedUncovered := []string{"1.2", "3.1"}
+	if !reflect.DeepEqual(res.Uncovered, expectedUncovered) {
+		t.Errorf("Test TestCoverageReport FAILED. Expected uncovered %v, got %v", expectedUncovered, res.Uncovered)
+	}
+	if res.Total != 4 {
+		t.Errorf("Test TestCoverageReport FAILED. Expected total 4, got %d", res.Total)
+	}
+	if percent := res.CoveragePercent(); percent != 50 {
+		t.Errorf("Test TestCoverageReport FAILED. Expected coverage 50%%, got %v%%", percent)
+	}
+}
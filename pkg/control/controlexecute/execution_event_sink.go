@@ -0,0 +1,17 @@
+package controlexecute
+
+import "context"
+
+// ExecutionEventSink receives notifications as the controls of an ExecutionTree execute
+// NOTE: controls execute in parallel, however the ExecutionTree serializes the calls to the sink,
+// so implementations need not be safe for concurrent use - they should however return promptly,
+// as a slow sink blocks the completion of other control runs
+type ExecutionEventSink interface {
+	// OnControlStart is called when a control run begins executing
+	OnControlStart(ctx context.Context, run *ControlRun)
+	// OnControlComplete is called when a control run which was started has finished - successfully or otherwise
+	OnControlComplete(ctx context.Context, run *ControlRun)
+	// OnGroupComplete is called when all the control runs and child groups of a result group have completed
+	// it is called after OnControlComplete for the final control run of the group, and before OnGroupComplete for the parent group
+	OnGroupComplete(ctx context.Context, group *ResultGroup)
+}
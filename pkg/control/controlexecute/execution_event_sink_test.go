@@ -0,0 +1,82 @@
+package controlexecute
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"golang.org/x/sync/semaphore"
+)
+
+// an ExecutionEventSink which records the events it receives
+type recordingEventSink struct {
+	events []string
+}
+
+func (s *recordingEventSink) OnControlStart(_ context.Context, run *ControlRun) {
+	s.events = append(s.events, "start "+run.Control.Name())
+}
+
+func (s *recordingEventSink) OnControlComplete(_ context.Context, run *ControlRun) {
+	s.events = append(s.events, "complete "+run.Control.Name())
+}
+
+func (s *recordingEventSink) OnGroupComplete(_ context.Context, group *ResultGroup) {
+	s.events = append(s.events, "group complete "+group.GroupId)
+}
+
+func TestExecutionEventSink(t *testing.T) {
+	tree := newTestExecutionTree(newTestControl("c1"), newTestControl("c2"))
+	sink := &recordingEventSink{}
+	tree.EventSink = sink
+
+	parallelismLock := semaphore.NewWeighted(1)
+	for _, run := range tree.ControlRuns {
+		if err := parallelismLock.Acquire(context.Background(), 1); err != nil {
+			t.Fatal(err)
+		}
+		// a nil client causes the run to fail - the events are still sent
+		executeRun(context.Background(), run, parallelismLock, nil)
+	}
+
+	expected := []string{
+		"start test.control.c1",
+		"complete test.control.c1",
+		"start test.control.c2",
+		"complete test.control.c2",
+		"group complete test.benchmark.b1",
+		"group complete " + RootResultGroupName,
+	}
+	if !reflect.DeepEqual(sink.events, expected) {
+		t.Errorf("Test TestExecutionEventSink FAILED. Expected events %v, got %v", expected, sink.events)
+	}
+}
+
+func TestExecuteWaitsForDependencies(t *testing.T) {
+	// c1 is started first, but depends on c2
+	c1 := newTestControl("c1")
+	c1.DependsOn = modconfig.NamedItemList{{Name: "test.control.c2"}}
+	tree := newTestExecutionTree(c1, newTestControl("c2"))
+	sink := &recordingEventSink{}
+	tree.EventSink = sink
+
+	parallelismLock := semaphore.NewWeighted(1)
+	// a nil client causes the runs to fail - the events are still sent
+	tree.Root.execute(context.Background(), nil, parallelismLock)
+	if err := tree.waitForActiveRunsToComplete(context.Background(), parallelismLock, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"start test.control.c2",
+		"complete test.control.c2",
+		"start test.control.c1",
+		"complete test.control.c1",
+		"group complete test.benchmark.b1",
+		"group complete " + RootResultGroupName,
+	}
+	if !reflect.DeepEqual(sink.events, expected) {
+		t.Errorf("Test TestExecuteWaitsForDependencies FAILED. Expected events %v, got %v", expected, sink.events)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/spf13/viper"
@@ -38,9 +39,33 @@ type ExecutionTree struct {
 	client     db_common.Client
 	// an optional map of control names used to filter the controls which are run
 	controlNameFilterMap map[string]bool
+	// an optional predicate used to filter the controls which are run
+	controlFilter ControlFilter
+	// optional callback invoked when a control run panics
+	PanicHandler PanicHandler `json:"-"`
+	// if set, the tags of child benchmarks are merged into the tags of their parent result group
+	// this is set from the merge-child-tags arg when the tree is created, as it is used when building the result groups
+	mergeChildBenchmarkTags bool
+	// optional backend to which the results of each control run are written as it completes
+	ResultSink ResultSink `json:"-"`
+	// if set, a ResultSink error cancels the execution
+	AbortOnResultSinkError bool `json:"-"`
+	resultSinkErrors       []error
+	resultSinkLock         sync.Mutex
+	// optional sink notified as each control run starts and completes, and as each result group completes
+	EventSink ExecutionEventSink `json:"-"`
+	// lock used to serialize the calls to the EventSink
+	eventSinkLock sync.Mutex
+	// control runs which are waiting for the runs they depend on before acquiring the parallelism lock
+	pendingRuns sync.WaitGroup
+	cancel      context.CancelFunc
 }
 
-func NewExecutionTree(ctx context.Context, workspace *workspace.Workspace, client db_common.Client, controlFilterWhereClause string, args ...string) (*ExecutionTree, error) {
+// PanicHandler is called with the control and the recovered value when a control run panics,
+// before the panic is recorded as the control error
+type PanicHandler func(control *modconfig.Control, recovered any)
+
+func NewExecutionTree(ctx context.Context, workspace *workspace.Workspace, client db_common.Client, controlFilterWhereClause string, controlFilter ControlFilter, args ...string) (*ExecutionTree, error) {
 	if len(args) < 1 {
 		return nil, sperr.New("need at least one argument to create a check execution tree")
 	}
@@ -49,11 +74,14 @@ func NewExecutionTree(ctx context.Context, workspace *workspace.Workspace, clien
 
 	// now populate the ExecutionTree
 	executionTree := &ExecutionTree{
-		Workspace:  workspace,
-		client:     client,
-		SearchPath: utils.UnquoteStringArray(searchPath),
+		Workspace:     workspace,
+		client:        client,
+		SearchPath:    utils.UnquoteStringArray(searchPath),
+		controlFilter: controlFilter,
+
+		mergeChildBenchmarkTags: viper.GetBool(constants.ArgMergeChildTags),
 	}
-	// if a "--where" or "--tag" parameter was passed, build a map of control names used to filter the controls to run
+	// if a "--where" parameter was passed, build a map of control names used to filter the controls to run
 	// create a context with status hooks disabled
 	noStatusCtx := statushooks.DisableStatusHooks(ctx)
 	err := executionTree.populateControlFilterMap(noStatusCtx, controlFilterWhereClause)
@@ -101,7 +129,7 @@ func (*ExecutionTree) IsExportSourceData() {}
 // if so, creates a ControlRun, which is added to the parent group
 func (e *ExecutionTree) AddControl(ctx context.Context, control *modconfig.Control, group *ResultGroup) {
 	// note we use short name to determine whether to include a control
-	if e.ShouldIncludeControl(control.ShortName) {
+	if e.ShouldIncludeControl(control.ShortName) && (e.controlFilter == nil || e.controlFilter(control)) {
 		// create new ControlRun with treeItem as the parent
 		controlRun := NewControlRun(control, group, e)
 		// add it into the group
@@ -118,6 +146,10 @@ func (e *ExecutionTree) Execute(ctx context.Context) error {
 	e.StartTime = time.Now()
 	e.Progress.Start(ctx)
 
+	// create a cancellable context so a ResultSink error can abort the execution
+	ctx, e.cancel = context.WithCancel(ctx)
+	defer e.cancel()
+
 	defer func() {
 		e.EndTime = time.Now()
 		e.Progress.Finish(ctx)
@@ -132,10 +164,7 @@ func (e *ExecutionTree) Execute(ctx context.Context) error {
 	}
 
 	// the number of goroutines parallel to start
-	var maxParallelGoRoutines int64 = constants.DefaultMaxConnections
-	if viper.IsSet(constants.ArgMaxParallel) {
-		maxParallelGoRoutines = viper.GetInt64(constants.ArgMaxParallel)
-	}
+	maxParallelGoRoutines := maxParallelControls()
 
 	// to limit the number of parallel controls go routines started
 	parallelismLock := semaphore.NewWeighted(maxParallelGoRoutines)
@@ -151,9 +180,90 @@ func (e *ExecutionTree) Execute(ctx context.Context) error {
 	e.DimensionColorGenerator, _ = NewDimensionColorGenerator(4, 27)
 	e.DimensionColorGenerator.populate(e)
 
+	if e.AbortOnResultSinkError {
+		if sinkErrors := e.ResultSinkErrors(); len(sinkErrors) > 0 {
+			return sinkErrors[0]
+		}
+	}
 	return nil
 }
 
+// maxParallelControls returns the maximum number of control runs which may execute concurrently
+func maxParallelControls() int64 {
+	if viper.IsSet(constants.ArgMaxParallel) {
+		return viper.GetInt64(constants.ArgMaxParallel)
+	}
+	return constants.DefaultMaxConnections
+}
+
+// controlTimeout returns the maximum duration of a single control run - zero means no limit
+func controlTimeout() time.Duration {
+	return time.Duration(viper.GetInt(constants.ArgControlTimeout)) * time.Second
+}
+
+// controlRetries returns the number of times a control query which fails with a transient error is retried
+func controlRetries() int {
+	return viper.GetInt(constants.ArgControlRetries)
+}
+
+// ResultSinkErrors returns any errors returned by the ResultSink
+func (e *ExecutionTree) ResultSinkErrors() []error {
+	e.resultSinkLock.Lock()
+	defer e.resultSinkLock.Unlock()
+	return e.resultSinkErrors
+}
+
+// write the completed control run to the ResultSink (if set)
+// errors are recorded and logged - if AbortOnResultSinkError is set, the execution is also cancelled
+func (e *ExecutionTree) onControlRunComplete(ctx context.Context, run *ControlRun) {
+	if e.ResultSink == nil {
+		return
+	}
+	err := e.ResultSink.OnControlRunComplete(ctx, run)
+	if err == nil {
+		return
+	}
+	log.Printf("[WARN] failed to write results for control %s to result sink: %s", run.Control.Name(), err.Error())
+
+	e.resultSinkLock.Lock()
+	e.resultSinkErrors = append(e.resultSinkErrors, sperr.WrapWithMessage(err, "failed to write results for control %s", run.Control.Name()))
+	e.resultSinkLock.Unlock()
+
+	if e.AbortOnResultSinkError && e.cancel != nil {
+		e.cancel()
+	}
+}
+
+// notify the EventSink (if set) that the control run has started
+func (e *ExecutionTree) onControlStart(ctx context.Context, run *ControlRun) {
+	if e.EventSink == nil {
+		return
+	}
+	e.eventSinkLock.Lock()
+	defer e.eventSinkLock.Unlock()
+	e.EventSink.OnControlStart(ctx, run)
+}
+
+// notify the EventSink (if set) that the control run has completed
+func (e *ExecutionTree) onControlComplete(ctx context.Context, run *ControlRun) {
+	if e.EventSink == nil {
+		return
+	}
+	e.eventSinkLock.Lock()
+	defer e.eventSinkLock.Unlock()
+	e.EventSink.OnControlComplete(ctx, run)
+}
+
+// notify the EventSink (if set) that all children of the result group have completed
+func (e *ExecutionTree) onGroupComplete(ctx context.Context, group *ResultGroup) {
+	if e.EventSink == nil {
+		return
+	}
+	e.eventSinkLock.Lock()
+	defer e.eventSinkLock.Unlock()
+	e.EventSink.OnGroupComplete(ctx, group)
+}
+
 func (e *ExecutionTree) waitForActiveRunsToComplete(ctx context.Context, parallelismLock *semaphore.Weighted, maxParallelGoRoutines int64) error {
 	waitCtx := ctx
 	// if the context was already cancelled, we must creat ea new one to use  when waiting to acquire the lock
@@ -164,6 +274,8 @@ func (e *ExecutionTree) waitForActiveRunsToComplete(ctx context.Context, paralle
 		waitCtx = c
 		defer cancel()
 	}
+	// wait for any runs waiting for their dependencies to have started (or been cancelled)
+	e.pendingRuns.Wait()
 	// wait till we can acquire all semaphores - meaning that all active runs have finished
 	return parallelismLock.Acquire(waitCtx, maxParallelGoRoutines)
 }
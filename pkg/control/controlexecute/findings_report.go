@@ -0,0 +1,56 @@
+package controlexecute
+
+import (
+	"encoding/json"
+
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+const FindingsReportVersion = 1
+
+// FindingsReport is a simple findings format listing each alarm in a result tree,
+// for consumption by external (e.g. supply chain) tooling
+type FindingsReport struct {
+	Version  int        `json:"version"`
+	Findings []*Finding `json:"findings"`
+}
+
+// Finding is a single alarm raised by a control for a resource
+type Finding struct {
+	ControlId string `json:"control_id"`
+	Title     string `json:"title,omitempty"`
+	Severity  string `json:"severity,omitempty"`
+	Status    string `json:"status"`
+	Resource  string `json:"resource"`
+	Reason    string `json:"reason"`
+}
+
+// ToFindings builds a FindingsReport from the alarm rows of all descendant control runs, and returns it as JSON
+// findings are in result tree order
+func (r *ResultGroup) ToFindings() ([]byte, error) {
+	report := &FindingsReport{
+		Version:  FindingsReportVersion,
+		Findings: []*Finding{},
+	}
+	for _, run := range r.allControlRuns() {
+		for _, row := range run.Rows {
+			if row.Status != constants.ControlAlarm {
+				continue
+			}
+			// if the control severity depends on row data, use the row severity
+			severity := row.Severity
+			if severity == "" {
+				severity = run.Severity
+			}
+			report.Findings = append(report.Findings, &Finding{
+				ControlId: run.FullName,
+				Title:     run.Title,
+				Severity:  severity,
+				Status:    row.Status,
+				Resource:  row.Resource,
+				Reason:    row.Reason,
+			})
+		}
+	}
+	return json.MarshalIndent(report, "", "  ")
+}
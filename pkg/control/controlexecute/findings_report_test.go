@@ -0,0 +1,73 @@
+package controlexecute
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+type findingsReportTest struct {
+	severity *string
+	rows     []*ResultRow
+	expected []*Finding
+}
+
+var testCasesFindingsReport = map[string]findingsReportTest{
+	"alarms only": {
+		severity: utils.ToStringPointer("high"),
+		rows: []*ResultRow{
+			{Resource: "r1", Status: constants.ControlAlarm, Reason: "r1 is public"},
+			{Resource: "r2", Status: constants.ControlOk, Reason: "r2 is private"},
+			{Resource: "r3", Status: constants.ControlSkip, Reason: "r3 is skipped"},
+		},
+		expected: []*Finding{
+			{ControlId: "test.control.c1", Severity: "high", Status: constants.ControlAlarm, Resource: "r1", Reason: "r1 is public"},
+		},
+	},
+	"row severity": {
+		severity: utils.ToStringPointer("low"),
+		rows: []*ResultRow{
+			{Resource: "r1", Status: constants.ControlAlarm, Reason: "r1 is public", Severity: "critical"},
+			{Resource: "r2", Status: constants.ControlAlarm, Reason: "r2 is public"},
+		},
+		expected: []*Finding{
+			{ControlId: "test.control.c1", Severity: "critical", Status: constants.ControlAlarm, Resource: "r1", Reason: "r1 is public"},
+			{ControlId: "test.control.c1", Severity: "low", Status: constants.ControlAlarm, Resource: "r2", Reason: "r2 is public"},
+		},
+	},
+	"no alarms": {
+		rows: []*ResultRow{
+			{Resource: "r1", Status: constants.ControlOk, Reason: "r1 is private"},
+		},
+		expected: []*Finding{},
+	},
+}
+
+func TestToFindings(t *testing.T) {
+	for name, test := range testCasesFindingsReport {
+		control := newTestControl("c1")
+		control.Severity = test.severity
+		tree := newTestExecutionTree(control)
+		addTestResultRows(tree.ControlRuns[0], test.rows)
+
+		data, err := tree.Root.ToFindings()
+		if err != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, err)
+			continue
+		}
+		var report FindingsReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			t.Errorf("Test %s FAILED. Could not unmarshal findings: %v", name, err)
+			continue
+		}
+		if report.Version != FindingsReportVersion {
+			t.Errorf("Test %s FAILED. Expected version %d, got %d", name, FindingsReportVersion, report.Version)
+		}
+		if !reflect.DeepEqual(report.Findings, test.expected) {
+			t.Errorf("Test %s FAILED. Expected findings %v, got %v", name, test.expected, report.Findings)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package controlexecute
+
+// the key used by GroupByResource for rows which do not have the requested dimension
+const UnknownResourceKey = "unknown"
+
+// ControlResult is the result of a single control for a single resource
+type ControlResult struct {
+	ControlId  string      `json:"control_id"`
+	Title      string      `json:"title,omitempty"`
+	Severity   string      `json:"severity,omitempty"`
+	Status     string      `json:"status"`
+	Resource   string      `json:"resource"`
+	Reason     string      `json:"reason"`
+	Dimensions []Dimension `json:"dimensions,omitempty"`
+}
+
+// GroupByResource pivots the result rows of all descendant control runs by resource,
+// keyed by the value of the given dimension ('resource' may be used to key by the resource column)
+// rows which do not have the dimension are keyed by UnknownResourceKey
+// the results for each resource are in result tree order
+func (r *ResultGroup) GroupByResource(resourceDimension string) map[string][]ControlResult {
+	res := make(map[string][]ControlResult)
+	for _, run := range r.allControlRuns() {
+		for _, row := range run.Rows {
+			key := resourceKey(row, resourceDimension)
+			// if the control severity depends on row data, use the row severity
+			severity := row.Severity
+			if severity == "" {
+				severity = run.Severity
+			}
+			res[key] = append(res[key], ControlResult{
+				ControlId:  run.FullName,
+				Title:      run.Title,
+				Severity:   severity,
+				Status:     row.Status,
+				Resource:   row.Resource,
+				Reason:     row.Reason,
+				Dimensions: row.Dimensions,
+			})
+		}
+	}
+	return res
+}
+
+// UniqueResourceCount returns the number of distinct values of the given dimension across the result rows
+// of all descendant control runs ('resource' may be used to count the resource column)
+// rows which do not have the dimension are ignored
+func (r *ResultGroup) UniqueResourceCount(resourceDimension string) int {
+	resources := make(map[string]struct{})
+	for _, run := range r.allControlRuns() {
+		for _, row := range run.Rows {
+			if value, ok := resourceDimensionValue(row, resourceDimension); ok {
+				resources[value] = struct{}{}
+			}
+		}
+	}
+	return len(resources)
+}
+
+// resourceKey returns the value of the given dimension for the row, or UnknownResourceKey if the row does not have it
+func resourceKey(row *ResultRow, resourceDimension string) string {
+	if value, ok := resourceDimensionValue(row, resourceDimension); ok {
+		return value
+	}
+	return UnknownResourceKey
+}
+
+// resourceDimensionValue returns the (non-empty) value of the given dimension for the row, if it has one
+func resourceDimensionValue(row *ResultRow, resourceDimension string) (string, bool) {
+	if resourceDimension == "resource" && row.Resource != "" {
+		return row.Resource, true
+	}
+	for _, dim := range row.Dimensions {
+		if dim.Key == resourceDimension && dim.Value != "" {
+			return dim.Value, true
+		}
+	}
+	return "", false
+}
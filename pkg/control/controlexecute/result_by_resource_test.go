@@ -0,0 +1,98 @@
+package controlexecute
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+type groupByResourceTest struct {
+	dimension string
+	// map of resource key to results, formatted as '<control>:<status>'
+	expected map[string][]string
+}
+
+var testCasesGroupByResource = map[string]groupByResourceTest{
+	"bucket dimension": {
+		dimension: "bucket",
+		expected: map[string][]string{
+			"bucket-a":         {"test.control.c1:alarm", "test.control.c2:ok"},
+			"bucket-b":         {"test.control.c1:ok"},
+			UnknownResourceKey: {"test.control.c2:info"},
+		},
+	},
+	"resource column": {
+		dimension: "resource",
+		expected: map[string][]string{
+			"arn:bucket-a": {"test.control.c1:alarm", "test.control.c2:ok"},
+			"arn:bucket-b": {"test.control.c1:ok"},
+			"arn:account":  {"test.control.c2:info"},
+		},
+	},
+	"missing dimension": {
+		dimension: "region",
+		expected: map[string][]string{
+			UnknownResourceKey: {"test.control.c1:alarm", "test.control.c1:ok", "test.control.c2:info", "test.control.c2:ok"},
+		},
+	},
+}
+
+func TestGroupByResource(t *testing.T) {
+	tree := newResourceTestExecutionTree()
+	for name, test := range testCasesGroupByResource {
+		res := make(map[string][]string)
+		for key, results := range tree.Root.GroupByResource(test.dimension) {
+			for _, r := range results {
+				res[key] = append(res[key], fmt.Sprintf("%s:%s", r.ControlId, r.Status))
+			}
+		}
+		if !reflect.DeepEqual(res, test.expected) {
+			t.Errorf("Test %s FAILED. Expected %v, got %v", name, test.expected, res)
+		}
+	}
+}
+
+type uniqueResourceCountTest struct {
+	dimension string
+	expected  int
+}
+
+var testCasesUniqueResourceCount = map[string]uniqueResourceCountTest{
+	"bucket dimension": {
+		dimension: "bucket",
+		expected:  2,
+	},
+	"resource column": {
+		dimension: "resource",
+		expected:  3,
+	},
+	"missing dimension": {
+		dimension: "region",
+		expected:  0,
+	},
+}
+
+func TestUniqueResourceCount(t *testing.T) {
+	tree := newResourceTestExecutionTree()
+	for name, test := range testCasesUniqueResourceCount {
+		if count := tree.Root.UniqueResourceCount(test.dimension); count != test.expected {
+			t.Errorf("Test %s FAILED. Expected %d, got %d", name, test.expected, count)
+		}
+	}
+}
+
+// newResourceTestExecutionTree creates a tree with 2 controls which both assess bucket-a
+func newResourceTestExecutionTree() *ExecutionTree {
+	tree := newTestExecutionTree(newTestControl("c1"), newTestControl("c2"))
+	addTestResultRows(tree.ControlRuns[0], []*ResultRow{
+		{Resource: "arn:bucket-a", Status: constants.ControlAlarm, Dimensions: []Dimension{{Key: "bucket", Value: "bucket-a"}}},
+		{Resource: "arn:bucket-b", Status: constants.ControlOk, Dimensions: []Dimension{{Key: "bucket", Value: "bucket-b"}}},
+	})
+	addTestResultRows(tree.ControlRuns[1], []*ResultRow{
+		{Resource: "arn:bucket-a", Status: constants.ControlOk, Dimensions: []Dimension{{Key: "bucket", Value: "bucket-a"}}},
+		{Resource: "arn:account", Status: constants.ControlInfo},
+	})
+	return tree
+}
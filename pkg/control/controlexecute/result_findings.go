@@ -0,0 +1,107 @@
+package controlexecute
+
+import (
+	"log"
+
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+const (
+	findingColumnName = "finding"
+
+	// FindingNew indicates a result row which was not present in the baseline
+	FindingNew = "new"
+	// FindingExisting indicates a result row which was present in the baseline
+	FindingExisting = "existing"
+)
+
+// findingKey identifies a result row when comparing against a baseline
+type findingKey struct {
+	control  string
+	resource string
+	status   string
+}
+
+func newFindingKey(run *ControlRun, row *ResultRow) findingKey {
+	return findingKey{run.FullName, run.rowIdentity(row), row.Status}
+}
+
+// rowIdentity returns the value which identifies the row across runs
+// this is the value of the primary key column of the control, if specified and present, otherwise the resource
+func (r *ControlRun) rowIdentity(row *ResultRow) string {
+	if r.PrimaryKey != "" {
+		if value := row.GetDimensionValue(r.PrimaryKey); value != "" {
+			return value
+		}
+	}
+	return row.Resource
+}
+
+// warnUnknownPrimaryKey logs a warning if the primary key of the control
+// refers to a column not returned by the control query
+func (r *ControlRun) warnUnknownPrimaryKey(cols []*queryresult.ColumnDef) {
+	if r.PrimaryKey == "" {
+		return
+	}
+	for _, c := range cols {
+		if c.Name == r.PrimaryKey {
+			return
+		}
+	}
+	log.Printf("[WARN] %s has primary_key '%s' which is not returned by the control query - rows will be matched by resource", r.FullName, r.PrimaryKey)
+}
+
+// AnnotateFindings compares the rows of all descendant control runs with the rows of a baseline result group
+// (e.g. the results of a previous run), and sets the Finding of each row.
+// A row is "existing" if the baseline contains a row for the same control and resource with the same status,
+// otherwise it is "new" - so an alarm for a resource which was previously ok is a new finding
+// if the control has a primary_key, rows are matched by the value of that column rather than the resource
+func (r *ResultGroup) AnnotateFindings(baseline *ResultGroup) {
+	baselineRows := make(map[findingKey]struct{})
+	if baseline != nil {
+		for _, run := range baseline.allControlRuns() {
+			for _, row := range run.Rows {
+				baselineRows[newFindingKey(run, row)] = struct{}{}
+			}
+		}
+	}
+
+	for _, run := range r.allControlRuns() {
+		for _, row := range run.Rows {
+			row.Finding = FindingNew
+			if _, ok := baselineRows[newFindingKey(run, row)]; ok {
+				row.Finding = FindingExisting
+			}
+		}
+		run.addFindingsToData()
+	}
+}
+
+// allControlRuns returns the control runs of this group and all descendant groups
+func (r *ResultGroup) allControlRuns() []*ControlRun {
+	runs := append([]*ControlRun{}, r.ControlRuns...)
+	for _, g := range r.Groups {
+		runs = append(runs, g.allControlRuns()...)
+	}
+	return runs
+}
+
+// addFindingsToData adds the finding of each row to the snapshot data of the run
+func (r *ControlRun) addFindingsToData() {
+	if r.Data == nil || len(r.Data.Rows) != len(r.Rows) {
+		return
+	}
+	hasFindingColumn := false
+	for _, c := range r.Data.Columns {
+		if c.Name == findingColumnName {
+			hasFindingColumn = true
+			break
+		}
+	}
+	if !hasFindingColumn {
+		r.Data.Columns = append(r.Data.Columns, &queryresult.ColumnDef{Name: findingColumnName, DataType: "TEXT"})
+	}
+	for i, row := range r.Rows {
+		r.Data.Rows[i][findingColumnName] = row.Finding
+	}
+}
@@ -0,0 +1,128 @@
+package controlexecute
+
+import (
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+type findingsTest struct {
+	primaryKey   *string
+	baselineRows []*ResultRow
+	currentRows  []*ResultRow
+	expected     map[string]string
+}
+
+var testCasesFindings = map[string]findingsTest{
+	"new alarm": {
+		baselineRows: []*ResultRow{
+			{Resource: "r1", Status: constants.ControlAlarm},
+			{Resource: "r2", Status: constants.ControlOk},
+		},
+		currentRows: []*ResultRow{
+			{Resource: "r1", Status: constants.ControlAlarm},
+			{Resource: "r2", Status: constants.ControlAlarm},
+			{Resource: "r3", Status: constants.ControlAlarm},
+		},
+		expected: map[string]string{
+			"r1": FindingExisting,
+			"r2": FindingNew,
+			"r3": FindingNew,
+		},
+	},
+	"matched by primary key": {
+		primaryKey: utils.ToStringPointer("id"),
+		baselineRows: []*ResultRow{
+			{Resource: "old-name-1", Status: constants.ControlAlarm, Dimensions: []Dimension{{Key: "id", Value: "i-1"}}},
+			{Resource: "r2", Status: constants.ControlAlarm, Dimensions: []Dimension{{Key: "id", Value: "i-2"}}},
+		},
+		currentRows: []*ResultRow{
+			{Resource: "new-name-1", Status: constants.ControlAlarm, Dimensions: []Dimension{{Key: "id", Value: "i-1"}}},
+			{Resource: "r2", Status: constants.ControlAlarm, Dimensions: []Dimension{{Key: "id", Value: "i-3"}}},
+		},
+		expected: map[string]string{
+			"new-name-1": FindingExisting,
+			"r2":         FindingNew,
+		},
+	},
+	"primary key column missing": {
+		primaryKey: utils.ToStringPointer("id"),
+		baselineRows: []*ResultRow{
+			{Resource: "r1", Status: constants.ControlAlarm},
+		},
+		currentRows: []*ResultRow{
+			{Resource: "r1", Status: constants.ControlAlarm},
+		},
+		expected: map[string]string{
+			"r1": FindingExisting,
+		},
+	},
+	"empty baseline": {
+		currentRows: []*ResultRow{
+			{Resource: "r1", Status: constants.ControlOk},
+		},
+		expected: map[string]string{
+			"r1": FindingNew,
+		},
+	},
+}
+
+func TestAnnotateFindings(t *testing.T) {
+	for name, test := range testCasesFindings {
+		baselineControl := newTestControl("c1")
+		baselineControl.PrimaryKey = test.primaryKey
+		baseline := newTestExecutionTree(baselineControl)
+		addTestResultRows(baseline.ControlRuns[0], test.baselineRows)
+
+		// the baseline may also be a previous run restored from its binary form, which has no controls
+		data, err := baseline.Root.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Test %s FAILED to marshal baseline: %v", name, err)
+		}
+		restoredBaseline := &ResultGroup{}
+		if err := restoredBaseline.UnmarshalBinary(data); err != nil {
+			t.Fatalf("Test %s FAILED to unmarshal baseline: %v", name, err)
+		}
+
+		baselines := map[string]*ResultGroup{"baseline": baseline.Root, "unmarshalled baseline": restoredBaseline}
+		for baselineName, baselineRoot := range baselines {
+			currentControl := newTestControl("c1")
+			currentControl.PrimaryKey = test.primaryKey
+			current := newTestExecutionTree(currentControl)
+			run := current.ControlRuns[0]
+			addTestResultRows(run, cloneTestResultRows(test.currentRows))
+
+			current.Root.AnnotateFindings(baselineRoot)
+
+			for i, row := range run.Rows {
+				if row.Finding != test.expected[row.Resource] {
+					t.Errorf("Test %s (%s) FAILED. Expected resource %s to be %s, got %s", name, baselineName, row.Resource, test.expected[row.Resource], row.Finding)
+				}
+				// check the finding is included in the snapshot data
+				if data := run.Data.Rows[i][findingColumnName]; data != row.Finding {
+					t.Errorf("Test %s (%s) FAILED. Expected data finding for resource %s to be %s, got %v", name, baselineName, row.Resource, row.Finding, data)
+				}
+			}
+		}
+	}
+}
+
+// cloneTestResultRows returns copies of the given rows, so they may be added to more than one run
+func cloneTestResultRows(rows []*ResultRow) []*ResultRow {
+	res := make([]*ResultRow, len(rows))
+	for i, row := range rows {
+		rowCopy := *row
+		res[i] = &rowCopy
+	}
+	return res
+}
+
+func addTestResultRows(run *ControlRun, rows []*ResultRow) {
+	for _, row := range rows {
+		row.Run = run
+		run.addResultRow(row)
+	}
+	run.createdOrderedResultRows()
+	run.Data = run.Rows.ToLeafData(run.getDimensionSchema())
+}
@@ -43,16 +43,21 @@ type ResultGroup struct {
 	Severity map[string]controlstatus.StatusSummary `json:"-"`
 	// "benchmark"
 	NodeType string `json:"panel_type"`
+	// the relative weight of the group when computing the score of its parent (see WeightedScore)
+	Weight float64 `json:"-"`
 	// the control tree item associated with this group(i.e. a mod/benchmark)
 	GroupItem modconfig.ModTreeItem `json:"-"`
 	Parent    *ResultGroup          `json:"-"`
-	Duration  time.Duration         `json:"-"`
+	// the wall-clock duration of the group execution, from the start of execution until all descendants are complete
+	// NOTE: as controls execute in parallel this may be less than the summed duration of the controls (see ControlDuration)
+	Duration time.Duration `json:"-"`
 
 	// a list of distinct dimension keys from descendant controls
 	DimensionKeys []string `json:"-"`
 
 	childrenComplete   uint32
 	executionStartTime time.Time
+	tree               *ExecutionTree
 	// lock to prevent multiple control_runs updating this
 	updateLock *sync.Mutex
 }
@@ -77,6 +82,8 @@ func NewRootResultGroup(ctx context.Context, executionTree *ExecutionTree, rootI
 		updateLock: new(sync.Mutex),
 		NodeType:   modconfig.BlockTypeBenchmark,
 		Title:      rootItem.GetTitle(),
+		Weight:     1,
+		tree:       executionTree,
 	}
 
 	// if root item is a benchmark, create new result group with root as parent
@@ -106,6 +113,8 @@ func NewResultGroup(ctx context.Context, executionTree *ExecutionTree, treeItem
 		Severity:    make(map[string]controlstatus.StatusSummary),
 		updateLock:  new(sync.Mutex),
 		NodeType:    modconfig.BlockTypeBenchmark,
+		Weight:      1,
+		tree:        executionTree,
 	}
 
 	// populate additional properties (this avoids adding GetDocumentation, GetDisplay and GetType to all ModTreeItems)
@@ -114,6 +123,7 @@ func NewResultGroup(ctx context.Context, executionTree *ExecutionTree, treeItem
 		group.Documentation = t.GetDocumentation()
 		group.Display = t.GetDisplay()
 		group.Type = t.GetType()
+		group.Weight = t.GetWeight()
 	case *modconfig.Control:
 		group.Documentation = t.GetDocumentation()
 		group.Display = t.GetDisplay()
@@ -128,6 +138,9 @@ func NewResultGroup(ctx context.Context, executionTree *ExecutionTree, treeItem
 			if benchmarkGroup.ControlRunCount() > 0 {
 				// create a new result group with 'group' as the parent
 				group.addResultGroup(benchmarkGroup)
+				if executionTree.mergeChildBenchmarkTags {
+					group.mergeChildTags(benchmarkGroup)
+				}
 			}
 		}
 		if control, ok := c.(*modconfig.Control); ok {
@@ -138,6 +151,20 @@ func NewResultGroup(ctx context.Context, executionTree *ExecutionTree, treeItem
 	return group
 }
 
+// mergeChildTags merges the tags of the child group into our tags - where a tag is set on both,
+// our value is kept
+// NOTE: the tags map is copied before merging so the underlying benchmark definition is not mutated
+func (r *ResultGroup) mergeChildTags(child *ResultGroup) {
+	mergedTags := make(map[string]string, len(r.Tags)+len(child.Tags))
+	for k, v := range child.Tags {
+		mergedTags[k] = v
+	}
+	for k, v := range r.Tags {
+		mergedTags[k] = v
+	}
+	r.Tags = mergedTags
+}
+
 func (r *ResultGroup) AllTagKeys() []string {
 	tags := []string{}
 	for k := range r.Tags {
@@ -197,6 +224,45 @@ func (r *ResultGroup) ControlRunCount() int {
 	return count
 }
 
+// ControlsWithoutDimensions returns the names of all descendant controls whose results have no dimension keys
+// (mixing controls with and without dimensions produces ragged CSV output)
+func (r *ResultGroup) ControlsWithoutDimensions() []string {
+	var res []string
+	for _, run := range r.allControlRuns() {
+		if len(run.DimensionKeys) == 0 {
+			res = append(res, run.FullName)
+		}
+	}
+	return res
+}
+
+// WorstStatus returns the highest severity status present in the group summary,
+// using the ordering error > alarm > info > ok > skip
+// If the group has no results, skip is returned
+func (r *ResultGroup) WorstStatus() string {
+	r.updateLock.Lock()
+	defer r.updateLock.Unlock()
+
+	return worstStatus(r.Summary.Status)
+}
+
+// worstStatus returns the most severe status with a non-zero count in the summary
+// (in order of severity: error, alarm, info, ok) - if there are no such results, skip is returned
+func worstStatus(status controlstatus.StatusSummary) string {
+	switch {
+	case status.Error > 0:
+		return constants.ControlError
+	case status.Alarm > 0:
+		return constants.ControlAlarm
+	case status.Info > 0:
+		return constants.ControlInfo
+	case status.Ok > 0:
+		return constants.ControlOk
+	default:
+		return constants.ControlSkip
+	}
+}
+
 // IsSnapshotPanel implements SnapshotPanel
 func (*ResultGroup) IsSnapshotPanel() {}
 
@@ -246,7 +312,7 @@ func (r *ResultGroup) addDimensionKeys(keys ...string) {
 }
 
 // onChildDone is a callback that gets called from the children of this result group when they are done
-func (r *ResultGroup) onChildDone() {
+func (r *ResultGroup) onChildDone(ctx context.Context) {
 	newCount := atomic.AddUint32(&r.childrenComplete, 1)
 	totalCount := uint32(len(r.ControlRuns) + len(r.Groups))
 	if newCount < totalCount {
@@ -256,9 +322,79 @@ func (r *ResultGroup) onChildDone() {
 
 	// all children are done
 	r.Duration = time.Since(r.executionStartTime)
+	if r.tree != nil {
+		r.tree.onGroupComplete(ctx, r)
+	}
 	if r.Parent != nil {
-		r.Parent.onChildDone()
+		r.Parent.onChildDone(ctx)
+	}
+}
+
+// ControlDuration returns the summed execution duration of all descendant control runs
+func (r *ResultGroup) ControlDuration() time.Duration {
+	var res time.Duration
+	for _, run := range r.allControlRuns() {
+		res += run.Duration
+	}
+	return res
+}
+
+// ParallelismEfficiency returns the ratio of the summed control duration to the wall-clock duration of the group,
+// i.e. the average number of controls executing concurrently. Returns 0 if the group has no wall-clock duration
+func (r *ResultGroup) ParallelismEfficiency() float64 {
+	if r.Duration == 0 {
+		return 0
+	}
+	return float64(r.ControlDuration()) / float64(r.Duration)
+}
+
+// WeightedScore returns the score of the group, in the range 0-1
+// The score of a control run is the proportion of its passed (ok/info) results out of its passed and failed (alarm/error) results.
+// The score of a group is the average of the scores of its child control runs and groups,
+// weighted by the Weight of each child group (control runs have a weight of 1).
+// Children with no passed or failed results are excluded - if there are no such children, false is returned
+func (r *ResultGroup) WeightedScore() (float64, bool) {
+	var totalScore, totalWeight float64
+	for _, run := range r.ControlRuns {
+		summary := run.GetStatusSummary()
+		scored := summary.PassedCount() + summary.FailedCount()
+		if scored == 0 {
+			continue
+		}
+		totalScore += float64(summary.PassedCount()) / float64(scored)
+		totalWeight++
+	}
+	for _, group := range r.Groups {
+		score, ok := group.WeightedScore()
+		if !ok {
+			continue
+		}
+		totalScore += score * group.Weight
+		totalWeight += group.Weight
+	}
+	if totalWeight == 0 {
+		return 0, false
 	}
+	return totalScore / totalWeight, true
+}
+
+// PassRateBySeverity returns the pass rate of the group for each severity in the range 0-1,
+// i.e. the proportion of ok results out of all results of controls with that severity.
+// Severities with no results have a pass rate of 0
+func (r *ResultGroup) PassRateBySeverity() map[string]float64 {
+	r.updateLock.Lock()
+	defer r.updateLock.Unlock()
+
+	res := make(map[string]float64, len(r.Summary.Severity))
+	for severity, summary := range r.Summary.Severity {
+		total := summary.TotalCount()
+		if total == 0 {
+			res[severity] = 0
+			continue
+		}
+		res[severity] = float64(summary.Ok) / float64(total)
+	}
+	return res
 }
 
 func (r *ResultGroup) updateSummary(summary *controlstatus.StatusSummary) {
@@ -280,7 +416,7 @@ func (r *ResultGroup) updateSeverityCounts(severity string, summary *controlstat
 	r.updateLock.Lock()
 	defer r.updateLock.Unlock()
 
-	val, exists := r.Severity[severity]
+	val, exists := r.Summary.Severity[severity]
 	if !exists {
 		val = controlstatus.StatusSummary{}
 	}
@@ -313,6 +449,25 @@ func (r *ResultGroup) execute(ctx context.Context, client db_common.Client, para
 			continue
 		}
 
+		if dependencies := controlRun.dependencyRuns(); len(dependencies) > 0 {
+			// wait for the dependencies before acquiring the parallelism lock, so a slot is never held
+			// by a run which is waiting for a run which has not yet started
+			r.tree.pendingRuns.Add(1)
+			go func(run *ControlRun) {
+				defer r.tree.pendingRuns.Done()
+				if err := waitForRuns(ctx, dependencies); err != nil {
+					run.setError(ctx, err)
+					return
+				}
+				if err := parallelismLock.Acquire(ctx, 1); err != nil {
+					run.setError(ctx, err)
+					return
+				}
+				executeRun(ctx, run, parallelismLock, client)
+			}(controlRun)
+			continue
+		}
+
 		err := parallelismLock.Acquire(ctx, 1)
 		if err != nil {
 			controlRun.setError(ctx, err)
@@ -326,15 +481,101 @@ func (r *ResultGroup) execute(ctx context.Context, client db_common.Client, para
 	}
 }
 
+// ExecutionPlan returns a preview of the order in which the controls of the group will execute,
+// as batches of control ids which may run in parallel
+// each control appears once, in the first stage in which all of the controls it depends on (see depends_on)
+// have run - controls with no dependencies form the first stage
+// within a stage, controls are ordered as they are started by execute (the runs of a group, followed by its
+// child groups) and the size of each batch is limited by the maximum parallelism
+func (r *ResultGroup) ExecutionPlan() [][]string {
+	maxParallel := int(maxParallelControls())
+	if maxParallel < 1 {
+		maxParallel = 1
+	}
+
+	// get the runs in the order they are started by execute, keeping only the first run of each control
+	var runs []*ControlRun
+	runsByName := make(map[string]*ControlRun)
+	for _, run := range r.allControlRuns() {
+		if _, ok := runsByName[run.FullName]; ok {
+			continue
+		}
+		runsByName[run.FullName] = run
+		runs = append(runs, run)
+	}
+
+	// the stage of each control is one more than the latest stage of the controls it depends on
+	// (dependencies which are not part of the group are ignored)
+	stages := make(map[string]int, len(runs))
+	var getStage func(run *ControlRun) int
+	getStage = func(run *ControlRun) int {
+		if stage, ok := stages[run.FullName]; ok {
+			return stage
+		}
+		// mark as visited before recursing - depends_on cycles are a parse error so this is just a safeguard
+		stages[run.FullName] = 0
+		stage := 0
+		for _, dependency := range run.Control.DependsOn {
+			if dependencyRun, ok := runsByName[dependency.Name]; ok {
+				stage = max(stage, getStage(dependencyRun)+1)
+			}
+		}
+		stages[run.FullName] = stage
+		return stage
+	}
+
+	var stageIds [][]string
+	for _, run := range runs {
+		stage := getStage(run)
+		for len(stageIds) <= stage {
+			stageIds = append(stageIds, nil)
+		}
+		stageIds[stage] = append(stageIds[stage], run.ControlId)
+	}
+
+	var plan [][]string
+	for _, ids := range stageIds {
+		for i := 0; i < len(ids); i += maxParallel {
+			plan = append(plan, ids[i:min(i+maxParallel, len(ids))])
+		}
+	}
+	return plan
+}
+
 func executeRun(ctx context.Context, run *ControlRun, parallelismLock *semaphore.Weighted, client db_common.Client) {
 	defer func() {
 		if r := recover(); r != nil {
+			// if a panic handler is configured, give it a chance to report the panic
+			if run.Tree != nil && run.Tree.PanicHandler != nil {
+				run.Tree.PanicHandler(run.Control, r)
+			}
 			// if the Execute panic'ed, set it as an error
 			run.setError(ctx, helpers.ToError(r))
 		}
+		// write the completed run to the result sink (if any) and notify the event sink (if any)
+		if run.Tree != nil {
+			run.Tree.onControlRunComplete(ctx, run)
+			run.Tree.onControlComplete(ctx, run)
+		}
+		// update the group - this notifies the event sink when the group (and any ancestor groups) are complete
+		// NOTE: this is done after the control complete notification so the event order is consistent
+		if run.Group != nil {
+			run.Group.onChildDone(ctx)
+		}
 		// Release in defer, so that we don't retain the lock even if there's a panic inside
 		parallelismLock.Release(1)
 	}()
 
-	run.execute(ctx, client)
+	// if a control timeout is configured, a control which runs for longer is failed - sibling controls continue
+	runCtx := ctx
+	if timeout := controlTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+		run.timeout = timeout
+	}
+	if run.Tree != nil {
+		run.Tree.onControlStart(runCtx, run)
+	}
+	run.execute(runCtx, client)
 }
@@ -0,0 +1,281 @@
+package controlexecute
+
+import (
+	"bytes"
+	"encoding/gob"
+	"sync"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/control/controlstatus"
+	"github.com/turbot/steampipe/pkg/dashboard/dashboardtypes"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+// resultGroupData is the serialisable form of a ResultGroup used for binary encoding
+// parent and tree references are not stored - they are restored when decoding
+type resultGroupData struct {
+	GroupId         string
+	Title           string
+	Description     string
+	Tags            map[string]string
+	Documentation   string
+	Display         string
+	Type            string
+	NodeType        string
+	Status          controlstatus.StatusSummary
+	SummarySeverity map[string]controlstatus.StatusSummary
+	Severity        map[string]controlstatus.StatusSummary
+	Duration        time.Duration
+	Weight          float64
+	DimensionKeys   []string
+	// children are stored in order, each child is either a group or a control run
+	Children []resultGroupChildData
+}
+
+type resultGroupChildData struct {
+	Group *resultGroupData
+	Run   *controlRunData
+}
+
+// controlRunData is the serialisable form of a ControlRun used for binary encoding
+type controlRunData struct {
+	ControlId      string
+	FullName       string
+	Title          string
+	Description    string
+	Documentation  string
+	Tags           map[string]string
+	Display        string
+	Type           string
+	FileName       string
+	StartLine      int
+	Severity       string
+	Profile        string
+	RoleArn        string
+	BatchSize      int
+	PrimaryKey     string
+	Deprecated     bool
+	DeprecationMsg string
+	NodeType       string
+	Summary        controlstatus.StatusSummary
+	RunStatus      dashboardtypes.RunStatus
+	Rows           []resultRowData
+	DimensionKeys  []string
+	Duration       time.Duration
+	RunErrorString string
+}
+
+type resultRowData struct {
+	Reason     string
+	Resource   string
+	Status     string
+	Dimensions []Dimension
+	Severity   string
+	Finding    string
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+// it encodes the result group and all descendant groups and control runs
+func (r *ResultGroup) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(r.toBinaryData()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+// NOTE: the modconfig items (GroupItem and Control) are not restored
+func (r *ResultGroup) UnmarshalBinary(data []byte) error {
+	var groupData resultGroupData
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&groupData); err != nil {
+		return err
+	}
+	r.fromBinaryData(&groupData, nil)
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (r *ControlRun) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(r.toBinaryData()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+// NOTE: the Control, Group and Tree are not restored
+func (r *ControlRun) UnmarshalBinary(data []byte) error {
+	var runData controlRunData
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&runData); err != nil {
+		return err
+	}
+	r.fromBinaryData(&runData, nil)
+	return nil
+}
+
+func (r *ResultGroup) toBinaryData() *resultGroupData {
+	res := &resultGroupData{
+		GroupId:       r.GroupId,
+		Title:         r.Title,
+		Description:   r.Description,
+		Tags:          r.Tags,
+		Documentation: r.Documentation,
+		Display:       r.Display,
+		Type:          r.Type,
+		NodeType:      r.NodeType,
+		Severity:      r.Severity,
+		Duration:      r.Duration,
+		Weight:        r.Weight,
+		DimensionKeys: r.DimensionKeys,
+	}
+	if r.Summary != nil {
+		res.Status = r.Summary.Status
+		res.SummarySeverity = r.Summary.Severity
+	}
+	for _, child := range r.Children {
+		switch c := child.(type) {
+		case *ResultGroup:
+			res.Children = append(res.Children, resultGroupChildData{Group: c.toBinaryData()})
+		case *ControlRun:
+			res.Children = append(res.Children, resultGroupChildData{Run: c.toBinaryData()})
+		}
+	}
+	return res
+}
+
+func (r *ResultGroup) fromBinaryData(data *resultGroupData, parent *ResultGroup) {
+	*r = ResultGroup{
+		GroupId:       data.GroupId,
+		Title:         data.Title,
+		Description:   data.Description,
+		Tags:          data.Tags,
+		Documentation: data.Documentation,
+		Display:       data.Display,
+		Type:          data.Type,
+		NodeType:      data.NodeType,
+		Summary:       &GroupSummary{Status: data.Status, Severity: data.SummarySeverity},
+		Severity:      data.Severity,
+		Duration:      data.Duration,
+		Weight:        data.Weight,
+		DimensionKeys: data.DimensionKeys,
+		Parent:        parent,
+		Groups:        []*ResultGroup{},
+		updateLock:    new(sync.Mutex),
+	}
+	if r.Severity == nil {
+		r.Severity = make(map[string]controlstatus.StatusSummary)
+	}
+	if r.Summary.Severity == nil {
+		r.Summary.Severity = make(map[string]controlstatus.StatusSummary)
+	}
+	for _, child := range data.Children {
+		switch {
+		case child.Group != nil:
+			group := &ResultGroup{}
+			group.fromBinaryData(child.Group, r)
+			r.addResultGroup(group)
+		case child.Run != nil:
+			run := &ControlRun{}
+			run.fromBinaryData(child.Run, r)
+			r.addControl(run)
+		}
+	}
+}
+
+func (r *ControlRun) toBinaryData() *controlRunData {
+	res := &controlRunData{
+		ControlId:      r.ControlId,
+		FullName:       r.FullName,
+		Title:          r.Title,
+		Description:    r.Description,
+		Documentation:  r.Documentation,
+		Tags:           r.Tags,
+		Display:        r.Display,
+		Type:           r.Type,
+		FileName:       r.FileName,
+		StartLine:      r.StartLine,
+		Severity:       r.Severity,
+		Profile:        r.Profile,
+		RoleArn:        r.RoleArn,
+		BatchSize:      r.BatchSize,
+		PrimaryKey:     r.PrimaryKey,
+		Deprecated:     r.Deprecated,
+		DeprecationMsg: r.DeprecationMessage,
+		NodeType:       r.NodeType,
+		RunStatus:      r.GetRunStatus(),
+		DimensionKeys:  r.DimensionKeys,
+		Duration:       r.Duration,
+		RunErrorString: r.RunErrorString,
+		Rows:           make([]resultRowData, len(r.Rows)),
+	}
+	if r.Summary != nil {
+		res.Summary = *r.Summary
+	}
+	for i, row := range r.Rows {
+		res.Rows[i] = resultRowData{
+			Reason:     row.Reason,
+			Resource:   row.Resource,
+			Status:     row.Status,
+			Dimensions: row.Dimensions,
+			Severity:   row.Severity,
+			Finding:    row.Finding,
+		}
+	}
+	return res
+}
+
+func (r *ControlRun) fromBinaryData(data *controlRunData, group *ResultGroup) {
+	*r = ControlRun{
+		ControlId:      data.ControlId,
+		FullName:       data.FullName,
+		Title:          data.Title,
+		Description:    data.Description,
+		Documentation:  data.Documentation,
+		Tags:           data.Tags,
+		Display:        data.Display,
+		Type:           data.Type,
+		FileName:       data.FileName,
+		StartLine:      data.StartLine,
+		Severity:       data.Severity,
+		Profile:        data.Profile,
+		RoleArn:        data.RoleArn,
+		BatchSize:      data.BatchSize,
+		PrimaryKey:     data.PrimaryKey,
+		NodeType:       data.NodeType,
+		Summary:        &data.Summary,
+		RunStatus:      data.RunStatus,
+		DimensionKeys:  data.DimensionKeys,
+		Duration:       data.Duration,
+		RunErrorString: data.RunErrorString,
+		Group:          group,
+		rowMap:         make(map[string]ResultRows),
+		doneChan:       make(chan bool, 1),
+
+		Deprecated:         data.Deprecated,
+		DeprecationMessage: data.DeprecationMsg,
+	}
+
+	dimensionsSchema := make(map[string]*queryresult.ColumnDef)
+	for _, rowData := range data.Rows {
+		row := &ResultRow{
+			Reason:     rowData.Reason,
+			Resource:   rowData.Resource,
+			Status:     rowData.Status,
+			Dimensions: rowData.Dimensions,
+			Severity:   rowData.Severity,
+			Finding:    rowData.Finding,
+			Run:        r,
+		}
+		r.Rows = append(r.Rows, row)
+		r.rowMap[row.Status] = append(r.rowMap[row.Status], row)
+		for _, dim := range row.Dimensions {
+			if _, ok := dimensionsSchema[dim.Key]; !ok {
+				dimensionsSchema[dim.Key] = &queryresult.ColumnDef{Name: dim.Key, DataType: dim.SqlType}
+			}
+		}
+	}
+	// rebuild the snapshot data from the rows
+	r.Data = r.Rows.ToLeafData(dimensionsSchema)
+}
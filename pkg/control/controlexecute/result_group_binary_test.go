@@ -0,0 +1,86 @@
+package controlexecute
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/control/controlstatus"
+	"github.com/turbot/steampipe/pkg/dashboard/dashboardtypes"
+)
+
+func TestResultGroupBinaryRoundTrip(t *testing.T) {
+	tree := newTestExecutionTree(newTestControl("c1"), newTestControl("c2"))
+	for i, run := range tree.ControlRuns {
+		run.RunStatus = dashboardtypes.RunComplete
+		run.Duration = time.Duration(i+1) * time.Second
+		run.Profile = "audit"
+		run.RoleArn = "arn:aws:iam::123456789012:role/audit"
+		run.BatchSize = 100 * (i + 1)
+		run.PrimaryKey = "id"
+		run.Deprecated = true
+		run.DeprecationMessage = "use control c3"
+		run.addResultRow(&ResultRow{Reason: "ok", Resource: "r1", Status: constants.ControlOk, Dimensions: []Dimension{{Key: "region", Value: "us-east-1", SqlType: "text"}}, Run: run})
+		run.addResultRow(&ResultRow{Reason: "alarm", Resource: "r2", Status: constants.ControlAlarm, Run: run})
+		run.createdOrderedResultRows()
+		run.Group.updateSummary(run.Summary)
+		run.Group.updateSeverityCounts("high", run.Summary)
+	}
+	tree.Root.Duration = 3 * time.Second
+
+	data, err := tree.Root.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %s", err.Error())
+	}
+	var decoded ResultGroup
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %s", err.Error())
+	}
+
+	if decoded.GroupId != tree.Root.GroupId || decoded.Duration != tree.Root.Duration {
+		t.Errorf("Expected group %s with duration %v, got %s with duration %v", tree.Root.GroupId, tree.Root.Duration, decoded.GroupId, decoded.Duration)
+	}
+	expectedStatus := controlstatus.StatusSummary{Ok: 2, Alarm: 2}
+	if decoded.Summary.Status != expectedStatus {
+		t.Errorf("Expected summary %+v, got %+v", expectedStatus, decoded.Summary.Status)
+	}
+	if !reflect.DeepEqual(decoded.Summary.Severity, tree.Root.Summary.Severity) {
+		t.Errorf("Expected severity summary %+v, got %+v", tree.Root.Summary.Severity, decoded.Summary.Severity)
+	}
+	if decoded.ControlRunCount() != len(tree.ControlRuns) {
+		t.Fatalf("Expected %d control runs, got %d", len(tree.ControlRuns), decoded.ControlRunCount())
+	}
+
+	benchmarkGroup := decoded.Groups[0]
+	if benchmarkGroup.Parent != &decoded {
+		t.Errorf("Expected parent of child group to be restored")
+	}
+	for i, run := range benchmarkGroup.ControlRuns {
+		original := tree.ControlRuns[i]
+		if run.FullName != original.FullName || run.Duration != original.Duration || run.GetRunStatus() != original.GetRunStatus() {
+			t.Errorf("Expected control run %s (%v, %s), got %s (%v, %s)", original.FullName, original.Duration, original.GetRunStatus(), run.FullName, run.Duration, run.GetRunStatus())
+		}
+		if run.Profile != original.Profile || run.RoleArn != original.RoleArn {
+			t.Errorf("Expected control run credential hints '%s', '%s', got '%s', '%s'", original.Profile, original.RoleArn, run.Profile, run.RoleArn)
+		}
+		if run.BatchSize != original.BatchSize {
+			t.Errorf("Expected control run batch size %d, got %d", original.BatchSize, run.BatchSize)
+		}
+		if run.Deprecated != original.Deprecated || run.DeprecationMessage != original.DeprecationMessage {
+			t.Errorf("Expected control run deprecated %v with message '%s', got %v with message '%s'", original.Deprecated, original.DeprecationMessage, run.Deprecated, run.DeprecationMessage)
+		}
+		if run.PrimaryKey != original.PrimaryKey {
+			t.Errorf("Expected control run primary key '%s', got '%s'", original.PrimaryKey, run.PrimaryKey)
+		}
+		if *run.Summary != *original.Summary {
+			t.Errorf("Expected control run summary %+v, got %+v", *original.Summary, *run.Summary)
+		}
+		if len(run.Rows) != len(original.Rows) || run.Rows[0].Status != original.Rows[0].Status || !reflect.DeepEqual(run.Rows[1].Dimensions, original.Rows[1].Dimensions) {
+			t.Errorf("Expected rows to be restored")
+		}
+		if run.Group != benchmarkGroup {
+			t.Errorf("Expected control run group to be restored")
+		}
+	}
+}
@@ -0,0 +1,446 @@
+package controlexecute
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/control/controlstatus"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/utils"
+	"github.com/turbot/steampipe/pkg/workspace"
+	"golang.org/x/sync/semaphore"
+)
+
+// build a test execution tree for a benchmark containing the given controls
+func newTestExecutionTree(controls ...*modconfig.Control) *ExecutionTree {
+	mod := modconfig.NewMod("test", "", hcl.Range{})
+	tree := &ExecutionTree{
+		Workspace: &workspace.Workspace{Mod: mod},
+	}
+	children := make([]modconfig.ModTreeItem, len(controls))
+	for i, c := range controls {
+		c.Mod = mod
+		children[i] = c
+	}
+	benchmark := newTestBenchmark(mod, "b1", children...)
+	tree.Root = NewRootResultGroup(context.Background(), tree, benchmark)
+	tree.Progress = controlstatus.NewControlProgress(len(tree.ControlRuns))
+	return tree
+}
+
+func newTestBenchmark(mod *modconfig.Mod, name string, children ...modconfig.ModTreeItem) *modconfig.Benchmark {
+	block := &hcl.Block{Type: modconfig.BlockTypeBenchmark, Labels: []string{name}}
+	benchmark := modconfig.NewBenchmark(block, mod, name).(*modconfig.Benchmark)
+	benchmark.SetChildren(children)
+	return benchmark
+}
+
+func newTestControl(name string) *modconfig.Control {
+	block := &hcl.Block{Type: modconfig.BlockTypeControl, Labels: []string{name}}
+	return modconfig.NewControl(block, modconfig.NewMod("test", "", hcl.Range{}), name).(*modconfig.Control)
+}
+
+func TestExecuteRunPanicHandler(t *testing.T) {
+	tree := newTestExecutionTree(newTestControl("c1"))
+
+	var panickedControl *modconfig.Control
+	var recovered any
+	tree.PanicHandler = func(control *modconfig.Control, r any) {
+		panickedControl = control
+		recovered = r
+	}
+
+	run := tree.ControlRuns[0]
+	parallelismLock := semaphore.NewWeighted(1)
+	if err := parallelismLock.Acquire(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	// a nil client causes the run to panic when acquiring a session
+	executeRun(context.Background(), run, parallelismLock, nil)
+
+	if panickedControl != run.Control {
+		t.Errorf("expected panic handler to be called with control %s, got %v", run.Control.Name(), panickedControl)
+	}
+	if recovered == nil {
+		t.Errorf("expected panic handler to receive the recovered value")
+	}
+	if run.GetError() == nil {
+		t.Errorf("expected the panic to be set as the control error")
+	}
+}
+
+// blockingClient is a database client whose sessions are only returned when the context is done
+type blockingClient struct {
+	db_common.Client
+}
+
+func (c *blockingClient) AcquireSession(ctx context.Context) *db_common.AcquireSessionResult {
+	<-ctx.Done()
+	res := &db_common.AcquireSessionResult{}
+	res.Error = ctx.Err()
+	return res
+}
+
+func TestExecuteRunTimeout(t *testing.T) {
+	defer viper.Set(constants.ArgControlTimeout, nil)
+	viper.Set(constants.ArgControlTimeout, 1)
+
+	tree := newTestExecutionTree(newTestControl("c1"))
+	run := tree.ControlRuns[0]
+
+	parallelismLock := semaphore.NewWeighted(1)
+	if err := parallelismLock.Acquire(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	executeRun(context.Background(), run, parallelismLock, &blockingClient{})
+
+	expected := "control exceeded timeout of 1s"
+	if run.GetError() == nil || run.GetError().Error() != expected {
+		t.Errorf("Test TestExecuteRunTimeout FAILED. Expected error '%s', got %v", expected, run.GetError())
+	}
+	if run.Summary.Error != 1 {
+		t.Errorf("Test TestExecuteRunTimeout FAILED. Expected error count 1, got %d", run.Summary.Error)
+	}
+	// the parallelism lock must be released when the control times out
+	if !parallelismLock.TryAcquire(1) {
+		t.Errorf("Test TestExecuteRunTimeout FAILED. Expected parallelism lock to be released")
+	}
+}
+
+// searchPathClient is a database client which only provides the session search path
+type searchPathClient struct {
+	db_common.Client
+}
+
+func (c *searchPathClient) GetRequiredSessionSearchPath() []string {
+	return []string{"public"}
+}
+
+type mergeChildBenchmarkTagsTest struct {
+	mergeChildTags bool
+	expectedTags   map[string]string
+}
+
+var testCasesMergeChildBenchmarkTags = map[string]mergeChildBenchmarkTagsTest{
+	"merge child tags": {
+		mergeChildTags: true,
+		expectedTags:   map[string]string{"service": "s3", "category": "parent"},
+	},
+	"do not merge child tags": {
+		mergeChildTags: false,
+		expectedTags:   map[string]string{"category": "parent"},
+	},
+}
+
+func TestMergeChildBenchmarkTags(t *testing.T) {
+	defer viper.Set(constants.ArgMergeChildTags, nil)
+
+	for name, test := range testCasesMergeChildBenchmarkTags {
+		viper.Set(constants.ArgMergeChildTags, test.mergeChildTags)
+
+		mod := modconfig.NewMod("test", "", hcl.Range{})
+		control := newTestControl("c1")
+		control.Mod = mod
+		child := newTestBenchmark(mod, "child", control)
+		child.Tags = map[string]string{"service": "s3", "category": "child"}
+		parent := newTestBenchmark(mod, "parent", child)
+		parent.Tags = map[string]string{"category": "parent"}
+		mod.ResourceMaps.Benchmarks[parent.Name()] = parent
+
+		tree, err := NewExecutionTree(context.Background(), &workspace.Workspace{Mod: mod}, &searchPathClient{}, "", nil, parent.Name())
+		if err != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, err)
+			continue
+		}
+		parentGroup := tree.Root.GetChildGroupByName(parent.Name())
+		if !reflect.DeepEqual(parentGroup.Tags, test.expectedTags) {
+			t.Errorf("Test %s FAILED. Expected tags %v, got %v", name, test.expectedTags, parentGroup.Tags)
+		}
+		// the benchmark definition must not be mutated
+		if !reflect.DeepEqual(parent.Tags, map[string]string{"category": "parent"}) {
+			t.Errorf("Test %s FAILED. Benchmark tags were mutated: %v", name, parent.Tags)
+		}
+	}
+}
+
+func TestWorstStatus(t *testing.T) {
+	testCases := map[string]struct {
+		summary  controlstatus.StatusSummary
+		expected string
+	}{
+		"empty":            {summary: controlstatus.StatusSummary{}, expected: constants.ControlSkip},
+		"skip only":        {summary: controlstatus.StatusSummary{Skip: 2}, expected: constants.ControlSkip},
+		"ok and skip":      {summary: controlstatus.StatusSummary{Ok: 3, Skip: 1}, expected: constants.ControlOk},
+		"info ok skip":     {summary: controlstatus.StatusSummary{Info: 1, Ok: 3, Skip: 1}, expected: constants.ControlInfo},
+		"alarm info ok":    {summary: controlstatus.StatusSummary{Alarm: 1, Info: 1, Ok: 3}, expected: constants.ControlAlarm},
+		"error and others": {summary: controlstatus.StatusSummary{Error: 1, Alarm: 5, Info: 1, Ok: 3, Skip: 2}, expected: constants.ControlError},
+	}
+	for name, test := range testCases {
+		tree := newTestExecutionTree(newTestControl("c1"))
+		tree.Root.Summary.Status = test.summary
+		if status := tree.Root.WorstStatus(); status != test.expected {
+			t.Errorf("Test %s FAILED. Expected %s, got %s", name, test.expected, status)
+		}
+	}
+}
+
+func TestControlsWithoutDimensions(t *testing.T) {
+	tree := newTestExecutionTree(newTestControl("c1"), newTestControl("c2"), newTestControl("c3"))
+	dimensions := map[string][]Dimension{
+		"test.control.c1": {{Key: "region", Value: "us-east-1", SqlType: "text"}},
+		"test.control.c2": nil,
+		"test.control.c3": nil,
+	}
+	for _, run := range tree.ControlRuns {
+		run.addResultRow(&ResultRow{Resource: "r1", Status: constants.ControlOk, Dimensions: dimensions[run.FullName], Run: run})
+		run.createdOrderedResultRows()
+		run.getDimensionSchema()
+	}
+
+	expected := []string{"test.control.c2", "test.control.c3"}
+	if controls := tree.Root.ControlsWithoutDimensions(); !reflect.DeepEqual(controls, expected) {
+		t.Errorf("Expected %v, got %v", expected, controls)
+	}
+}
+
+func TestParallelismEfficiency(t *testing.T) {
+	tree := newTestExecutionTree(newTestControl("c1"), newTestControl("c2"), newTestControl("c3"))
+
+	// simulate the execution of 3 controls of 2s each, running in parallel over a wall-clock duration of 3s
+	startTime := time.Now().Add(-3 * time.Second)
+	tree.Root.executionStartTime = startTime
+	for _, group := range tree.Root.Groups {
+		group.executionStartTime = startTime
+	}
+	for _, run := range tree.ControlRuns {
+		run.Duration = 2 * time.Second
+		run.Group.onChildDone(context.Background())
+	}
+
+	if controlDuration := tree.Root.ControlDuration(); controlDuration != 6*time.Second {
+		t.Errorf("Expected control duration 6s, got %s", controlDuration)
+	}
+	if tree.Root.Duration < 3*time.Second || tree.Root.Duration > 4*time.Second {
+		t.Errorf("Expected wall-clock duration of approximately 3s, got %s", tree.Root.Duration)
+	}
+	// the wall-clock duration is slightly over 3s so the ratio is slightly under 2
+	if efficiency := tree.Root.ParallelismEfficiency(); efficiency < 1.5 || efficiency > 2 {
+		t.Errorf("Expected parallelism efficiency of approximately 2, got %f", efficiency)
+	}
+
+	// a group which has not executed has no efficiency
+	if efficiency := newTestExecutionTree(newTestControl("c1")).Root.ParallelismEfficiency(); efficiency != 0 {
+		t.Errorf("Expected parallelism efficiency of 0 for a group which has not executed, got %f", efficiency)
+	}
+}
+
+type weightedScoreTest struct {
+	weights       []*float64
+	summaries     []controlstatus.StatusSummary
+	expectedScore float64
+	expectedOk    bool
+}
+
+var testCasesWeightedScore = map[string]weightedScoreTest{
+	"weighted benchmarks": {
+		weights:       []*float64{utils.ToFloat64Pointer(3), utils.ToFloat64Pointer(1)},
+		summaries:     []controlstatus.StatusSummary{{Ok: 1}, {Alarm: 1}},
+		expectedScore: 0.75,
+		expectedOk:    true,
+	},
+	"default weights": {
+		weights:       []*float64{nil, nil},
+		summaries:     []controlstatus.StatusSummary{{Ok: 3, Alarm: 1}, {Alarm: 1}},
+		expectedScore: 0.375,
+		expectedOk:    true,
+	},
+	"zero weight": {
+		weights:       []*float64{utils.ToFloat64Pointer(0), nil},
+		summaries:     []controlstatus.StatusSummary{{Alarm: 1}, {Ok: 1, Info: 1}},
+		expectedScore: 1,
+		expectedOk:    true,
+	},
+	"unscored benchmark excluded": {
+		weights:       []*float64{utils.ToFloat64Pointer(5), nil},
+		summaries:     []controlstatus.StatusSummary{{Skip: 2}, {Ok: 1, Error: 1}},
+		expectedScore: 0.5,
+		expectedOk:    true,
+	},
+	"no scored results": {
+		weights:   []*float64{nil, nil},
+		summaries: []controlstatus.StatusSummary{{Skip: 1}, {}},
+	},
+}
+
+func TestWeightedScore(t *testing.T) {
+	for name, test := range testCasesWeightedScore {
+		mod := modconfig.NewMod("test", "", hcl.Range{})
+		tree := &ExecutionTree{
+			Workspace: &workspace.Workspace{Mod: mod},
+		}
+		// create a child benchmark with the given weight for each control
+		var benchmarks []modconfig.ModTreeItem
+		for i, weight := range test.weights {
+			control := newTestControl(fmt.Sprintf("c%d", i))
+			control.Mod = mod
+			benchmark := newTestBenchmark(mod, fmt.Sprintf("b%d", i), control)
+			benchmark.Weight = weight
+			benchmarks = append(benchmarks, benchmark)
+		}
+		tree.Root = NewRootResultGroup(context.Background(), tree, newTestBenchmark(mod, "parent", benchmarks...))
+		for i, run := range tree.ControlRuns {
+			summary := test.summaries[i]
+			run.Summary = &summary
+		}
+
+		score, ok := tree.Root.WeightedScore()
+		if ok != test.expectedOk {
+			t.Errorf("Test %s FAILED. Expected ok %v, got %v", name, test.expectedOk, ok)
+		}
+		if score != test.expectedScore {
+			t.Errorf("Test %s FAILED. Expected score %v, got %v", name, test.expectedScore, score)
+		}
+	}
+}
+
+type passRateBySeverityTest struct {
+	severities        []string
+	summaries         []controlstatus.StatusSummary
+	expectedPassRates map[string]float64
+}
+
+var testCasesPassRateBySeverity = map[string]passRateBySeverityTest{
+	"multiple severities": {
+		severities: []string{"high", "high", "low", "critical"},
+		summaries:  []controlstatus.StatusSummary{{Ok: 3, Alarm: 1}, {Ok: 1, Error: 3}, {Ok: 2}, {Alarm: 2, Skip: 1}},
+		expectedPassRates: map[string]float64{
+			"high":     0.5,
+			"low":      1,
+			"critical": 0,
+		},
+	},
+	"severity with no results": {
+		severities:        []string{"medium", "low"},
+		summaries:         []controlstatus.StatusSummary{{}, {Ok: 1, Info: 1, Alarm: 2}},
+		expectedPassRates: map[string]float64{"medium": 0, "low": 0.25},
+	},
+	"no controls": {
+		expectedPassRates: map[string]float64{},
+	},
+}
+
+func TestPassRateBySeverity(t *testing.T) {
+	for name, test := range testCasesPassRateBySeverity {
+		var controls []*modconfig.Control
+		for i := range test.severities {
+			controls = append(controls, newTestControl(fmt.Sprintf("c%d", i)))
+		}
+		tree := newTestExecutionTree(controls...)
+		for i, run := range tree.ControlRuns {
+			summary := test.summaries[i]
+			run.Group.updateSeverityCounts(test.severities[i], &summary)
+		}
+
+		if passRates := tree.Root.PassRateBySeverity(); !reflect.DeepEqual(passRates, test.expectedPassRates) {
+			t.Errorf("Test %s FAILED. Expected pass rates %v, got %v", name, test.expectedPassRates, passRates)
+		}
+	}
+}
+
+type executionPlanTest struct {
+	maxParallel int
+	// map of control name to the names of the controls it depends on
+	dependsOn    map[string][]string
+	expectedPlan [][]string
+}
+
+var testCasesExecutionPlan = map[string]executionPlanTest{
+	"parallelism 2": {
+		maxParallel: 2,
+		expectedPlan: [][]string{
+			{"control.c1", "control.c2"},
+			{"control.c3", "control.c4"},
+			{"control.c5"},
+		},
+	},
+	"parallelism exceeds control count": {
+		maxParallel: 10,
+		expectedPlan: [][]string{
+			{"control.c1", "control.c2", "control.c3", "control.c4", "control.c5"},
+		},
+	},
+	"parallelism 1": {
+		maxParallel: 1,
+		expectedPlan: [][]string{
+			{"control.c1"}, {"control.c2"}, {"control.c3"}, {"control.c4"}, {"control.c5"},
+		},
+	},
+	"dependencies": {
+		maxParallel: 10,
+		dependsOn: map[string][]string{
+			"c1": {"test.control.c3"},
+			"c4": {"test.control.c1"},
+			"c5": {"test.control.c4", "test.control.c2"},
+		},
+		expectedPlan: [][]string{
+			{"control.c2", "control.c3"},
+			{"control.c1"},
+			{"control.c4"},
+			{"control.c5"},
+		},
+	},
+	"dependencies with parallelism 2": {
+		maxParallel: 2,
+		dependsOn: map[string][]string{
+			"c1": {"test.control.c5"},
+		},
+		expectedPlan: [][]string{
+			{"control.c2", "control.c3"},
+			{"control.c4", "control.c5"},
+			{"control.c1"},
+		},
+	},
+	"dependency not in tree": {
+		maxParallel: 10,
+		dependsOn: map[string][]string{
+			"c1": {"test.control.c9"},
+		},
+		expectedPlan: [][]string{
+			{"control.c1", "control.c2", "control.c3", "control.c4", "control.c5"},
+		},
+	},
+}
+
+func TestExecutionPlan(t *testing.T) {
+	defer viper.Set(constants.ArgMaxParallel, nil)
+
+	for name, test := range testCasesExecutionPlan {
+		// b1 contains c1, c2 and b2 - b2 contains c3, c4, c5 and c2 (which must only appear in the plan once)
+		mod := modconfig.NewMod("test", "", hcl.Range{})
+		controls := make([]modconfig.ModTreeItem, 5)
+		for i := range controls {
+			control := newTestControl(fmt.Sprintf("c%d", i+1))
+			control.Mod = mod
+			for _, dependency := range test.dependsOn[control.ShortName] {
+				control.DependsOn = append(control.DependsOn, modconfig.NamedItem{Name: dependency})
+			}
+			controls[i] = control
+		}
+		b2 := newTestBenchmark(mod, "b2", controls[2], controls[3], controls[4], controls[1])
+		b1 := newTestBenchmark(mod, "b1", controls[0], controls[1], b2)
+		tree := &ExecutionTree{Workspace: &workspace.Workspace{Mod: mod}}
+		tree.Root = NewRootResultGroup(context.Background(), tree, b1)
+
+		viper.Set(constants.ArgMaxParallel, test.maxParallel)
+		if plan := tree.Root.ExecutionPlan(); !reflect.DeepEqual(plan, test.expectedPlan) {
+			t.Errorf("Test %s FAILED. Expected plan %v, got %v", name, test.expectedPlan, plan)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package controlexecute
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// JUnitTestSuites is the root element of a JUnit XML report
+type JUnitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr,omitempty"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Time     string            `xml:"time,attr"`
+	Suites   []*JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite is a JUnit test suite - this corresponds to a result group (i.e. a benchmark)
+// the counts include the test cases of nested suites
+type JUnitTestSuite struct {
+	Name      string            `xml:"name,attr"`
+	Tests     int               `xml:"tests,attr"`
+	Failures  int               `xml:"failures,attr"`
+	Skipped   int               `xml:"skipped,attr"`
+	Time      string            `xml:"time,attr"`
+	TestCases []*JUnitTestCase  `xml:"testcase"`
+	Suites    []*JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestCase is a JUnit test case - this corresponds to a single control result row
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitMessage `xml:"failure,omitempty"`
+	Skipped   *JUnitMessage `xml:"skipped,omitempty"`
+}
+
+// JUnitMessage is the failure or skipped element of a JUnit test case
+type JUnitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+}
+
+// ExportJUnit writes the result tree as a JUnit XML report, for consumption by CI pipelines
+// each result group is a testsuite (nested benchmarks are nested testsuites) and each control result row is a testcase
+// alarm and error rows (and control runs which failed to execute) are failures and skip rows are skipped
+// the type of a failure is the row status, so alarms and errors may be distinguished
+func ExportJUnit(root *ResultGroup, w io.Writer) error {
+	report := &JUnitTestSuites{Time: junitSeconds(root.Duration)}
+	// if this is the synthetic root group created to hold the benchmarks being run, its child groups are the top level suites
+	if root.GroupId == RootResultGroupName && len(root.ControlRuns) == 0 {
+		for _, group := range root.Groups {
+			report.addSuite(newJUnitTestSuite(group))
+		}
+	} else {
+		report.Name = root.GroupId
+		report.addSuite(newJUnitTestSuite(root))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	// the encoder does not terminate the document with a newline
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func newJUnitTestSuite(group *ResultGroup) *JUnitTestSuite {
+	name := group.Title
+	if name == "" {
+		name = group.GroupId
+	}
+	suite := &JUnitTestSuite{
+		Name: name,
+		Time: junitSeconds(group.Duration),
+	}
+	for _, run := range group.ControlRuns {
+		for _, testCase := range newJUnitTestCases(run) {
+			suite.addTestCase(testCase)
+		}
+	}
+	for _, childGroup := range group.Groups {
+		child := newJUnitTestSuite(childGroup)
+		suite.Suites = append(suite.Suites, child)
+		suite.Tests += child.Tests
+		suite.Failures += child.Failures
+		suite.Skipped += child.Skipped
+	}
+	return suite
+}
+
+func (r *JUnitTestSuites) addSuite(suite *JUnitTestSuite) {
+	r.Suites = append(r.Suites, suite)
+	r.Tests += suite.Tests
+	r.Failures += suite.Failures
+	r.Skipped += suite.Skipped
+}
+
+func (s *JUnitTestSuite) addTestCase(testCase *JUnitTestCase) {
+	s.TestCases = append(s.TestCases, testCase)
+	s.Tests++
+	switch {
+	case testCase.Failure != nil:
+		s.Failures++
+	case testCase.Skipped != nil:
+		s.Skipped++
+	}
+}
+
+// newJUnitTestCases returns a test case for each result row of the control run
+// the control duration is divided evenly between the rows
+// if the control failed to execute (so has no rows), a single failed test case is returned
+func newJUnitTestCases(run *ControlRun) []*JUnitTestCase {
+	if len(run.Rows) == 0 {
+		if run.GetError() == nil {
+			return nil
+		}
+		return []*JUnitTestCase{{
+			Name:      run.FullName,
+			ClassName: run.FullName,
+			Time:      junitSeconds(run.Duration),
+			Failure:   &JUnitMessage{Message: run.GetError().Error(), Type: constants.ControlError},
+		}}
+	}
+
+	rowDuration := run.Duration / time.Duration(len(run.Rows))
+	res := make([]*JUnitTestCase, len(run.Rows))
+	for i, row := range run.Rows {
+		name := row.Resource
+		if name == "" {
+			name = row.Reason
+		}
+		testCase := &JUnitTestCase{
+			Name:      name,
+			ClassName: run.FullName,
+			Time:      junitSeconds(rowDuration),
+		}
+		message := &JUnitMessage{Message: row.Reason, Type: row.Status}
+		switch row.Status {
+		case constants.ControlAlarm, constants.ControlError:
+			testCase.Failure = message
+		case constants.ControlSkip:
+			testCase.Skipped = message
+		}
+		res[i] = testCase
+	}
+	return res
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
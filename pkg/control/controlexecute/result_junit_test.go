@@ -0,0 +1,78 @@
+package controlexecute
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/workspace"
+)
+
+const expectedJUnit = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="5" failures="3" skipped="1" time="3.000">
+  <testsuite name="test.benchmark.b1" tests="5" failures="3" skipped="1" time="3.000">
+    <testcase name="r2" classname="test.control.c1" time="1.000">
+      <failure message="r2 is &lt;bad&gt;" type="alarm"></failure>
+    </testcase>
+    <testcase name="r1" classname="test.control.c1" time="1.000"></testcase>
+    <testsuite name="test.benchmark.b2" tests="3" failures="2" skipped="1" time="0.500">
+      <testcase name="r2" classname="test.control.c2" time="0.250">
+        <failure message="r2 errored" type="error"></failure>
+      </testcase>
+      <testcase name="r1" classname="test.control.c2" time="0.250">
+        <skipped message="r1 is skipped" type="skip"></skipped>
+      </testcase>
+      <testcase name="test.control.c3" classname="test.control.c3" time="0.000">
+        <failure message="relation does not exist" type="error"></failure>
+      </testcase>
+    </testsuite>
+  </testsuite>
+</testsuites>
+`
+
+func TestExportJUnit(t *testing.T) {
+	// b1 contains c1 and b2 - b2 contains c2 and c3
+	mod := modconfig.NewMod("test", "", hcl.Range{})
+	controls := make([]modconfig.ModTreeItem, 3)
+	for i, name := range []string{"c1", "c2", "c3"} {
+		control := newTestControl(name)
+		control.Mod = mod
+		controls[i] = control
+	}
+	b2 := newTestBenchmark(mod, "b2", controls[1:]...)
+	b1 := newTestBenchmark(mod, "b1", controls[0], b2)
+	tree := &ExecutionTree{Workspace: &workspace.Workspace{Mod: mod}}
+	tree.Root = NewRootResultGroup(context.Background(), tree, b1)
+	tree.Root.Duration = 3 * time.Second
+	tree.Root.Groups[0].Duration = 3 * time.Second
+	tree.Root.Groups[0].Groups[0].Duration = 500 * time.Millisecond
+
+	runs := map[string]*ControlRun{}
+	for _, run := range tree.ControlRuns {
+		runs[run.Control.ShortName] = run
+	}
+	runs["c1"].Duration = 2 * time.Second
+	addTestResultRows(runs["c1"], []*ResultRow{
+		{Resource: "r1", Status: constants.ControlOk, Reason: "r1 is ok"},
+		{Resource: "r2", Status: constants.ControlAlarm, Reason: "r2 is <bad>"},
+	})
+	runs["c2"].Duration = 500 * time.Millisecond
+	addTestResultRows(runs["c2"], []*ResultRow{
+		{Resource: "r1", Status: constants.ControlSkip, Reason: "r1 is skipped"},
+		{Resource: "r2", Status: constants.ControlError, Reason: "r2 errored"},
+	})
+	runs["c3"].runError = errors.New("relation does not exist")
+
+	var buf bytes.Buffer
+	if err := ExportJUnit(tree.Root, &buf); err != nil {
+		t.Fatalf("Test TestExportJUnit FAILED with unexpected error: %v", err)
+	}
+	if buf.String() != expectedJUnit {
+		t.Errorf("Test TestExportJUnit FAILED. Expected\n%s\ngot\n%s", expectedJUnit, buf.String())
+	}
+}
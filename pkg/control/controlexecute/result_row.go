@@ -27,12 +27,19 @@ func (r ResultRows) ToLeafData(dimensionSchema map[string]*queryresult.ColumnDef
 	for _, d := range dimensionSchema {
 		res.Columns = append(res.Columns, d)
 	}
+	// if the rows have been annotated with findings, add a finding column
+	if len(r) > 0 && r[0].Finding != "" {
+		res.Columns = append(res.Columns, &queryresult.ColumnDef{Name: findingColumnName, DataType: "TEXT"})
+	}
 	for i, row := range r {
 		res.Rows[i] = map[string]interface{}{
 			"reason":   row.Reason,
 			"resource": row.Resource,
 			"status":   row.Status,
 		}
+		if row.Finding != "" {
+			res.Rows[i][findingColumnName] = row.Finding
+		}
 		// flatten dimensions
 		for _, d := range row.Dimensions {
 			res.Rows[i][d.Key] = d.Value
@@ -51,6 +58,10 @@ type ResultRow struct {
 	Status string `json:"status" csv:"status"`
 	// dimensions for this row
 	Dimensions []Dimension `json:"dimensions"`
+	// severity of the row - only set if the control severity depends on row data
+	Severity string `json:"severity,omitempty"`
+	// whether this row is new or existing when compared to a baseline (only set by ResultGroup.AnnotateFindings)
+	Finding string `json:"finding,omitempty"`
 	// parent control run
 	Run *ControlRun `json:"-"`
 	// source control
@@ -76,6 +87,15 @@ func (r *ResultRow) AddDimension(c *queryresult.ColumnDef, val interface{}) {
 	})
 }
 
+// addHintedDimension adds a dimension for a column with a type hint, formatting the value using the hinted sql data type
+func (r *ResultRow) addHintedDimension(c *queryresult.ColumnDef, val interface{}, dataType string) {
+	r.Dimensions = append(r.Dimensions, Dimension{
+		Key:     c.Name,
+		Value:   formatHintedValue(val, dataType),
+		SqlType: dataType,
+	})
+}
+
 func NewResultRow(run *ControlRun, row *queryresult.RowResult, cols []*queryresult.ColumnDef) (*ResultRow, error) {
 	// validate the required columns exist in the result
 	if err := validateColumns(cols); err != nil {
@@ -105,14 +125,34 @@ func NewResultRow(run *ControlRun, row *queryresult.RowResult, cols []*queryresu
 			}
 			res.Status = status
 		default:
-			// if this is a scalar type, add to dimensions
 			val := row.Data[i]
+			// if the control has a type hint for this column, format the value using the hinted type
+			if run.Control != nil {
+				if dataType, ok := run.Control.GetColumnTypeHint(c.Name); ok {
+					res.addHintedDimension(c, val, dataType)
+					continue
+				}
+			}
+			// if this is a scalar type, add to dimensions
 			// isScalar may mutate the ColumnDef struct by lazily populating the internal isScalar property
 			if c.IsScalar(val) {
 				res.AddDimension(c, val)
 			}
 		}
 	}
+
+	// if the control severity depends on the row data, evaluate it now
+	if run.Control != nil && run.Control.HasRowSeverity() {
+		rowData := make(map[string]any, len(cols))
+		for i, c := range cols {
+			rowData[c.Name] = row.Data[i]
+		}
+		severity, err := run.Control.EvaluateRowSeverity(rowData)
+		if err != nil {
+			return nil, err
+		}
+		res.Severity = severity
+	}
 	return res, nil
 }
 
@@ -0,0 +1,137 @@
+package controlexecute
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/control/controlstatus"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+type rowSeverityTest struct {
+	severityExpression string
+	rows               [][]any
+	expectedSeverities []string
+	expectedCounts     map[string]controlstatus.StatusSummary
+}
+
+var testCasesRowSeverity = map[string]rowSeverityTest{
+	"severity depends on row": {
+		severityExpression: `row.public ? "critical" : "high"`,
+		rows: [][]any{
+			{"bucket is public", "b1", constants.ControlAlarm, true},
+			{"bucket is private", "b2", constants.ControlOk, false},
+			{"bucket is private", "b3", constants.ControlAlarm, false},
+		},
+		expectedSeverities: []string{"critical", "high", "high"},
+		expectedCounts: map[string]controlstatus.StatusSummary{
+			"critical": {Alarm: 1},
+			"high":     {Alarm: 1, Ok: 1},
+		},
+	},
+}
+
+func TestRowSeverity(t *testing.T) {
+	cols := []*queryresult.ColumnDef{{Name: "reason"}, {Name: "resource"}, {Name: "status"}, {Name: "public", DataType: "BOOL"}}
+
+	for name, test := range testCasesRowSeverity {
+		expr, diags := hclsyntax.ParseExpression([]byte(test.severityExpression), "", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatalf("failed to parse expression: %s", diags.Error())
+		}
+		control := newTestControl("c1")
+		control.SeverityExpression = expr
+		tree := newTestExecutionTree(control)
+		run := tree.ControlRuns[0]
+
+		var severities []string
+		for _, data := range test.rows {
+			row, err := NewResultRow(run, &queryresult.RowResult{Data: data}, cols)
+			if err != nil {
+				t.Errorf("Test %s FAILED with unexpected error: %v", name, err)
+				continue
+			}
+			severities = append(severities, row.Severity)
+			run.addResultRow(row)
+		}
+		run.createdOrderedResultRows()
+		run.updateSeverityCounts()
+
+		if !reflect.DeepEqual(severities, test.expectedSeverities) {
+			t.Errorf("Test %s FAILED. Expected severities %v, got %v", name, test.expectedSeverities, severities)
+		}
+		if !reflect.DeepEqual(tree.Root.Summary.Severity, test.expectedCounts) {
+			t.Errorf("Test %s FAILED. Expected severity counts %v, got %v", name, test.expectedCounts, tree.Root.Summary.Severity)
+		}
+	}
+}
+
+type columnTypeHintTest struct {
+	columnTypes        map[string]string
+	value              any
+	expectedDimensions []Dimension
+}
+
+var testCasesColumnTypeHint = map[string]columnTypeHintTest{
+	"no hint": {
+		value:              "2023-06-01T10:30:00Z",
+		expectedDimensions: []Dimension{{Key: "created", Value: "2023-06-01T10:30:00Z", SqlType: "TEXT"}},
+	},
+	"timestamp hint on string": {
+		columnTypes:        map[string]string{"created": "timestamp"},
+		value:              "2023-06-01T10:30:00Z",
+		expectedDimensions: []Dimension{{Key: "created", Value: "2023-06-01 10:30:00", SqlType: "TIMESTAMP"}},
+	},
+	"timestamp hint on time": {
+		columnTypes:        map[string]string{"created": "timestamp"},
+		value:              time.Date(2023, 6, 1, 10, 30, 0, 0, time.UTC),
+		expectedDimensions: []Dimension{{Key: "created", Value: "2023-06-01 10:30:00", SqlType: "TIMESTAMP"}},
+	},
+	"date hint": {
+		columnTypes:        map[string]string{"created": "date"},
+		value:              "2023-06-01T10:30:00Z",
+		expectedDimensions: []Dimension{{Key: "created", Value: "2023-06-01", SqlType: "DATE"}},
+	},
+	"number hint": {
+		columnTypes:        map[string]string{"created": "number"},
+		value:              "0042.50",
+		expectedDimensions: []Dimension{{Key: "created", Value: "42.5", SqlType: "NUMERIC"}},
+	},
+	"json hint": {
+		columnTypes:        map[string]string{"created": "json"},
+		value:              map[string]any{"a": 1},
+		expectedDimensions: []Dimension{{Key: "created", Value: `{"a":1}`, SqlType: "JSONB"}},
+	},
+	"unparseable value": {
+		columnTypes:        map[string]string{"created": "timestamp"},
+		value:              "yesterday",
+		expectedDimensions: []Dimension{{Key: "created", Value: "yesterday", SqlType: "TIMESTAMP"}},
+	},
+	"hint for another column": {
+		columnTypes:        map[string]string{"updated": "timestamp"},
+		value:              "2023-06-01T10:30:00Z",
+		expectedDimensions: []Dimension{{Key: "created", Value: "2023-06-01T10:30:00Z", SqlType: "TEXT"}},
+	},
+}
+
+func TestColumnTypeHint(t *testing.T) {
+	for name, test := range testCasesColumnTypeHint {
+		cols := []*queryresult.ColumnDef{{Name: "reason"}, {Name: "resource"}, {Name: "status"}, {Name: "created", DataType: "TEXT"}}
+		control := newTestControl("c1")
+		control.ColumnTypes = test.columnTypes
+		run := newTestExecutionTree(control).ControlRuns[0]
+
+		row, err := NewResultRow(run, &queryresult.RowResult{Data: []any{"reason", "r1", constants.ControlOk, test.value}}, cols)
+		if err != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(row.Dimensions, test.expectedDimensions) {
+			t.Errorf("Test %s FAILED. Expected dimensions %v, got %v", name, test.expectedDimensions, row.Dimensions)
+		}
+	}
+}
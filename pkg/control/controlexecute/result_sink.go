@@ -0,0 +1,36 @@
+package controlexecute
+
+import (
+	"context"
+	"sync"
+)
+
+// ResultSink is a backend to which control results are written as each control run completes
+type ResultSink interface {
+	OnControlRunComplete(ctx context.Context, run *ControlRun) error
+}
+
+// InMemoryResultSink is a ResultSink which stores completed control runs in memory
+type InMemoryResultSink struct {
+	runs []*ControlRun
+	lock sync.Mutex
+}
+
+func NewInMemoryResultSink() *InMemoryResultSink {
+	return &InMemoryResultSink{}
+}
+
+// OnControlRunComplete implements ResultSink
+func (s *InMemoryResultSink) OnControlRunComplete(_ context.Context, run *ControlRun) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.runs = append(s.runs, run)
+	return nil
+}
+
+// GetRuns returns the control runs written to the sink, in order of completion
+func (s *InMemoryResultSink) GetRuns() []*ControlRun {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]*ControlRun{}, s.runs...)
+}
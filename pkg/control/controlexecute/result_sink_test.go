@@ -0,0 +1,60 @@
+package controlexecute
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// a ResultSink which records the control runs written to it, and returns the configured error
+type recordingResultSink struct {
+	InMemoryResultSink
+	err error
+}
+
+func (s *recordingResultSink) OnControlRunComplete(ctx context.Context, run *ControlRun) error {
+	_ = s.InMemoryResultSink.OnControlRunComplete(ctx, run)
+	return s.err
+}
+
+func TestResultSink(t *testing.T) {
+	testCases := map[string]struct {
+		sinkError error
+		abort     bool
+	}{
+		"sink succeeds":              {},
+		"sink fails":                 {sinkError: errors.New("sink unavailable")},
+		"sink fails abort execution": {sinkError: errors.New("sink unavailable"), abort: true},
+	}
+
+	for name, test := range testCases {
+		tree := newTestExecutionTree(newTestControl("c1"), newTestControl("c2"))
+		sink := &recordingResultSink{err: test.sinkError}
+		tree.ResultSink = sink
+		tree.AbortOnResultSinkError = test.abort
+		ctx, cancel := context.WithCancel(context.Background())
+		tree.cancel = cancel
+
+		parallelismLock := semaphore.NewWeighted(1)
+		for _, run := range tree.ControlRuns {
+			if err := parallelismLock.Acquire(context.Background(), 1); err != nil {
+				t.Fatal(err)
+			}
+			// a nil client causes the run to fail - the completed run is still written to the sink
+			executeRun(ctx, run, parallelismLock, nil)
+		}
+
+		if runs := sink.GetRuns(); len(runs) != len(tree.ControlRuns) {
+			t.Errorf("Test %s FAILED. Expected %d runs written to sink, got %d", name, len(tree.ControlRuns), len(runs))
+		}
+		if sinkErrors := tree.ResultSinkErrors(); test.sinkError != nil && len(sinkErrors) != len(tree.ControlRuns) {
+			t.Errorf("Test %s FAILED. Expected %d sink errors, got %d", name, len(tree.ControlRuns), len(sinkErrors))
+		}
+		if aborted := ctx.Err() != nil; aborted != test.abort {
+			t.Errorf("Test %s FAILED. Expected execution aborted to be %v, got %v", name, test.abort, aborted)
+		}
+		cancel()
+	}
+}
@@ -1,5 +1,7 @@
 package controlstatus
 
+import "encoding/json"
+
 // StatusSummary is a struct containing the counts of each possible control status
 type StatusSummary struct {
 	Alarm int `json:"alarm"`
@@ -21,6 +23,20 @@ func (s *StatusSummary) TotalCount() int {
 	return s.Alarm + s.Ok + s.Info + s.Skip + s.Error
 }
 
+// MarshalJSON implements json.Marshaler
+// the computed total is serialised alongside the individual counts, so consumers do not need to recompute it
+func (s StatusSummary) MarshalJSON() ([]byte, error) {
+	// use a local type without the MarshalJSON method to avoid recursion
+	type statusSummary StatusSummary
+	return json.Marshal(struct {
+		statusSummary
+		Total int `json:"total"`
+	}{
+		statusSummary: statusSummary(s),
+		Total:         s.TotalCount(),
+	})
+}
+
 func (s *StatusSummary) Merge(summary *StatusSummary) {
 	s.Alarm += summary.Alarm
 	s.Ok += summary.Ok
@@ -0,0 +1,55 @@
+package controlstatus
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type statusSummaryJsonTest struct {
+	summary       StatusSummary
+	expectedTotal int
+}
+
+var testCasesStatusSummaryJson = map[string]statusSummaryJsonTest{
+	"all statuses": {
+		summary:       StatusSummary{Alarm: 1, Ok: 2, Info: 3, Skip: 4, Error: 5},
+		expectedTotal: 15,
+	},
+	"empty": {
+		summary:       StatusSummary{},
+		expectedTotal: 0,
+	},
+}
+
+func TestStatusSummaryMarshalJSON(t *testing.T) {
+	for name, test := range testCasesStatusSummaryJson {
+		if total := test.summary.TotalCount(); total != test.expectedTotal {
+			t.Errorf("Test %s FAILED. Expected total %d, got %d", name, test.expectedTotal, total)
+		}
+
+		// marshal both the value and a pointer, as both forms are serialised
+		for _, summary := range []any{test.summary, &test.summary} {
+			data, err := json.Marshal(summary)
+			if err != nil {
+				t.Errorf("Test %s FAILED with unexpected error: %v", name, err)
+				continue
+			}
+			var res map[string]int
+			if err := json.Unmarshal(data, &res); err != nil {
+				t.Errorf("Test %s FAILED with unexpected error: %v", name, err)
+				continue
+			}
+			sum := res["alarm"] + res["ok"] + res["info"] + res["skip"] + res["error"]
+			if res["total"] != test.expectedTotal || sum != test.expectedTotal {
+				t.Errorf("Test %s FAILED. Expected total and sum of counts %d, got total %d, sum %d (%s)", name, test.expectedTotal, res["total"], sum, data)
+			}
+		}
+
+		// the total is ignored when unmarshalling
+		data, _ := json.Marshal(test.summary)
+		var roundTripped StatusSummary
+		if err := json.Unmarshal(data, &roundTripped); err != nil || roundTripped != test.summary {
+			t.Errorf("Test %s FAILED. Expected round tripped summary %v, got %v (%v)", name, test.summary, roundTripped, err)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync"
 
 	"github.com/turbot/steampipe/pkg/dashboard/dashboardtypes"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
@@ -12,8 +13,13 @@ import (
 // DashboardContainerRun is a struct representing a container run
 type DashboardContainerRun struct {
 	DashboardParentImpl
+	// if set, the children of the container are laid out horizontally in a single row
+	Row bool `json:"row,omitempty"`
+	// if set, the children of the container are not executed until the container is loaded
+	Lazy bool `json:"lazy,omitempty"`
 
 	dashboardNode *modconfig.DashboardContainer
+	loadOnce      sync.Once
 }
 
 func (r *DashboardContainerRun) AsTreeNode() *dashboardtypes.SnapshotTreeNode {
@@ -43,11 +49,21 @@ func NewDashboardContainerRun(container *modconfig.DashboardContainer, parent da
 	if container.Width != nil {
 		r.Width = *container.Width
 	}
+	r.Row = container.IsRow()
+	// lazy containers can only be loaded by an interactive execution - otherwise execute the children immediately
+	r.Lazy = container.IsLazy() && executionTree.interactive
 	r.childCompleteChan = make(chan dashboardtypes.DashboardTreeRun, len(children))
 	for _, child := range children {
+		// if the child has a condition which evaluates false, exclude it
+		include, err := executionTree.evaluateCondition(child)
+		if err != nil {
+			return nil, err
+		}
+		if !include {
+			continue
+		}
+
 		var childRun dashboardtypes.DashboardTreeRun
-		//nolint:golint // using a global var here to maintain parity with definition of childRun
-		var err error
 		switch i := child.(type) {
 		case *modconfig.DashboardContainer:
 			childRun, err = NewDashboardContainerRun(i, r, executionTree)
@@ -104,7 +120,17 @@ func (r *DashboardContainerRun) Initialise(ctx context.Context) {
 
 // Execute implements DashboardTreeRun
 // execute all children and wait for them to complete
+// if the container is lazy, the children are not executed and the container is blocked until Load is called
 func (r *DashboardContainerRun) Execute(ctx context.Context) {
+	if r.Lazy {
+		log.Printf("[TRACE] %s Execute - container is lazy, deferring execution of children until loaded", r.Name)
+		r.setStatus(ctx, dashboardtypes.RunBlocked)
+		return
+	}
+	r.executeChildren(ctx)
+}
+
+func (r *DashboardContainerRun) executeChildren(ctx context.Context) {
 	// execute all children asynchronously
 	r.executeChildrenAsync(ctx)
 
@@ -123,5 +149,18 @@ func (r *DashboardContainerRun) Execute(ctx context.Context) {
 	}
 }
 
+// Load executes the children of a lazy container and waits for them to complete
+// the children are only executed once - subsequent calls do nothing
+func (r *DashboardContainerRun) Load(ctx context.Context) error {
+	if !r.Lazy {
+		return fmt.Errorf("%s is not a lazy container", r.Name)
+	}
+	r.loadOnce.Do(func() {
+		log.Printf("[TRACE] %s Load - executing children", r.Name)
+		r.executeChildren(ctx)
+	})
+	return r.GetError()
+}
+
 // IsSnapshotPanel implements SnapshotPanel
 func (*DashboardContainerRun) IsSnapshotPanel() {}
@@ -0,0 +1,192 @@
+package dashboardexecute
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/pkg/dashboard/dashboardtypes"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/workspace"
+)
+
+// testChildRun is a child run which records whether it has been executed
+type testChildRun struct {
+	DashboardTreeRunImpl
+	executed bool
+	// the error of the context the child was executed with
+	ctxErr error
+}
+
+func (r *testChildRun) Initialise(context.Context) {}
+
+func (r *testChildRun) Execute(ctx context.Context) {
+	r.executed = true
+	r.ctxErr = ctx.Err()
+	r.SetComplete(ctx)
+}
+
+func (r *testChildRun) AsTreeNode() *dashboardtypes.SnapshotTreeNode {
+	return &dashboardtypes.SnapshotTreeNode{Name: r.Name, NodeType: r.NodeType}
+}
+
+type containerRunLazyTest struct {
+	lazy        bool
+	interactive bool
+	// is the child executed when the container is executed
+	expectedExecuted bool
+	// can the container be loaded
+	expectedLoadable bool
+}
+
+var testCasesContainerRunLazy = map[string]containerRunLazyTest{
+	"eager": {
+		lazy:             false,
+		interactive:      true,
+		expectedExecuted: true,
+	},
+	"lazy": {
+		lazy:             true,
+		interactive:      true,
+		expectedExecuted: false,
+		expectedLoadable: true,
+	},
+	"lazy non interactive": {
+		lazy:             true,
+		interactive:      false,
+		expectedExecuted: true,
+	},
+}
+
+func TestContainerRunLazy(t *testing.T) {
+	ctx := context.Background()
+	for name, test := range testCasesContainerRunLazy {
+		containerRun, childRun := newTestContainerRun(t, test.lazy, test.interactive)
+
+		containerRun.Execute(ctx)
+		if childRun.executed != test.expectedExecuted {
+			t.Errorf("Test %s FAILED. Expected child executed %v, got %v", name, test.expectedExecuted, childRun.executed)
+		}
+		// a lazy container is blocked until it is loaded
+		if containerRun.RunComplete() != test.expectedExecuted {
+			t.Errorf("Test %s FAILED. Expected container complete %v, got status %s", name, test.expectedExecuted, containerRun.GetRunStatus())
+		}
+
+		err := containerRun.Load(ctx)
+		if !test.expectedLoadable {
+			if err == nil {
+				t.Errorf("Test %s FAILED. Expected error loading a container which is not lazy", name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, err)
+			continue
+		}
+		if !childRun.executed || !childRun.RunComplete() {
+			t.Errorf("Test %s FAILED. Expected child to be executed and complete after load, got status %s", name, childRun.GetRunStatus())
+		}
+		if !containerRun.RunComplete() {
+			t.Errorf("Test %s FAILED. Expected container to be complete after load, got status %s", name, containerRun.GetRunStatus())
+		}
+		// a second load does nothing
+		if err := containerRun.Load(ctx); err != nil {
+			t.Errorf("Test %s FAILED with unexpected error on second load: %v", name, err)
+		}
+	}
+}
+
+type loadContainerTest struct {
+	// is the execution started before the container is loaded
+	started bool
+	// is the execution cancelled before the container is loaded
+	cancelled     bool
+	expectedError string
+}
+
+var testCasesLoadContainer = map[string]loadContainerTest{
+	"running execution": {
+		started: true,
+	},
+	"cancelled execution": {
+		started:       true,
+		cancelled:     true,
+		expectedError: "context canceled",
+	},
+	"execution not started": {
+		expectedError: "execution of test.dashboard.d1 has not started",
+	},
+}
+
+func TestLoadContainer(t *testing.T) {
+	for name, test := range testCasesLoadContainer {
+		containerRun, childRun := newTestContainerRun(t, true, true)
+		executionTree := containerRun.executionTree
+		executionTree.runs[containerRun.Name] = containerRun
+
+		if test.started {
+			// the execution context is set when the tree is executed
+			runCtx, cancel := context.WithCancel(context.Background())
+			executionTree.setRunContext(runCtx)
+			if test.cancelled {
+				cancel()
+			} else {
+				defer cancel()
+			}
+		}
+
+		err := executionTree.LoadContainer(containerRun.Name)
+		if test.expectedError != "" {
+			if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+				t.Errorf("Test %s FAILED. Expected error containing '%s', got %v", name, test.expectedError, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, err)
+			continue
+		}
+		if !childRun.executed || childRun.ctxErr != nil {
+			t.Errorf("Test %s FAILED. Expected child to be executed with a live context, got executed %v, context error %v", name, childRun.executed, childRun.ctxErr)
+		}
+	}
+}
+
+// newTestContainerRun creates a container run with a single child run which has not yet executed
+func newTestContainerRun(t *testing.T, lazy, interactive bool) (*DashboardContainerRun, *testChildRun) {
+	mod := modconfig.NewMod("test", "", hcl.Range{})
+	container := newTestContainer(mod, "container1")
+	container.Lazy = &lazy
+	child := newTestContainer(mod, "container2")
+
+	executionTree := &DashboardExecutionTree{
+		dashboardName:   "test.dashboard.d1",
+		runs:            make(map[string]dashboardtypes.DashboardTreeRun),
+		workspace:       &workspace.Workspace{},
+		runComplete:     make(chan dashboardtypes.DashboardTreeRun, 1),
+		inputValues:     make(map[string]any),
+		conditionInputs: make(map[string]struct{}),
+		interactive:     interactive,
+	}
+	containerRun, err := NewDashboardContainerRun(container, executionTree, executionTree)
+	if err != nil {
+		t.Fatalf("failed to create container run: %s", err.Error())
+	}
+
+	childRun := &testChildRun{}
+	childRun.DashboardTreeRunImpl = NewDashboardTreeRunImpl(child, containerRun, childRun, executionTree)
+	childRun.Status = dashboardtypes.RunInitialized
+	containerRun.children = []dashboardtypes.DashboardTreeRun{childRun}
+	containerRun.childCompleteChan = make(chan dashboardtypes.DashboardTreeRun, 1)
+	containerRun.Status = dashboardtypes.RunInitialized
+
+	return containerRun, childRun
+}
+
+func newTestContainer(mod *modconfig.Mod, shortName string) *modconfig.DashboardContainer {
+	block := &hcl.Block{Type: modconfig.BlockTypeContainer, Labels: []string{shortName}}
+	container := modconfig.NewDashboardContainer(block, mod, shortName).(*modconfig.DashboardContainer)
+	container.SetMetadata(&modconfig.ResourceMetadata{})
+	return container
+}
@@ -0,0 +1,65 @@
+package dashboardexecute
+
+import (
+	"github.com/turbot/pipe-fittings/hclhelpers"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// evaluateCondition evaluates the 'if' condition of a dashboard child which depends on inputs,
+// using the input values of the execution tree
+// (conditions which do not depend on inputs are evaluated when the mod is parsed)
+// If any input referenced by the condition does not yet have a value, the child is excluded
+func (e *DashboardExecutionTree) evaluateCondition(item modconfig.ModTreeItem) (bool, error) {
+	impl := item.GetModTreeItemImpl()
+	inputNames := impl.ConditionInputs()
+	if len(inputNames) == 0 {
+		return true, nil
+	}
+
+	e.inputLock.Lock()
+	defer e.inputLock.Unlock()
+
+	inputs := make(map[string]cty.Value, len(inputNames))
+	for _, inputName := range inputNames {
+		e.conditionInputs[inputName] = struct{}{}
+
+		value, ok := e.inputValues[inputName]
+		if !ok || value == nil {
+			return false, nil
+		}
+		ctyValue, err := hclhelpers.ConvertInterfaceToCtyValue(value)
+		if err != nil {
+			return false, err
+		}
+		parsedName, err := modconfig.ParseResourceName(inputName)
+		if err != nil {
+			return false, err
+		}
+		inputs[parsedName.Name] = cty.ObjectVal(map[string]cty.Value{"value": ctyValue})
+	}
+
+	// inputs may be referenced either as 'input.<name>' or 'self.input.<name>'
+	// the inputs are added to a child of the parse eval context, so the condition may also reference variables and locals
+	inputsValue := cty.ObjectVal(inputs)
+	evalCtx := impl.ConditionEvalContext()
+	evalCtx.Variables = map[string]cty.Value{
+		modconfig.BlockTypeInput: inputsValue,
+		"self":                   cty.ObjectVal(map[string]cty.Value{modconfig.BlockTypeInput: inputsValue}),
+	}
+	include, diags := impl.EvaluateCondition(evalCtx)
+	if diags.HasErrors() {
+		return false, plugin.DiagsToError("failed to evaluate condition", diags)
+	}
+	return include, nil
+}
+
+// hasConditionDependingOn returns whether the given input is referenced by the 'if' condition of any dashboard child
+func (e *DashboardExecutionTree) hasConditionDependingOn(inputName string) bool {
+	e.inputLock.Lock()
+	defer e.inputLock.Unlock()
+
+	_, ok := e.conditionInputs[inputName]
+	return ok
+}
@@ -0,0 +1,72 @@
+package dashboardexecute
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type evaluateConditionTest struct {
+	condition   string
+	inputValues map[string]any
+	expected    bool
+}
+
+var testCasesEvaluateCondition = map[string]evaluateConditionTest{
+	"variable and input": {
+		condition:   `var.region == "us-east-1" && input.i1.value == "show"`,
+		inputValues: map[string]any{"input.i1": "show"},
+		expected:    true,
+	},
+	"variable and self input": {
+		condition:   `var.region == "us-east-1" && self.input.i1.value == "show"`,
+		inputValues: map[string]any{"input.i1": "hide"},
+		expected:    false,
+	},
+	"local and input": {
+		condition:   `local.enabled && input.i1.value == "show"`,
+		inputValues: map[string]any{"input.i1": "show"},
+		expected:    true,
+	},
+	"input without value": {
+		condition: `var.region == "us-east-1" && input.i1.value == "show"`,
+		expected:  false,
+	},
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	// the parse eval context, which provides the mod variables and locals
+	parseEvalCtx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var":   cty.ObjectVal(map[string]cty.Value{"region": cty.StringVal("us-east-1")}),
+			"local": cty.ObjectVal(map[string]cty.Value{"enabled": cty.True}),
+		},
+	}
+	mod := modconfig.NewMod("test", "", hcl.Range{})
+
+	for name, test := range testCasesEvaluateCondition {
+		expr, diags := hclsyntax.ParseExpression([]byte(test.condition), "test.sp", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		container := newTestContainer(mod, "container1")
+		container.If = expr
+		container.SetConditionEvalContext(parseEvalCtx)
+
+		executionTree := &DashboardExecutionTree{
+			inputValues:     test.inputValues,
+			conditionInputs: make(map[string]struct{}),
+		}
+		include, err := executionTree.evaluateCondition(container)
+		if err != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, err)
+			continue
+		}
+		if include != test.expected {
+			t.Errorf("Test %s FAILED. Expected %v, got %v", name, test.expected, include)
+		}
+	}
+}
@@ -33,19 +33,35 @@ type DashboardExecutionTree struct {
 	cancel      context.CancelFunc
 	inputLock   sync.Mutex
 	inputValues map[string]any
+	// the names of inputs referenced by the 'if' conditions of dashboard children
+	conditionInputs map[string]struct{}
+	// the cancellable context the tree is executed with - lazy containers are loaded with this context
+	// so that cancelling the execution also cancels their children
+	runCtx     context.Context
+	runCtxLock sync.Mutex
+	// is this an interactive execution, i.e. may inputs be set and lazy containers be loaded after execution starts
+	interactive bool
 	id          string
 }
 
-func NewDashboardExecutionTree(rootName string, sessionId string, client db_common.Client, workspace *workspace.Workspace) (*DashboardExecutionTree, error) {
+// NewDashboardExecutionTree creates an execution tree for the given root resource
+// inputValues are the input values known when the execution starts - these are used to evaluate
+// the 'if' conditions of dashboard children which depend on inputs
+func NewDashboardExecutionTree(rootName string, sessionId string, client db_common.Client, workspace *workspace.Workspace, inputValues map[string]any, interactive bool) (*DashboardExecutionTree, error) {
 	// now populate the DashboardExecutionTree
 	executionTree := &DashboardExecutionTree{
-		dashboardName: rootName,
-		sessionId:     sessionId,
-		client:        client,
-		runs:          make(map[string]dashboardtypes.DashboardTreeRun),
-		workspace:     workspace,
-		runComplete:   make(chan dashboardtypes.DashboardTreeRun, 1),
-		inputValues:   make(map[string]any),
+		dashboardName:   rootName,
+		sessionId:       sessionId,
+		client:          client,
+		runs:            make(map[string]dashboardtypes.DashboardTreeRun),
+		workspace:       workspace,
+		runComplete:     make(chan dashboardtypes.DashboardTreeRun, 1),
+		inputValues:     maps.Clone(inputValues),
+		conditionInputs: make(map[string]struct{}),
+		interactive:     interactive,
+	}
+	if executionTree.inputValues == nil {
+		executionTree.inputValues = make(map[string]any)
 	}
 	executionTree.id = fmt.Sprintf("%p", executionTree)
 
@@ -128,6 +144,7 @@ func (e *DashboardExecutionTree) Execute(ctx context.Context) {
 	// store context
 	cancelCtx, cancel := context.WithCancel(ctx)
 	e.cancel = cancel
+	e.setRunContext(cancelCtx)
 	workspace := e.workspace
 
 	// perform any necessary initialisation
@@ -195,6 +212,36 @@ func (e *DashboardExecutionTree) Execute(ctx context.Context) {
 	e.Root.Execute(cancelCtx)
 }
 
+func (e *DashboardExecutionTree) setRunContext(ctx context.Context) {
+	e.runCtxLock.Lock()
+	defer e.runCtxLock.Unlock()
+	e.runCtx = ctx
+}
+
+func (e *DashboardExecutionTree) getRunContext() context.Context {
+	e.runCtxLock.Lock()
+	defer e.runCtxLock.Unlock()
+	return e.runCtx
+}
+
+// LoadContainer executes the children of the lazy container with the given name
+// the children are executed with the context of the execution, so they are cancelled if the execution is cancelled
+func (e *DashboardExecutionTree) LoadContainer(containerName string) error {
+	ctx := e.getRunContext()
+	if ctx == nil {
+		return fmt.Errorf("cannot load container '%s' - execution of %s has not started", containerName, e.dashboardName)
+	}
+	run, ok := e.runs[containerName]
+	if !ok {
+		return fmt.Errorf("container '%s' is not part of the execution of %s", containerName, e.dashboardName)
+	}
+	containerRun, ok := run.(*DashboardContainerRun)
+	if !ok {
+		return fmt.Errorf("'%s' is not a container", containerName)
+	}
+	return containerRun.Load(ctx)
+}
+
 // GetRunStatus returns the stats of the Root run
 func (e *DashboardExecutionTree) GetRunStatus() dashboardtypes.RunStatus {
 	return e.Root.GetRunStatus()
@@ -132,12 +132,22 @@ func (r *DashboardParentImpl) ChildStatusChanged(ctx context.Context) {
 		log.Printf("[TRACE] %s ChildStatusChanged - calling setRunning to see if we are still running, status %s blockedByChild %v", r.Name, r.GetRunStatus(), r.blockedByChild)
 
 		// try setting our status to running again
-		r.setRunning(ctx)
+		r.updateRunningStatus(ctx)
 	}
 }
 
 // override DashboardTreeRunImpl) setStatus(
 func (r *DashboardParentImpl) setRunning(ctx context.Context) {
+	// children may be changing status asynchronously, so serialise with ChildStatusChanged
+	r.childStatusLock.Lock()
+	defer r.childStatusLock.Unlock()
+
+	r.updateRunningStatus(ctx)
+}
+
+// updateRunningStatus sets our status to running, or blocked if any of our children are blocked
+// NOTE: the caller must hold childStatusLock
+func (r *DashboardParentImpl) updateRunningStatus(ctx context.Context) {
 	// if the run is already complete (for example, canceled), do nothing
 	if r.GetRunStatus().IsFinished() {
 		log.Printf("[TRACE] %s setRunning - run already terminated - current state %s - NOT setting running", r.Name, r.GetRunStatus())
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/spf13/viper"
+	typehelpers "github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/dashboard/dashboardtypes"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 )
@@ -13,6 +16,9 @@ import (
 type DashboardRun struct {
 	runtimeDependencyPublisherImpl
 
+	// the interval at which the UI should refresh the dashboard, if any
+	AutoRefresh string `json:"auto_refresh,omitempty"`
+
 	parent    dashboardtypes.DashboardParent
 	dashboard *modconfig.Dashboard
 }
@@ -36,12 +42,17 @@ func (r *DashboardRun) AsTreeNode() *dashboardtypes.SnapshotTreeNode {
 
 func NewDashboardRun(dashboard *modconfig.Dashboard, parent dashboardtypes.DashboardParent, executionTree *DashboardExecutionTree) (*DashboardRun, error) {
 	r := &DashboardRun{
-		parent:    parent,
-		dashboard: dashboard,
+		AutoRefresh: typehelpers.SafeString(dashboard.AutoRefresh),
+		parent:      parent,
+		dashboard:   dashboard,
 	}
 	// create RuntimeDependencyPublisherImpl- this handles 'with' run creation and resolving runtime dependency resolution
 	// (we must create after creating the run as it requires a ref to the run)
 	r.runtimeDependencyPublisherImpl = newRuntimeDependencyPublisherImpl(dashboard, parent, r, executionTree)
+	// resolve the title and description for the selected locale (if any)
+	locale := viper.GetString(constants.ArgLocale)
+	r.Title = dashboard.GetLocalisedTitle(locale)
+	r.Description = dashboard.GetLocalisedDescription(locale)
 	// add r into execution tree BEFORE creating child runs or initialising runtime depdencies
 	// - this is so child runs can find this dashboard run
 	executionTree.runs[r.Name] = r
@@ -104,13 +115,7 @@ func (r *DashboardRun) GetInput(name string) (*modconfig.DashboardInput, bool) {
 
 // GetInputsDependingOn returns a list o DashboardInputs which have a runtime dependency on the given input
 func (r *DashboardRun) GetInputsDependingOn(changedInputName string) []string {
-	var res []string
-	for _, input := range r.dashboard.Inputs {
-		if input.DependsOnInput(changedInputName) {
-			res = append(res, input.UnqualifiedName)
-		}
-	}
-	return res
+	return r.dashboard.GetInputsDependingOn(changedInputName)
 }
 
 func (r *DashboardRun) createChildRuns(executionTree *DashboardExecutionTree) error {
@@ -118,8 +123,16 @@ func (r *DashboardRun) createChildRuns(executionTree *DashboardExecutionTree) er
 	children := r.dashboard.GetChildren()
 
 	for _, child := range children {
+		// if the child has a condition which evaluates false, exclude it
+		include, err := executionTree.evaluateCondition(child)
+		if err != nil {
+			return err
+		}
+		if !include {
+			continue
+		}
+
 		var childRun dashboardtypes.DashboardTreeRun
-		var err error
 		switch i := child.(type) {
 		case *modconfig.DashboardWith:
 			// ignore as with runs are created by RuntimeDependencyPublisherImpl
@@ -7,6 +7,7 @@ import (
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 	"log"
+	"sync"
 )
 
 type DashboardTreeRunImpl struct {
@@ -31,6 +32,8 @@ type DashboardTreeRunImpl struct {
 	// store the top level run which embeds this struct
 	// we need this for setStatus which serialises the run for the message payload
 	run dashboardtypes.DashboardTreeRun
+	// the status is set by the run and read asynchronously by its parent
+	statusLock *sync.RWMutex
 }
 
 func NewDashboardTreeRunImpl(resource modconfig.DashboardLeafNode, parent dashboardtypes.DashboardParent, run dashboardtypes.DashboardTreeRun, executionTree *DashboardExecutionTree) DashboardTreeRunImpl {
@@ -56,6 +59,7 @@ func NewDashboardTreeRunImpl(resource modconfig.DashboardLeafNode, parent dashbo
 		executionTree: executionTree,
 		resource:      resource,
 		run:           run,
+		statusLock:    new(sync.RWMutex),
 	}
 
 	// TACTICAL if this run was created to create a snapshot output for a control run,
@@ -76,6 +80,8 @@ func (r *DashboardTreeRunImpl) GetName() string {
 
 // GetRunStatus implements DashboardTreeRun
 func (r *DashboardTreeRunImpl) GetRunStatus() dashboardtypes.RunStatus {
+	r.statusLock.RLock()
+	defer r.statusLock.RUnlock()
 	return r.Status
 }
 
@@ -86,7 +92,7 @@ func (r *DashboardTreeRunImpl) GetError() error {
 
 // RunComplete implements DashboardTreeRun
 func (r *DashboardTreeRunImpl) RunComplete() bool {
-	return r.Status.IsFinished()
+	return r.GetRunStatus().IsFinished()
 }
 
 // GetInputsDependingOn implements DashboardTreeRun
@@ -153,7 +159,9 @@ func (r *DashboardTreeRunImpl) SetComplete(ctx context.Context) {
 }
 
 func (r *DashboardTreeRunImpl) setStatus(ctx context.Context, status dashboardtypes.RunStatus) {
+	r.statusLock.Lock()
 	r.Status = status
+	r.statusLock.Unlock()
 	// notify our parent that our status has changed
 	r.parent.ChildStatusChanged(ctx)
 
@@ -57,8 +57,11 @@ func (e *DashboardExecutor) ExecuteDashboard(ctx context.Context, sessionId, das
 	// reset any existing executions for this session
 	e.CancelExecutionForSession(ctx, sessionId)
 
+	// populate any inputs which have not been provided from their defaults
+	inputs = resolveDefaultInputValues(workspace, dashboardName, inputs)
+
 	// now create a new execution
-	executionTree, err = NewDashboardExecutionTree(dashboardName, sessionId, client, workspace)
+	executionTree, err = NewDashboardExecutionTree(dashboardName, sessionId, client, workspace, inputs, e.interactive)
 	if err != nil {
 		return err
 	}
@@ -82,6 +85,16 @@ func (e *DashboardExecutor) ExecuteDashboard(ctx context.Context, sessionId, das
 	return nil
 }
 
+// resolveDefaultInputValues returns the input values for the execution of the named dashboard,
+// using the input defaults for any inputs which have no value
+func resolveDefaultInputValues(workspace *workspace.Workspace, dashboardName string, inputs map[string]any) map[string]any {
+	dashboard, ok := workspace.GetResourceMaps().Dashboards[dashboardName]
+	if !ok {
+		return inputs
+	}
+	return dashboard.ResolveDefaultInputValues(inputs)
+}
+
 // if inputs must be provided before execution (i.e. this is a batch dashboard execution),
 // verify all required inputs are provided
 func (e *DashboardExecutor) validateInputs(executionTree *DashboardExecutionTree, inputs map[string]any) error {
@@ -152,7 +165,9 @@ func (e *DashboardExecutor) OnInputChanged(ctx context.Context, sessionId string
 	}
 	// if there are any dependent inputs, set their value to nil and send an event to the UI
 	// if the dashboard run is complete, just re-execute
-	if executionTree.GetRunStatus().IsFinished() || inputPrevValue != nil {
+	// also re-execute if the input is referenced by the condition of a dashboard child,
+	// as the children included in the execution tree may change
+	if executionTree.GetRunStatus().IsFinished() || inputPrevValue != nil || executionTree.hasConditionDependingOn(changedInput) {
 		return e.ExecuteDashboard(
 			ctx,
 			sessionId,
@@ -168,6 +183,15 @@ func (e *DashboardExecutor) OnInputChanged(ctx context.Context, sessionId string
 	return nil
 }
 
+// LoadContainer executes the panels of a lazy container in the dashboard running for the given session
+func (e *DashboardExecutor) LoadContainer(sessionId string, containerName string) error {
+	executionTree, found := e.getExecution(sessionId)
+	if !found {
+		return fmt.Errorf("no dashboard running for session %s", sessionId)
+	}
+	return executionTree.LoadContainer(containerName)
+}
+
 func (e *DashboardExecutor) clearDependentInputs(root dashboardtypes.DashboardTreeRun, changedInput string, inputs map[string]any) []string {
 	dependentInputs := root.GetInputsDependingOn(changedInput)
 	clearedInputs := dependentInputs
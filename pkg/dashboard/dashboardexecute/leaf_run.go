@@ -110,7 +110,7 @@ func (r *LeafRun) Execute(ctx context.Context) {
 	}()
 
 	// if there is nothing to do, return
-	if r.Status.IsFinished() {
+	if r.RunComplete() {
 		return
 	}
 
@@ -354,8 +354,9 @@ func (s *Server) handleMessageFunc(ctx context.Context) func(session *melody.Ses
 			}
 			_ = session.Write(payload)
 		case "select_dashboard":
-			s.setDashboardForSession(sessionId, request.Payload.Dashboard.FullName, request.Payload.InputValues)
-			_ = dashboardexecute.Executor.ExecuteDashboard(ctx, sessionId, request.Payload.Dashboard.FullName, request.Payload.InputValues, s.workspace, s.dbClient)
+			inputValues := s.resolveUrlParamInputValues(request.Payload)
+			s.setDashboardForSession(sessionId, request.Payload.Dashboard.FullName, inputValues)
+			_ = dashboardexecute.Executor.ExecuteDashboard(ctx, sessionId, request.Payload.Dashboard.FullName, inputValues, s.workspace, s.dbClient)
 		case "select_snapshot":
 			snapshotName := request.Payload.Dashboard.FullName
 			s.setDashboardForSession(sessionId, snapshotName, request.Payload.InputValues)
@@ -372,6 +373,14 @@ func (s *Server) handleMessageFunc(ctx context.Context) func(session *melody.Ses
 		case "input_changed":
 			s.setDashboardInputsForSession(sessionId, request.Payload.InputValues)
 			_ = dashboardexecute.Executor.OnInputChanged(ctx, sessionId, request.Payload.InputValues, request.Payload.ChangedInput)
+		case "load_container":
+			// load asynchronously - panel updates are sent as the panels complete
+			// NOTE: the panels are executed with the context of the dashboard execution, so they are cancelled with it
+			go func() {
+				if err := dashboardexecute.Executor.LoadContainer(sessionId, request.Payload.Container); err != nil {
+					log.Printf("[WARN] failed to load container %s: %s", request.Payload.Container, err.Error())
+				}
+			}()
 		case "clear_dashboard":
 			s.setDashboardInputsForSession(sessionId, nil)
 			dashboardexecute.Executor.CancelExecutionForSession(ctx, sessionId)
@@ -401,6 +410,18 @@ func (s *Server) addSession(session *melody.Session) {
 	s.addDashboardClient(sessionId, clientSession)
 }
 
+// if the request provides URL query parameters, use these to populate any inputs which specify a url_param
+func (s *Server) resolveUrlParamInputValues(payload ClientRequestPayload) map[string]any {
+	if len(payload.UrlParams) == 0 {
+		return payload.InputValues
+	}
+	dashboard, ok := s.workspace.GetResourceMaps().Dashboards[payload.Dashboard.FullName]
+	if !ok {
+		return payload.InputValues
+	}
+	return dashboard.ResolveUrlParamInputValues(payload.InputValues, payload.UrlParams)
+}
+
 func (s *Server) setDashboardInputsForSession(sessionId string, inputs map[string]interface{}) {
 	dashboardClients := s.getDashboardClients()
 	if sessionInfo, ok := dashboardClients[sessionId]; ok {
@@ -116,6 +116,10 @@ type ClientRequestPayload struct {
 	Dashboard    ClientRequestDashboardPayload `json:"dashboard"`
 	InputValues  map[string]interface{}        `json:"input_values"`
 	ChangedInput string                        `json:"changed_input"`
+	// URL query parameters used to populate inputs which specify a url_param
+	UrlParams map[string]string `json:"url_params,omitempty"`
+	// the name of the lazy container to load
+	Container string `json:"container,omitempty"`
 }
 
 type ClientRequest struct {
@@ -36,3 +36,32 @@ func GetMissingSchemaFromIsRelationNotFoundError(err error) (string, string, boo
 	}
 	return "", "", true
 }
+
+// transientErrorCodes are the postgres error codes of query failures which may succeed if the query is retried
+// in the same session
+// NOTE: connection errors are not included - after a connection error the session cannot be used to retry the query
+var transientErrorCodes = map[string]struct{}{
+	"40001": {}, // serialization_failure
+	"40P01": {}, // deadlock_detected
+	"55P03": {}, // lock_not_available
+}
+
+// fdwErrorCode is the postgres error code used by the FDW to report plugin errors
+const fdwErrorCode = "HV000"
+
+// rateLimitErrorRegex matches the messages of plugin errors caused by the plugin being rate limited (throttled) by its API
+var rateLimitErrorRegex = regexp.MustCompile(`(?i)rate exceeded|rate limit|throttl|too many requests|\b429\b`)
+
+// IsTransientError returns whether the error is a postgres error with one of the known transient error codes,
+// or a plugin error caused by rate limiting
+func IsTransientError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	if pgErr.Code == fdwErrorCode {
+		return rateLimitErrorRegex.MatchString(pgErr.Message)
+	}
+	_, ok := transientErrorCodes[pgErr.Code]
+	return ok
+}
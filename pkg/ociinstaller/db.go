@@ -40,17 +40,15 @@ func InstallDB(ctx context.Context, dblocation string) (string, error) {
 
 func updateVersionFileDB(image *SteampipeImage) error {
 	timeNow := versionfile.FormatTime(time.Now())
-	v, err := versionfile.LoadDatabaseVersionFile()
-	if err != nil {
-		return err
-	}
-	v.EmbeddedDB.Version = image.Config.Database.Version
-	v.EmbeddedDB.Name = "embeddedDB"
-	v.EmbeddedDB.ImageDigest = string(image.OCIDescriptor.Digest)
-	v.EmbeddedDB.InstalledFrom = image.ImageRef.requestedRef
-	v.EmbeddedDB.LastCheckedDate = timeNow
-	v.EmbeddedDB.InstallDate = timeNow
-	return v.Save()
+	return versionfile.UpdateDatabaseVersionFile(func(v *versionfile.DatabaseVersionFile) error {
+		v.EmbeddedDB.Version = image.Config.Database.Version
+		v.EmbeddedDB.Name = "embeddedDB"
+		v.EmbeddedDB.ImageDigest = string(image.OCIDescriptor.Digest)
+		v.EmbeddedDB.InstalledFrom = image.ImageRef.requestedRef
+		v.EmbeddedDB.LastCheckedDate = timeNow
+		v.EmbeddedDB.InstallDate = timeNow
+		return nil
+	})
 }
 
 func installDbFiles(image *SteampipeImage, tempDir string, dest string) error {
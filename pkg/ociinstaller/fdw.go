@@ -44,17 +44,15 @@ func InstallFdw(ctx context.Context, dbLocation string) (string, error) {
 
 func updateVersionFileFdw(image *SteampipeImage) error {
 	timeNow := versionfile.FormatTime(time.Now())
-	v, err := versionfile.LoadDatabaseVersionFile()
-	if err != nil {
-		return err
-	}
-	v.FdwExtension.Version = image.Config.Fdw.Version
-	v.FdwExtension.Name = "fdwExtension"
-	v.FdwExtension.ImageDigest = string(image.OCIDescriptor.Digest)
-	v.FdwExtension.InstalledFrom = image.ImageRef.requestedRef
-	v.FdwExtension.LastCheckedDate = timeNow
-	v.FdwExtension.InstallDate = timeNow
-	return v.Save()
+	return versionfile.UpdateDatabaseVersionFile(func(v *versionfile.DatabaseVersionFile) error {
+		v.FdwExtension.Version = image.Config.Fdw.Version
+		v.FdwExtension.Name = "fdwExtension"
+		v.FdwExtension.ImageDigest = string(image.OCIDescriptor.Digest)
+		v.FdwExtension.InstalledFrom = image.ImageRef.requestedRef
+		v.FdwExtension.LastCheckedDate = timeNow
+		v.FdwExtension.InstallDate = timeNow
+		return nil
+	})
 }
 
 func installFdwFiles(image *SteampipeImage, tempdir string) error {
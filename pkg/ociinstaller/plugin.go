@@ -75,41 +75,34 @@ func updatePluginVersionFiles(ctx context.Context, image *SteampipeImage, constr
 	defer versionFileUpdateLock.Unlock()
 
 	timeNow := versionfile.FormatTime(time.Now())
-	v, err := versionfile.LoadPluginVersionFile(ctx)
-	if err != nil {
-		return err
-	}
-
-	// For the full name we want the constraint (^0.4) used, not the resolved version (0.4.1)
-	// we override the DisplayImageRef with the constraint here.
-	pluginFullName := image.ImageRef.DisplayImageRefConstraintOverride(constraint)
-
-	installedVersion, ok := v.Plugins[pluginFullName]
-	if !ok {
-		installedVersion = versionfile.EmptyInstalledVersion()
-	}
-
-	installedVersion.Name = pluginFullName
-	installedVersion.Version = image.Config.Plugin.Version
-	installedVersion.ImageDigest = string(image.OCIDescriptor.Digest)
-	installedVersion.BinaryDigest = image.Plugin.BinaryDigest
-	installedVersion.BinaryArchitecture = image.Plugin.BinaryArchitecture
-	installedVersion.InstalledFrom = image.ImageRef.ActualImageRef()
-	installedVersion.LastCheckedDate = timeNow
-	installedVersion.InstallDate = timeNow
-
-	v.Plugins[pluginFullName] = installedVersion
-
-	// Ensure that the version file is written to the plugin installation folder
-	// Having this file is important, since this can be used
-	// to compose the global version file if it is unavailable or unparseable
-	// This makes sure that in the event of corruption (global/individual) we don't end up
-	// losing all the plugin install data
-	if err := v.EnsurePluginVersionFile(installedVersion); err != nil {
-		return err
-	}
+	return versionfile.UpdatePluginVersionFile(ctx, func(v *versionfile.PluginVersionFile) error {
+		// For the full name we want the constraint (^0.4) used, not the resolved version (0.4.1)
+		// we override the DisplayImageRef with the constraint here.
+		pluginFullName := image.ImageRef.DisplayImageRefConstraintOverride(constraint)
+
+		installedVersion, ok := v.Plugins[pluginFullName]
+		if !ok {
+			installedVersion = versionfile.EmptyInstalledVersion()
+		}
 
-	return v.Save()
+		installedVersion.Name = pluginFullName
+		installedVersion.Version = image.Config.Plugin.Version
+		installedVersion.ImageDigest = string(image.OCIDescriptor.Digest)
+		installedVersion.BinaryDigest = image.Plugin.BinaryDigest
+		installedVersion.BinaryArchitecture = image.Plugin.BinaryArchitecture
+		installedVersion.InstalledFrom = image.ImageRef.ActualImageRef()
+		installedVersion.LastCheckedDate = timeNow
+		installedVersion.InstallDate = timeNow
+
+		v.Plugins[pluginFullName] = installedVersion
+
+		// Ensure that the version file is written to the plugin installation folder
+		// Having this file is important, since this can be used
+		// to compose the global version file if it is unavailable or unparseable
+		// This makes sure that in the event of corruption (global/individual) we don't end up
+		// losing all the plugin install data
+		return v.EnsurePluginVersionFile(installedVersion)
+	})
 }
 
 func installPluginBinary(image *SteampipeImage, tempDir string, destDir string) error {
@@ -35,8 +35,37 @@ func (s DatabaseVersionFile) IsValid() bool {
 // LoadDatabaseVersionFile migrates from the old version file format if necessary and loads the database version data
 func LoadDatabaseVersionFile() (*DatabaseVersionFile, error) {
 	versionFilePath := filepaths.DatabaseVersionFilePath()
-	if filehelpers.FileExists(versionFilePath) {
-		return readDatabaseVersionFile(versionFilePath)
+	var data *DatabaseVersionFile
+	err := withVersionFileLock(versionFilePath, func() (err error) {
+		data, err = loadDatabaseVersionFile(versionFilePath)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// UpdateDatabaseVersionFile loads the database version data, passes it to the update func and saves the result
+// the version file is locked for the duration, so the changes of concurrent steampipe processes are not lost
+// NOTE: the update func must not load or save the database version file
+func UpdateDatabaseVersionFile(update func(*DatabaseVersionFile) error) error {
+	versionFilePath := filepaths.DatabaseVersionFilePath()
+	return withVersionFileLock(versionFilePath, func() error {
+		data, err := loadDatabaseVersionFile(versionFilePath)
+		if err != nil {
+			return err
+		}
+		if err := update(data); err != nil {
+			return err
+		}
+		return data.write(versionFilePath)
+	})
+}
+
+func loadDatabaseVersionFile(path string) (*DatabaseVersionFile, error) {
+	if filehelpers.FileExists(path) {
+		return readDatabaseVersionFile(path)
 	}
 	return NewDBVersionFile(), nil
 }
@@ -60,21 +89,24 @@ func readDatabaseVersionFile(path string) (*DatabaseVersionFile, error) {
 }
 
 // Save writes the config
+// NOTE: to update the version file based on its current contents, use UpdateDatabaseVersionFile
 func (f *DatabaseVersionFile) Save() error {
-	// set the struct version
-	f.StructVersion = DatabaseStructVersion
-
 	versionFilePath := filepaths.DatabaseVersionFilePath()
-	return f.write(versionFilePath)
+	return withVersionFileLock(versionFilePath, func() error {
+		return f.write(versionFilePath)
+	})
 }
 
 func (f *DatabaseVersionFile) write(path string) error {
+	// set the struct version
+	f.StructVersion = DatabaseStructVersion
+
 	versionFileJSON, err := json.MarshalIndent(f, "", "  ")
 	if err != nil {
 		log.Println("[ERROR]", "Error while writing version file", err)
 		return err
 	}
-	return os.WriteFile(path, versionFileJSON, 0644)
+	return writeFileAtomic(path, versionFileJSON, 0644)
 }
 
 // FormatTime :: format time as RFC3339 in UTC
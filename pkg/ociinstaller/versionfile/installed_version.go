@@ -1,5 +1,7 @@
 package versionfile
 
+import "time"
+
 const InstalledVersionStructVersion = 20230502
 
 type InstalledVersion struct {
@@ -24,3 +26,47 @@ func EmptyInstalledVersion() *InstalledVersion {
 func (f *InstalledVersion) Equal(other *InstalledVersion) bool {
 	return f.Name == other.Name && f.BinaryDigest == other.BinaryDigest
 }
+
+// MergeNewer returns a new record merging this record with other
+// the record with the more recent LastCheckedDate (or InstallDate, if the LastCheckedDates are the same) is preferred,
+// with any digests it is missing taken from the other record
+// dates which cannot be parsed are treated as the oldest possible date
+func (f *InstalledVersion) MergeNewer(other *InstalledVersion) *InstalledVersion {
+	if other == nil {
+		res := *f
+		return &res
+	}
+	newer, older := f, other
+	if isNewer(other, f) {
+		newer, older = other, f
+	}
+
+	res := *newer
+	if res.ImageDigest == "" {
+		res.ImageDigest = older.ImageDigest
+	}
+	if res.BinaryDigest == "" {
+		res.BinaryDigest = older.BinaryDigest
+	}
+	return &res
+}
+
+// isNewer returns whether a was checked (or, failing that, installed) more recently than b
+func isNewer(a, b *InstalledVersion) bool {
+	aChecked, bChecked := parseTime(a.LastCheckedDate), parseTime(b.LastCheckedDate)
+	if !aChecked.Equal(bChecked) {
+		return aChecked.After(bChecked)
+	}
+	return parseTime(a.InstallDate).After(parseTime(b.InstallDate))
+}
+
+// parseTime parses a date written by FormatTime (or in UnixDate format)
+// returning the zero time if the date cannot be parsed
+func parseTime(date string) time.Time {
+	for _, layout := range []string{time.RFC3339, time.UnixDate} {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,54 @@
+package versionfile
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mergeNewerTest struct {
+	version  *InstalledVersion
+	other    *InstalledVersion
+	expected *InstalledVersion
+}
+
+var mergeNewerTime = time.Date(2023, 5, 2, 12, 0, 0, 0, time.UTC)
+
+var testCasesMergeNewer = map[string]mergeNewerTest{
+	"other checked more recently": {
+		version:  &InstalledVersion{Name: "aws", Version: "0.1.0", ImageDigest: "sha1", LastCheckedDate: FormatTime(mergeNewerTime), InstallDate: FormatTime(mergeNewerTime)},
+		other:    &InstalledVersion{Name: "aws", Version: "0.2.0", ImageDigest: "sha2", LastCheckedDate: FormatTime(mergeNewerTime.Add(time.Hour)), InstallDate: FormatTime(mergeNewerTime)},
+		expected: &InstalledVersion{Name: "aws", Version: "0.2.0", ImageDigest: "sha2", LastCheckedDate: FormatTime(mergeNewerTime.Add(time.Hour)), InstallDate: FormatTime(mergeNewerTime)},
+	},
+	"same checked date, newer install date": {
+		version:  &InstalledVersion{Name: "aws", Version: "0.2.0", LastCheckedDate: FormatTime(mergeNewerTime), InstallDate: FormatTime(mergeNewerTime)},
+		other:    &InstalledVersion{Name: "aws", Version: "0.1.0", LastCheckedDate: FormatTime(mergeNewerTime), InstallDate: FormatTime(mergeNewerTime.Add(-time.Hour))},
+		expected: &InstalledVersion{Name: "aws", Version: "0.2.0", LastCheckedDate: FormatTime(mergeNewerTime), InstallDate: FormatTime(mergeNewerTime)},
+	},
+	"newer record missing digests": {
+		version:  &InstalledVersion{Name: "aws", Version: "0.1.0", ImageDigest: "sha1", BinaryDigest: "bin1", LastCheckedDate: FormatTime(mergeNewerTime)},
+		other:    &InstalledVersion{Name: "aws", Version: "0.2.0", LastCheckedDate: FormatTime(mergeNewerTime.Add(time.Hour))},
+		expected: &InstalledVersion{Name: "aws", Version: "0.2.0", ImageDigest: "sha1", BinaryDigest: "bin1", LastCheckedDate: FormatTime(mergeNewerTime.Add(time.Hour))},
+	},
+	"unparseable date treated as oldest": {
+		version:  &InstalledVersion{Name: "aws", Version: "0.1.0", LastCheckedDate: "not a date"},
+		other:    &InstalledVersion{Name: "aws", Version: "0.2.0", LastCheckedDate: mergeNewerTime.Format(time.UnixDate)},
+		expected: &InstalledVersion{Name: "aws", Version: "0.2.0", LastCheckedDate: mergeNewerTime.Format(time.UnixDate)},
+	},
+	"nil other": {
+		version:  &InstalledVersion{Name: "aws", Version: "0.1.0"},
+		expected: &InstalledVersion{Name: "aws", Version: "0.1.0"},
+	},
+}
+
+func TestMergeNewer(t *testing.T) {
+	for name, test := range testCasesMergeNewer {
+		res := test.version.MergeNewer(test.other)
+		if !reflect.DeepEqual(res, test.expected) {
+			t.Errorf("Test %s FAILED. Expected %+v, got %+v", name, test.expected, res)
+		}
+		if res == test.version || res == test.other {
+			t.Errorf("Test %s FAILED. Expected a new record to be returned", name)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package versionfile
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+)
+
+const LockfileStructVersion = 20230601
+
+// lockfileEntry is the lockfile representation of an InstalledVersion
+// only the properties required to reproduce an installation are included
+type lockfileEntry struct {
+	Version     string `json:"version"`
+	ImageDigest string `json:"image_digest,omitempty"`
+}
+
+type lockfile struct {
+	// NOTE: encoding/json serialises map keys in sorted order, so the output is stable
+	Plugins       map[string]*lockfileEntry `json:"plugins"`
+	StructVersion int64                     `json:"struct_version"`
+}
+
+// WriteLockfile writes the name, version and digest of the given installed versions to a lockfile at the given path
+// nil versions are skipped
+func WriteLockfile(versions map[string]*InstalledVersion, path string) error {
+	l := &lockfile{
+		Plugins:       make(map[string]*lockfileEntry, len(versions)),
+		StructVersion: LockfileStructVersion,
+	}
+	for name, v := range versions {
+		if v == nil {
+			continue
+		}
+		l.Plugins[name] = &lockfileEntry{
+			Version:     v.Version,
+			ImageDigest: v.ImageDigest,
+		}
+	}
+
+	lockfileJSON, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	// add a trailing newline to keep diffs clean
+	lockfileJSON = append(lockfileJSON, '\n')
+	return os.WriteFile(path, lockfileJSON, 0644)
+}
+
+// ReadLockfile reads a lockfile written by WriteLockfile, returning a map of installed versions keyed by name
+func ReadLockfile(path string) (map[string]*InstalledVersion, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, sperr.WrapWithMessage(ErrNoContent, "lockfile '%s' is empty", path)
+	}
+
+	var l lockfile
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, sperr.WrapWithMessage(err, "failed to parse lockfile '%s'", path)
+	}
+
+	versions := make(map[string]*InstalledVersion, len(l.Plugins))
+	for name, entry := range l.Plugins {
+		if entry == nil {
+			continue
+		}
+		v := EmptyInstalledVersion()
+		v.Name = name
+		v.Version = entry.Version
+		v.ImageDigest = entry.ImageDigest
+		versions[name] = v
+	}
+	return versions, nil
+}
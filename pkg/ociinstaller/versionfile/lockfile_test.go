@@ -0,0 +1,101 @@
+package versionfile
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLockfileRoundTrip(t *testing.T) {
+	lockfilePath := filepath.Join(t.TempDir(), "plugins.lock")
+
+	versions := map[string]*InstalledVersion{
+		"hub.steampipe.io/plugins/turbot/gcp@latest": {
+			Name:            "hub.steampipe.io/plugins/turbot/gcp@latest",
+			Version:         "0.35.0",
+			ImageDigest:     "sha256:3211232123654987313216549876516351",
+			InstalledFrom:   "hub.steampipe.io/plugins/turbot/gcp:latest",
+			LastCheckedDate: time.Now().Format(time.UnixDate),
+			InstallDate:     time.Now().Format(time.UnixDate),
+			StructVersion:   InstalledVersionStructVersion,
+		},
+		"hub.steampipe.io/plugins/turbot/aws@latest": {
+			Name:            "hub.steampipe.io/plugins/turbot/aws@latest",
+			Version:         "0.101.0",
+			ImageDigest:     "sha256:88995cc15963225884b825b12409f798b24aa7364bbf35a83d3a5fb5db85f346",
+			InstalledFrom:   "hub.steampipe.io/plugins/turbot/aws:latest",
+			LastCheckedDate: time.Now().Format(time.UnixDate),
+			InstallDate:     time.Now().Format(time.UnixDate),
+			StructVersion:   InstalledVersionStructVersion,
+		},
+	}
+
+	if err := WriteLockfile(versions, lockfilePath); err != nil {
+		t.Fatalf("Error writing lockfile: %s", err.Error())
+	}
+
+	content, err := os.ReadFile(lockfilePath)
+	if err != nil {
+		t.Fatalf("Error reading lockfile: %s", err.Error())
+	}
+	// verify the output is sorted and excludes install metadata
+	if strings.Index(string(content), "turbot/aws") > strings.Index(string(content), "turbot/gcp") {
+		t.Errorf("Expected lockfile entries to be sorted by name")
+	}
+	if strings.Contains(string(content), "install_date") || strings.Contains(string(content), "installed_from") {
+		t.Errorf("Expected lockfile to exclude install metadata, got:\n%s", content)
+	}
+
+	// verify writing the same data again gives identical output
+	if err := WriteLockfile(versions, lockfilePath); err != nil {
+		t.Fatalf("Error writing lockfile: %s", err.Error())
+	}
+	if content2, _ := os.ReadFile(lockfilePath); string(content2) != string(content) {
+		t.Errorf("Expected lockfile output to be stable")
+	}
+
+	read, err := ReadLockfile(lockfilePath)
+	if err != nil {
+		t.Fatalf("Error reading lockfile: %s", err.Error())
+	}
+	for name, v := range versions {
+		expected := &InstalledVersion{
+			Name:          v.Name,
+			Version:       v.Version,
+			ImageDigest:   v.ImageDigest,
+			StructVersion: InstalledVersionStructVersion,
+		}
+		if !reflect.DeepEqual(read[name], expected) {
+			t.Errorf("Expected %+v for %s, got %+v", expected, name, read[name])
+		}
+	}
+	if len(read) != len(versions) {
+		t.Errorf("Expected %d plugins, found %d", len(versions), len(read))
+	}
+}
+
+func TestWriteLockfileSkipsNilVersions(t *testing.T) {
+	lockfilePath := filepath.Join(t.TempDir(), "plugins.lock")
+
+	versions := map[string]*InstalledVersion{
+		"hub.steampipe.io/plugins/turbot/aws@latest": {
+			Name:    "hub.steampipe.io/plugins/turbot/aws@latest",
+			Version: "0.101.0",
+		},
+		"hub.steampipe.io/plugins/turbot/gcp@latest": nil,
+	}
+
+	if err := WriteLockfile(versions, lockfilePath); err != nil {
+		t.Fatalf("Error writing lockfile: %s", err.Error())
+	}
+	read, err := ReadLockfile(lockfilePath)
+	if err != nil {
+		t.Fatalf("Error reading lockfile: %s", err.Error())
+	}
+	if len(read) != 1 || read["hub.steampipe.io/plugins/turbot/aws@latest"] == nil {
+		t.Errorf("Expected only the aws plugin in the lockfile, got %+v", read)
+	}
+}
@@ -68,18 +68,21 @@ func (p *PluginVersionFile) EnsurePluginVersionFile(installData *InstalledVersio
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(versionFile, theBytes, 0644)
+	return writeFileAtomic(versionFile, theBytes, 0644)
 }
 
 // Save writes the config file to disk
+// NOTE: to update the version file based on its current contents, use UpdatePluginVersionFile
 func (p *PluginVersionFile) Save() error {
-	// set struct version
-	p.StructVersion = PluginStructVersion
 	versionFilePath := filepaths.PluginVersionFilePath()
-	return p.write(versionFilePath)
+	return withVersionFileLock(versionFilePath, func() error {
+		return p.write(versionFilePath)
+	})
 }
 
 func (p *PluginVersionFile) write(path string) error {
+	// set struct version
+	p.StructVersion = PluginStructVersion
 	versionFileJSON, err := json.MarshalIndent(p, "", "  ")
 	if err != nil {
 		log.Println("[ERROR]", "Error while writing version file", err)
@@ -89,7 +92,7 @@ func (p *PluginVersionFile) write(path string) error {
 		log.Println("[ERROR]", "Cannot write 0 bytes to file")
 		return sperr.WrapWithMessage(ErrNoContent, "cannot write versions file")
 	}
-	return os.WriteFile(path, versionFileJSON, 0644)
+	return writeFileAtomic(path, versionFileJSON, 0644)
 }
 
 func (p *PluginVersionFile) ensureVersionFilesInPluginDirectories() error {
@@ -143,6 +146,40 @@ func LoadPluginVersionFile(ctx context.Context) (*PluginVersionFile, error) {
 	defer pluginLoadLock.Unlock()
 
 	versionFilePath := filepaths.PluginVersionFilePath()
+	var pluginVersions *PluginVersionFile
+	err := withVersionFileLock(versionFilePath, func() (err error) {
+		pluginVersions, err = loadPluginVersionFile(ctx, versionFilePath)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pluginVersions, nil
+}
+
+// UpdatePluginVersionFile loads the plugin version data, passes it to the update func and saves the result
+// the version file is locked for the duration, so the changes of concurrent steampipe processes are not lost
+// NOTE: the update func must not load or save the plugin version file
+func UpdatePluginVersionFile(ctx context.Context, update func(*PluginVersionFile) error) error {
+	pluginLoadLock.Lock()
+	defer pluginLoadLock.Unlock()
+
+	versionFilePath := filepaths.PluginVersionFilePath()
+	return withVersionFileLock(versionFilePath, func() error {
+		pluginVersions, err := loadPluginVersionFile(ctx, versionFilePath)
+		if err != nil {
+			return err
+		}
+		if err := update(pluginVersions); err != nil {
+			return err
+		}
+		return pluginVersions.write(versionFilePath)
+	})
+}
+
+// loadPluginVersionFile loads the plugin version data from the given path, recomposing it if necessary
+// NOTE: the caller must hold the version file lock
+func loadPluginVersionFile(ctx context.Context, versionFilePath string) (*PluginVersionFile, error) {
 	if filehelpers.FileExists(versionFilePath) {
 		pluginVersions, err := readGlobalPluginVersionsFile(versionFilePath)
 
@@ -158,7 +195,7 @@ func LoadPluginVersionFile(ctx context.Context) (*PluginVersionFile, error) {
 	pluginVersions := recomposePluginVersionFile(ctx)
 
 	// save the recomposed file
-	err := pluginVersions.Save()
+	err := pluginVersions.write(versionFilePath)
 	if err != nil {
 		return nil, err
 	}
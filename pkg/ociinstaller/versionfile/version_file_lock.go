@@ -0,0 +1,57 @@
+package versionfile
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+)
+
+// withVersionFileLock runs f while holding an exclusive OS level lock for the version file at the given path
+// the CLI, service and plugin manager may all read and write the version files, so the lock is shared between processes
+// NOTE: the lock is taken on a separate '.lock' file, as the version file itself is replaced on every write
+// NOTE: the lock is not reentrant - f must not call any function which takes the lock for the same path
+func withVersionFileLock(path string, f func() error) error {
+	lock, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return sperr.WrapWithMessage(err, "failed to open lock file for '%s'", path)
+	}
+	defer lock.Close()
+
+	if err := lockFile(lock); err != nil {
+		return sperr.WrapWithMessage(err, "failed to lock '%s'", path)
+	}
+	defer unlockFile(lock)
+
+	return f()
+}
+
+// writeFileAtomic writes the data to a temp file in the same directory as the target path, then renames it over the target
+// this ensures readers only ever see the complete old or new contents of the file, never a partial write
+func writeFileAtomic(path string, data []byte, perm os.FileMode) (err error) {
+	tempFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// if anything fails, remove the temp file
+	defer func() {
+		if err != nil {
+			tempFile.Close()
+			os.Remove(tempFile.Name())
+		}
+	}()
+
+	if _, err = tempFile.Write(data); err != nil {
+		return err
+	}
+	if err = tempFile.Sync(); err != nil {
+		return err
+	}
+	if err = tempFile.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tempFile.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tempFile.Name(), path)
+}
@@ -0,0 +1,80 @@
+package versionfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentPluginVersionFileWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "versions.json")
+	writers := 20
+
+	// each writer adds a plugin to the version file, and a reader continually reads the file while they do so
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	readErrors := make(chan error, 1)
+	go func() {
+		for {
+			select {
+			case <-done:
+				close(readErrors)
+				return
+			default:
+			}
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			if _, err := readGlobalPluginVersionsFile(path); err != nil {
+				readErrors <- err
+				close(readErrors)
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := withVersionFileLock(path, func() error {
+				v := newPluginVersionFile()
+				if _, err := os.Stat(path); err == nil {
+					existing, err := readGlobalPluginVersionsFile(path)
+					if err != nil {
+						return err
+					}
+					v = existing
+				}
+				name := fmt.Sprintf("hub.steampipe.io/plugins/turbot/plugin%d@latest", i)
+				v.Plugins[name] = &InstalledVersion{Name: name, Version: "1.0.0"}
+				return v.write(path)
+			})
+			if err != nil {
+				t.Errorf("Test FAILED. Expected no error, got %s", err.Error())
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(done)
+
+	if err := <-readErrors; err != nil {
+		t.Errorf("Test FAILED. Expected the version file to always be readable, got %s", err.Error())
+	}
+
+	v, err := readGlobalPluginVersionsFile(path)
+	if err != nil {
+		t.Fatalf("Test FAILED. Expected the version file to be readable, got %s", err.Error())
+	}
+	if len(v.Plugins) != writers {
+		t.Errorf("Test FAILED. Expected %d plugins, got %d", writers, len(v.Plugins))
+	}
+
+	// no temp files should be left behind
+	tempFiles, _ := filepath.Glob(path + ".*.tmp")
+	if len(tempFiles) > 0 {
+		t.Errorf("Test FAILED. Expected no temp files, got %v", tempFiles)
+	}
+}
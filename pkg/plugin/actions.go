@@ -40,12 +40,10 @@ func Remove(ctx context.Context, image string, pluginConnections map[string][]*m
 	}
 
 	// update the version file
-	v, err := versionfile.LoadPluginVersionFile(ctx)
-	if err != nil {
-		return nil, err
-	}
-	delete(v.Plugins, fullPluginName)
-	err = v.Save()
+	err = versionfile.UpdatePluginVersionFile(ctx, func(v *versionfile.PluginVersionFile) error {
+		delete(v.Plugins, fullPluginName)
+		return nil
+	})
 
 	return &steampipeconfig.PluginRemoveReport{Connections: conns, Image: imageRef}, err
 }
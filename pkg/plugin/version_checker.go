@@ -80,13 +80,6 @@ func GetAllUpdateReport(ctx context.Context, installationID string) map[string]V
 }
 
 func (v *VersionChecker) reportPluginUpdates(ctx context.Context) map[string]VersionCheckReport {
-	// retrieve the plugin version data from steampipe config
-	versionFileData, err := versionfile.LoadPluginVersionFile(ctx)
-	if err != nil {
-		log.Printf("[TRACE] reportPluginUpdates could not load version file: %s", err.Error())
-		return nil
-	}
-
 	if len(v.pluginsToCheck) == 0 {
 		// there's no plugin installed. no point continuing
 		return nil
@@ -104,11 +97,16 @@ func (v *VersionChecker) reportPluginUpdates(ctx context.Context) map[string]Ver
 	}
 
 	// update the version file
-	for _, plugin := range v.pluginsToCheck {
-		versionFileData.Plugins[plugin.Name].LastCheckedDate = versionfile.FormatTime(time.Now())
-	}
-
-	if err = versionFileData.Save(); err != nil {
+	err := versionfile.UpdatePluginVersionFile(ctx, func(versionFileData *versionfile.PluginVersionFile) error {
+		for _, plugin := range v.pluginsToCheck {
+			// the plugin may have been removed by another process since it was checked
+			if installed, ok := versionFileData.Plugins[plugin.Name]; ok {
+				installed.LastCheckedDate = versionfile.FormatTime(time.Now())
+			}
+		}
+		return nil
+	})
+	if err != nil {
 		log.Printf("[WARN] reportPluginUpdates could not save version file: %s", err.Error())
 		return nil
 	}
@@ -0,0 +1,31 @@
+package steampipeconfig
+
+import (
+	"sort"
+
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// FindResourcesByTag returns the resources of the mod which have a tag with the given key and value, sorted by name
+// if value is empty, resources with any value for the key are returned
+func FindResourcesByTag(mod *modconfig.Mod, key, value string) []modconfig.ResourceWithMetadata {
+	res := []modconfig.ResourceWithMetadata{}
+	if mod == nil || mod.ResourceMaps == nil {
+		return res
+	}
+	// the walk function never returns an error
+	_ = mod.ResourceMaps.WalkResources(func(item modconfig.HclResource) (bool, error) {
+		resource, ok := item.(modconfig.ResourceWithMetadata)
+		if !ok {
+			return true, nil
+		}
+		if tagValue, ok := item.GetTags()[key]; ok && (value == "" || tagValue == value) {
+			res = append(res, resource)
+		}
+		return true, nil
+	})
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name() < res[j].Name()
+	})
+	return res
+}
@@ -0,0 +1,62 @@
+package steampipeconfig
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+type findResourcesByTagTest struct {
+	key      string
+	value    string
+	expected []string
+}
+
+var testCasesFindResourcesByTag = map[string]findResourcesByTagTest{
+	"key and value": {
+		key:      "service",
+		value:    "s3",
+		expected: []string{"test.benchmark.b1", "test.control.c1"},
+	},
+	"any value": {
+		key:      "service",
+		expected: []string{"test.benchmark.b1", "test.control.c1", "test.control.c2"},
+	},
+	"no match": {
+		key:      "service",
+		value:    "ec2",
+		expected: []string{},
+	},
+	"unknown key": {
+		key:      "owner",
+		expected: []string{},
+	},
+}
+
+func TestFindResourcesByTag(t *testing.T) {
+	mod := modconfig.NewMod("test", "", hcl.Range{})
+	c1 := modconfig.NewControl(&hcl.Block{Type: modconfig.BlockTypeControl, Labels: []string{"c1"}}, mod, "c1").(*modconfig.Control)
+	c1.Tags = map[string]string{"service": "s3"}
+	c2 := modconfig.NewControl(&hcl.Block{Type: modconfig.BlockTypeControl, Labels: []string{"c2"}}, mod, "c2").(*modconfig.Control)
+	c2.Tags = map[string]string{"service": "iam"}
+	c3 := modconfig.NewControl(&hcl.Block{Type: modconfig.BlockTypeControl, Labels: []string{"c3"}}, mod, "c3").(*modconfig.Control)
+	b1 := modconfig.NewBenchmark(&hcl.Block{Type: modconfig.BlockTypeBenchmark, Labels: []string{"b1"}}, mod, "b1").(*modconfig.Benchmark)
+	b1.Tags = map[string]string{"service": "s3"}
+	for _, r := range []modconfig.HclResource{c1, c2, c3, b1} {
+		if diags := mod.AddResource(r); diags.HasErrors() {
+			t.Fatalf("failed to add resource %s: %s", r.Name(), diags.Error())
+		}
+	}
+
+	for name, test := range testCasesFindResourcesByTag {
+		names := []string{}
+		for _, r := range FindResourcesByTag(mod, test.key, test.value) {
+			names = append(names, r.Name())
+		}
+		if !reflect.DeepEqual(names, test.expected) {
+			t.Errorf("Test %s FAILED. Expected %v, got %v", name, test.expected, names)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package steampipeconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// ModContentHash returns a stable digest of the definitions of the mod and all its resources
+// the digest is insensitive to the order (and location) in which resources are declared,
+// but changes if the definition of any resource changes
+func ModContentHash(mod *modconfig.Mod) string {
+	if mod == nil {
+		return ""
+	}
+	resources := modResourceMap(mod)
+	resources[mod.Name()] = mod
+
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("%s:%s\n", name, modconfig.ResourceContentHash(resources[name])))
+	}
+	return helpers.GetMD5Hash(sb.String())
+}
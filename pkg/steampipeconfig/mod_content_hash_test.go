@@ -0,0 +1,85 @@
+package steampipeconfig
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// modContentHashTestControl defines a control, and the line it is declared on
+type modContentHashTestControl struct {
+	name  string
+	title string
+	sql   string
+	line  int
+}
+
+type modContentHashTest struct {
+	a             []modContentHashTestControl
+	b             []modContentHashTestControl
+	expectedEqual bool
+}
+
+var testCasesModContentHash = map[string]modContentHashTest{
+	"identical": {
+		a:             []modContentHashTestControl{{"c1", "control 1", "select 1", 1}, {"c2", "control 2", "select 2", 5}},
+		b:             []modContentHashTestControl{{"c1", "control 1", "select 1", 1}, {"c2", "control 2", "select 2", 5}},
+		expectedEqual: true,
+	},
+	"reordered": {
+		a:             []modContentHashTestControl{{"c1", "control 1", "select 1", 1}, {"c2", "control 2", "select 2", 5}},
+		b:             []modContentHashTestControl{{"c2", "control 2", "select 2", 1}, {"c1", "control 1", "select 1", 5}},
+		expectedEqual: true,
+	},
+	"title changed": {
+		a:             []modContentHashTestControl{{"c1", "control 1", "select 1", 1}, {"c2", "control 2", "select 2", 5}},
+		b:             []modContentHashTestControl{{"c1", "control 1", "select 1", 1}, {"c2", "control 2 updated", "select 2", 5}},
+		expectedEqual: false,
+	},
+	"sql changed": {
+		a:             []modContentHashTestControl{{"c1", "control 1", "select 1", 1}},
+		b:             []modContentHashTestControl{{"c1", "control 1", "select 11", 1}},
+		expectedEqual: false,
+	},
+	"control added": {
+		a:             []modContentHashTestControl{{"c1", "control 1", "select 1", 1}},
+		b:             []modContentHashTestControl{{"c1", "control 1", "select 1", 1}, {"c2", "control 2", "select 2", 5}},
+		expectedEqual: false,
+	},
+	"control renamed": {
+		a:             []modContentHashTestControl{{"c1", "control 1", "select 1", 1}},
+		b:             []modContentHashTestControl{{"c2", "control 1", "select 1", 1}},
+		expectedEqual: false,
+	},
+}
+
+func TestModContentHash(t *testing.T) {
+	for name, test := range testCasesModContentHash {
+		hashA := ModContentHash(newModContentHashTestMod(t, test.a))
+		hashB := ModContentHash(newModContentHashTestMod(t, test.b))
+		if (hashA == hashB) != test.expectedEqual {
+			t.Errorf("Test %s FAILED. Expected hashes equal: %v, got %s and %s", name, test.expectedEqual, hashA, hashB)
+		}
+		// the hash must be stable
+		if rehashA := ModContentHash(newModContentHashTestMod(t, test.a)); rehashA != hashA {
+			t.Errorf("Test %s FAILED. Expected stable hash %s, got %s", name, hashA, rehashA)
+		}
+	}
+}
+
+func newModContentHashTestMod(t *testing.T, controls []modContentHashTestControl) *modconfig.Mod {
+	mod := modconfig.NewMod("test", "", hcl.Range{})
+	for _, c := range controls {
+		declRange := hcl.Range{Filename: "controls.sp", Start: hcl.Pos{Line: c.line}, End: hcl.Pos{Line: c.line + 3}}
+		block := &hcl.Block{Type: modconfig.BlockTypeControl, Labels: []string{c.name}, DefRange: declRange}
+		control := modconfig.NewControl(block, mod, c.name).(*modconfig.Control)
+		control.Title = utils.ToStringPointer(c.title)
+		control.SQL = utils.ToStringPointer(c.sql)
+		if diags := mod.AddResource(control); diags.HasErrors() {
+			t.Fatalf("failed to add resource %s: %s", control.Name(), diags.Error())
+		}
+	}
+	return mod
+}
@@ -0,0 +1,131 @@
+package steampipeconfig
+
+import (
+	"sort"
+
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// ModDiff is a struct representing the structural differences between 2 mods
+type ModDiff struct {
+	// the names of the resources which exist only in the new mod
+	Added []string
+	// the names of the resources which exist only in the old mod
+	Removed []string
+	// the property changes of resources which exist in both mods
+	Changed []*modconfig.DashboardTreeItemDiffs
+}
+
+func (d *ModDiff) HasChanges() bool {
+	return len(d.Added)+len(d.Removed)+len(d.Changed) > 0
+}
+
+// DiffMods returns the resources added, removed and changed between the mods a (old) and b (new)
+// changed resources are detected using the Diff function of each resource type
+// NOTE: changes to resources of a type with no Diff function are not reported
+func DiffMods(a, b *modconfig.Mod) *ModDiff {
+	res := &ModDiff{
+		Added:   []string{},
+		Removed: []string{},
+		Changed: []*modconfig.DashboardTreeItemDiffs{},
+	}
+	aResources := modResourceMap(a)
+	bResources := modResourceMap(b)
+
+	for name, aResource := range aResources {
+		bResource, ok := bResources[name]
+		if !ok {
+			res.Removed = append(res.Removed, name)
+			continue
+		}
+		if diff := diffResources(aResource, bResource); diff != nil && diff.HasChanges() {
+			res.Changed = append(res.Changed, diff)
+		}
+	}
+	for name := range bResources {
+		if _, ok := aResources[name]; !ok {
+			res.Added = append(res.Added, name)
+		}
+	}
+
+	sort.Strings(res.Added)
+	sort.Strings(res.Removed)
+	sort.Slice(res.Changed, func(i, j int) bool {
+		return res.Changed[i].Name < res.Changed[j].Name
+	})
+	return res
+}
+
+// modResourceMap returns a map of all resources of the mod, keyed by name
+func modResourceMap(mod *modconfig.Mod) map[string]modconfig.HclResource {
+	res := make(map[string]modconfig.HclResource)
+	if mod == nil || mod.ResourceMaps == nil {
+		return res
+	}
+	resourceFunc := func(item modconfig.HclResource) (bool, error) {
+		res[item.Name()] = item
+		// continue walking
+		return true, nil
+	}
+	// resourceFunc never returns an error
+	_ = mod.ResourceMaps.WalkResources(resourceFunc)
+	return res
+}
+
+// diffResources returns the diff between 2 resources with the same name
+// if the resources are of different types, the 'Type' property is reported as changed
+// if the resource type has no Diff function, nil is returned
+func diffResources(a, b modconfig.HclResource) *modconfig.DashboardTreeItemDiffs {
+	if a.BlockType() != b.BlockType() {
+		res := &modconfig.DashboardTreeItemDiffs{Name: a.Name()}
+		if item, ok := b.(modconfig.ModTreeItem); ok {
+			res.Item = item
+		}
+		res.AddPropertyDiff("Type")
+		return res
+	}
+
+	switch t := a.(type) {
+	case *modconfig.Benchmark:
+		return t.Diff(b.(*modconfig.Benchmark))
+	case *modconfig.Control:
+		return t.Diff(b.(*modconfig.Control))
+	case *modconfig.Dashboard:
+		return t.Diff(b.(*modconfig.Dashboard))
+	case *modconfig.DashboardCard:
+		return t.Diff(b.(*modconfig.DashboardCard))
+	case *modconfig.DashboardCategory:
+		return t.Diff(b.(*modconfig.DashboardCategory))
+	case *modconfig.DashboardChart:
+		return t.Diff(b.(*modconfig.DashboardChart))
+	case *modconfig.DashboardContainer:
+		return t.Diff(b.(*modconfig.DashboardContainer))
+	case *modconfig.DashboardEdge:
+		return t.Diff(b.(*modconfig.DashboardEdge))
+	case *modconfig.DashboardFlow:
+		return t.Diff(b.(*modconfig.DashboardFlow))
+	case *modconfig.DashboardGraph:
+		return t.Diff(b.(*modconfig.DashboardGraph))
+	case *modconfig.DashboardHierarchy:
+		return t.Diff(b.(*modconfig.DashboardHierarchy))
+	case *modconfig.DashboardImage:
+		return t.Diff(b.(*modconfig.DashboardImage))
+	case *modconfig.DashboardInput:
+		return t.Diff(b.(*modconfig.DashboardInput))
+	case *modconfig.DashboardNode:
+		return t.Diff(b.(*modconfig.DashboardNode))
+	case *modconfig.DashboardTable:
+		return t.Diff(b.(*modconfig.DashboardTable))
+	case *modconfig.DashboardText:
+		return t.Diff(b.(*modconfig.DashboardText))
+	case *modconfig.DashboardWith:
+		return t.Diff(b.(*modconfig.DashboardWith))
+	case *modconfig.Local:
+		return t.Diff(b.(*modconfig.Local))
+	case *modconfig.Query:
+		return t.Diff(b.(*modconfig.Query))
+	case *modconfig.Variable:
+		return t.Diff(b.(*modconfig.Variable))
+	}
+	return nil
+}
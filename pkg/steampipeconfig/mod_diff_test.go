@@ -0,0 +1,78 @@
+package steampipeconfig
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// map of control short name to title
+type modDiffTestControls map[string]string
+
+type modDiffTest struct {
+	a               modDiffTestControls
+	b               modDiffTestControls
+	expectedAdded   []string
+	expectedRemoved []string
+	// map of changed resource name to changed properties
+	expectedChanged map[string][]string
+}
+
+var testCasesModDiff = map[string]modDiffTest{
+	"added, removed and changed": {
+		a:               modDiffTestControls{"c1": "control 1", "c2": "control 2", "c3": "control 3"},
+		b:               modDiffTestControls{"c1": "control 1", "c2": "control 2 updated", "c4": "control 4"},
+		expectedAdded:   []string{"test.control.c4"},
+		expectedRemoved: []string{"test.control.c3"},
+		expectedChanged: map[string][]string{"test.control.c2": {"Title"}},
+	},
+	"no changes": {
+		a:               modDiffTestControls{"c1": "control 1", "c2": "control 2"},
+		b:               modDiffTestControls{"c1": "control 1", "c2": "control 2"},
+		expectedAdded:   []string{},
+		expectedRemoved: []string{},
+		expectedChanged: map[string][]string{},
+	},
+	"empty mods": {
+		expectedAdded:   []string{},
+		expectedRemoved: []string{},
+		expectedChanged: map[string][]string{},
+	},
+}
+
+func TestDiffMods(t *testing.T) {
+	for name, test := range testCasesModDiff {
+		diff := DiffMods(newModDiffTestMod(t, test.a), newModDiffTestMod(t, test.b))
+		if !reflect.DeepEqual(diff.Added, test.expectedAdded) {
+			t.Errorf("Test %s FAILED. Expected added %v, got %v", name, test.expectedAdded, diff.Added)
+		}
+		if !reflect.DeepEqual(diff.Removed, test.expectedRemoved) {
+			t.Errorf("Test %s FAILED. Expected removed %v, got %v", name, test.expectedRemoved, diff.Removed)
+		}
+		changed := make(map[string][]string)
+		for _, d := range diff.Changed {
+			changed[d.Name] = d.ChangedProperties
+		}
+		if !reflect.DeepEqual(changed, test.expectedChanged) {
+			t.Errorf("Test %s FAILED. Expected changed %v, got %v", name, test.expectedChanged, changed)
+		}
+		if hasChanges := len(test.expectedAdded)+len(test.expectedRemoved)+len(test.expectedChanged) > 0; diff.HasChanges() != hasChanges {
+			t.Errorf("Test %s FAILED. Expected HasChanges %v, got %v", name, hasChanges, diff.HasChanges())
+		}
+	}
+}
+
+func newModDiffTestMod(t *testing.T, controls modDiffTestControls) *modconfig.Mod {
+	mod := modconfig.NewMod("test", "", hcl.Range{})
+	for shortName, title := range controls {
+		control := modconfig.NewControl(&hcl.Block{Type: modconfig.BlockTypeControl, Labels: []string{shortName}}, mod, shortName).(*modconfig.Control)
+		control.Title = utils.ToStringPointer(title)
+		if diags := mod.AddResource(control); diags.HasErrors() {
+			t.Fatalf("failed to add resource %s: %s", control.Name(), diags.Error())
+		}
+	}
+	return mod
+}
@@ -2,6 +2,7 @@ package modconfig
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -25,6 +26,17 @@ type Benchmark struct {
 	ChildNames NamedItemList `cty:"child_names" json:"-"`
 	// used for introspection tables
 	ChildNameStrings []string `cty:"child_name_strings" column:"children,jsonb" json:"-"`
+	// optional list of child names, specifying the order in which children are displayed
+	ChildOrder []string `cty:"child_order" column:"child_order,jsonb" json:"-"`
+	// optional glob patterns used to select child controls by name - resolved when the benchmark is decoded
+	Include []string `cty:"include" column:"include,jsonb" json:"-"`
+	Exclude []string `cty:"exclude" column:"exclude,jsonb" json:"-"`
+	// the relative weight of the benchmark when computing the score of its parent - defaults to 1
+	Weight *float64 `cty:"weight" column:"weight,numeric" json:"weight,omitempty"`
+	// optional severity expectations for the descendant controls of the benchmark
+	// - all controls must have at least the minimum severity, or exactly the required severity
+	MinSeverity      *string `cty:"min_severity" column:"min_severity,text" json:"-"`
+	RequiredSeverity *string `cty:"required_severity" column:"required_severity,text" json:"-"`
 
 	// dashboard specific properties
 	Base    *Benchmark `hcl:"base" json:"-"`
@@ -79,7 +91,96 @@ func (b *Benchmark) Equals(other *Benchmark) bool {
 // OnDecoded implements HclResource
 func (b *Benchmark) OnDecoded(block *hcl.Block, _ ResourceMapsProvider) hcl.Diagnostics {
 	b.setBaseProperties()
-	return nil
+	// NOTE: this must be done after the base properties are set, as the children and child order may be inherited
+	diags := b.ValidateChildOrder()
+	diags = append(diags, b.validateWeight()...)
+	diags = append(diags, b.validateSeverityExpectations()...)
+	return diags
+}
+
+// validate the weight, if specified, is not negative
+func (b *Benchmark) validateWeight() hcl.Diagnostics {
+	if b.Weight == nil || *b.Weight >= 0 {
+		return nil
+	}
+	return hcl.Diagnostics{&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf("%s has invalid weight %v - weight must not be negative", b.Name(), *b.Weight),
+		Subject:  &b.DeclRange,
+	}}
+}
+
+// validate min_severity and required_severity, if specified, are supported severities
+func (b *Benchmark) validateSeverityExpectations() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	expectations := []struct {
+		name  string
+		value *string
+	}{
+		{"min_severity", b.MinSeverity},
+		{"required_severity", b.RequiredSeverity},
+	}
+	for _, expectation := range expectations {
+		if expectation.value == nil {
+			continue
+		}
+		if _, ok := SeverityRank(*expectation.value); !ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("%s has invalid %s '%s'", b.Name(), expectation.name, *expectation.value),
+				Detail:   fmt.Sprintf("%s must be one of: %s", expectation.name, severitiesString()),
+				Subject:  &b.DeclRange,
+			})
+		}
+	}
+	return diags
+}
+
+// ValidateControlSeverities checks the severities of the descendant controls of the benchmark
+// against the min_severity and required_severity of the benchmark, returning a warning for each violation
+// controls whose severity is determined from row data at execution time are not checked
+func (b *Benchmark) ValidateControlSeverities() hcl.Diagnostics {
+	if b.MinSeverity == nil && b.RequiredSeverity == nil {
+		return nil
+	}
+	var diags hcl.Diagnostics
+	for _, control := range b.GetChildControls() {
+		if control.Severity == nil && control.SeverityExpression != nil {
+			continue
+		}
+		severity := typehelpers.SafeString(control.Severity)
+		severityString := fmt.Sprintf("severity '%s'", severity)
+		if severity == "" {
+			severityString = "no severity"
+		}
+		if b.RequiredSeverity != nil && severity != *b.RequiredSeverity {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  fmt.Sprintf("%s has %s but %s requires severity '%s'", control.Name(), severityString, b.Name(), *b.RequiredSeverity),
+				Subject:  control.GetDeclRange(),
+			})
+			continue
+		}
+		if b.MinSeverity != nil {
+			minRank, _ := SeverityRank(*b.MinSeverity)
+			if rank, ok := SeverityRank(severity); !ok || rank < minRank {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagWarning,
+					Summary:  fmt.Sprintf("%s has %s which is below the minimum severity '%s' of %s", control.Name(), severityString, *b.MinSeverity, b.Name()),
+					Subject:  control.GetDeclRange(),
+				})
+			}
+		}
+	}
+	return diags
+}
+
+// GetWeight returns the relative weight of the benchmark when computing the score of its parent - defaults to 1
+func (b *Benchmark) GetWeight() float64 {
+	if b.Weight == nil {
+		return 1
+	}
+	return *b.Weight
 }
 
 func (b *Benchmark) String() string {
@@ -175,6 +276,30 @@ func (b *Benchmark) Diff(other *Benchmark) *DashboardTreeItemDiffs {
 		res.AddPropertyDiff("Type")
 	}
 
+	if b.GetWeight() != other.GetWeight() {
+		res.AddPropertyDiff("Weight")
+	}
+
+	if strings.Join(b.ChildOrder, ",") != strings.Join(other.ChildOrder, ",") {
+		res.AddPropertyDiff("ChildOrder")
+	}
+
+	if strings.Join(b.Include, ",") != strings.Join(other.Include, ",") {
+		res.AddPropertyDiff("Include")
+	}
+
+	if strings.Join(b.Exclude, ",") != strings.Join(other.Exclude, ",") {
+		res.AddPropertyDiff("Exclude")
+	}
+
+	if !utils.SafeStringsEqual(b.MinSeverity, other.MinSeverity) {
+		res.AddPropertyDiff("MinSeverity")
+	}
+
+	if !utils.SafeStringsEqual(b.RequiredSeverity, other.RequiredSeverity) {
+		res.AddPropertyDiff("RequiredSeverity")
+	}
+
 	if len(b.ChildNameStrings) != len(other.ChildNameStrings) {
 		res.AddPropertyDiff("Childen")
 	} else {
@@ -210,8 +335,81 @@ func (b *Benchmark) WalkResources(resourceFunc func(resource ModTreeItem) (bool,
 	return nil
 }
 
+// SetChildren sets the benchmark children, ordered according to ChildOrder (if set)
+// ChildNameStrings is set from the ordered children and ChildNames is reordered to match
 func (b *Benchmark) SetChildren(children []ModTreeItem) {
-	b.children = children
+	b.children = b.orderChildren(children)
+
+	b.ChildNameStrings = make([]string, len(b.children))
+	for i, child := range b.children {
+		b.ChildNameStrings[i] = child.Name()
+	}
+	if len(b.ChildOrder) > 0 {
+		b.ChildNames = b.orderChildNames()
+	}
+}
+
+// return a copy of ChildNames in the order of the children
+// names which do not resolve to a child are placed at the end
+func (b *Benchmark) orderChildNames() NamedItemList {
+	childIndex := func(name string) int {
+		if child := findChildByName(b.children, name); child != nil {
+			return slices.Index(b.children, child)
+		}
+		return len(b.children)
+	}
+	res := slices.Clone(b.ChildNames)
+	sort.SliceStable(res, func(i, j int) bool {
+		return childIndex(res[i].Name) < childIndex(res[j].Name)
+	})
+	return res
+}
+
+// order the children according to ChildOrder
+// children which are not listed in ChildOrder are appended in declaration order
+func (b *Benchmark) orderChildren(children []ModTreeItem) []ModTreeItem {
+	if len(b.ChildOrder) == 0 {
+		return children
+	}
+	var res = make([]ModTreeItem, 0, len(children))
+	var added = make(map[ModTreeItem]bool, len(children))
+	for _, name := range b.ChildOrder {
+		if child := findChildByName(children, name); child != nil && !added[child] {
+			res = append(res, child)
+			added[child] = true
+		}
+	}
+	for _, child := range children {
+		if !added[child] {
+			res = append(res, child)
+		}
+	}
+	return res
+}
+
+// ValidateChildOrder returns a warning for each name in ChildOrder which is not a child of the benchmark
+func (b *Benchmark) ValidateChildOrder() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, name := range b.ChildOrder {
+		if findChildByName(b.children, name) == nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  fmt.Sprintf("%s child_order contains '%s' which is not a child of the benchmark", b.Name(), name),
+				Subject:  &b.DeclRange,
+			})
+		}
+	}
+	return diags
+}
+
+// find a child by either its fully qualified or unqualified name
+func findChildByName(children []ModTreeItem, name string) ModTreeItem {
+	for _, child := range children {
+		if child.Name() == name || child.GetUnqualifiedName() == name {
+			return child
+		}
+	}
+	return nil
 }
 
 // CtyValue implements CtyValueProvider
@@ -236,9 +434,27 @@ func (b *Benchmark) setBaseProperties() {
 		b.Display = b.Base.Display
 	}
 
+	if b.Weight == nil {
+		b.Weight = b.Base.Weight
+	}
+
+	if b.MinSeverity == nil {
+		b.MinSeverity = b.Base.MinSeverity
+	}
+
+	if b.RequiredSeverity == nil {
+		b.RequiredSeverity = b.Base.RequiredSeverity
+	}
+
+	if b.ChildOrder == nil {
+		b.ChildOrder = b.Base.ChildOrder
+	}
+
 	if len(b.children) == 0 {
-		b.children = b.Base.children
-		b.ChildNameStrings = b.Base.ChildNameStrings
 		b.ChildNames = b.Base.ChildNames
+		b.SetChildren(b.Base.children)
+	} else if len(b.ChildOrder) > 0 {
+		// the child order may be inherited from the base, so reorder our children
+		b.SetChildren(b.children)
 	}
 }
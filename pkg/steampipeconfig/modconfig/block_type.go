@@ -31,6 +31,7 @@ const (
 	BlockTypeLegacyRequires = "requires"
 	BlockTypeCategory       = "category"
 	BlockTypeWith           = "with"
+	BlockTypeLabels         = "labels"
 
 	// config blocks
 	BlockTypeRateLimiter      = "limiter"
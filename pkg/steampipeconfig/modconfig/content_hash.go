@@ -0,0 +1,84 @@
+package modconfig
+
+import (
+	"encoding/json"
+	"log"
+	"reflect"
+	"strings"
+
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/pipe-fittings/hclhelpers"
+	"github.com/turbot/steampipe/pkg/utils"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ResourceContentHash returns a digest of the normalised definition of the resource
+// the normalised definition is the set of properties which are exposed as introspection table columns,
+// excluding the resource metadata (i.e. the file and line the resource is declared at)
+func ResourceContentHash(resource HclResource) string {
+	definition := make(map[string]any)
+	addColumnValues(definition, resource)
+	if qp, ok := resource.(QueryProvider); ok {
+		addColumnValues(definition, qp.GetQueryProviderImpl())
+	}
+	if mti, ok := resource.(ModTreeItem); ok {
+		addColumnValues(definition, mti.GetModTreeItemImpl())
+	}
+	addColumnValues(definition, resource.GetHclResourceImpl())
+	return definitionHash(resource, definition)
+}
+
+// QueryContentHash returns a digest of the query definition of the resource, i.e. its sql, args and params
+// the sql is normalised, so differences in whitespace outside of string literals, or a trailing semicolon, are ignored
+func QueryContentHash(resource QueryProvider) string {
+	definition := make(map[string]any)
+	addColumnValues(definition, resource.GetQueryProviderImpl())
+	if sql := resource.GetQueryProviderImpl().SQL; sql != nil {
+		definition["sql"] = utils.NormaliseSqlWhitespace(*sql)
+	}
+	return definitionHash(resource, definition)
+}
+
+func definitionHash(resource HclResource, definition map[string]any) string {
+	// json.Marshal sorts map keys so the resulting string is stable
+	definitionJson, err := json.Marshal(definition)
+	if err != nil {
+		// we do not expect this - all values have already been converted to json compatible values
+		log.Printf("[WARN] failed to build content hash for %s: %s", resource.Name(), err.Error())
+		return ""
+	}
+	return helpers.GetMD5Hash(string(definitionJson))
+}
+
+// addColumnValues adds the values of all properties of item which have a `column` tag to the map, keyed by column name
+func addColumnValues(values map[string]any, item any) {
+	val := reflect.ValueOf(helpers.DereferencePointer(item))
+	if val.Kind() != reflect.Struct {
+		return
+	}
+	t := val.Type()
+	for i := 0; i < val.NumField(); i++ {
+		columnTag, ok := t.Field(i).Tag.Lookup("column")
+		if !ok {
+			continue
+		}
+		value := helpers.DereferencePointer(val.Field(i).Interface())
+		if value == nil {
+			continue
+		}
+		column := strings.Split(columnTag, ",")[0]
+		// cty values cannot be marshalled directly - convert to json
+		if ctyVal, ok := value.(cty.Value); ok {
+			str, err := hclhelpers.CtyToJSON(ctyVal)
+			if err != nil {
+				log.Printf("[WARN] failed to convert %s to json: %s", column, err.Error())
+				continue
+			}
+			value = str
+		}
+		if ctyType, ok := value.(cty.Type); ok {
+			value = ctyType.FriendlyName()
+		}
+		values[column] = value
+	}
+}
@@ -2,16 +2,23 @@ package modconfig
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/turbot/go-kit/types"
 	typehelpers "github.com/turbot/go-kit/types"
 	"github.com/turbot/pipe-fittings/hclhelpers"
+	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/utils"
 	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/exp/maps"
 )
 
+// a primary key must be an unquoted postgres identifier
+var primaryKeyRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_$]*$`)
+
 // Control is a struct representing the Control resource
 type Control struct {
 	ResourceWithMetadataImpl
@@ -21,6 +28,29 @@ type Control struct {
 	Remain hcl.Body `hcl:",remain" json:"-"`
 
 	Severity *string `cty:"severity" hcl:"severity"  column:"severity,text" json:"severity,omitempty"`
+	// if the severity is an expression referencing row data, it is evaluated for each result row at execution time
+	SeverityExpression hcl.Expression `json:"-"`
+	// the parse eval context, used to evaluate the severity expression so it may reference variables and functions
+	severityEvalCtx *hcl.EvalContext
+	// the status to give the control if its query fails (error, skip or alarm) - defaults to error
+	OnError *string `cty:"on_error" hcl:"on_error" column:"on_error,text" json:"on_error,omitempty"`
+	// the connection the control queries - used to validate the connections a mod requires before execution
+	Connection *string `cty:"connection" hcl:"connection" column:"connection,text" json:"connection,omitempty"`
+	// optional credential hints - the profile or role the control query should run under (absent means default credentials)
+	Profile *string `cty:"profile" hcl:"profile" column:"profile,text" json:"profile,omitempty"`
+	RoleArn *string `cty:"role_arn" hcl:"role_arn" column:"role_arn,text" json:"role_arn,omitempty"`
+	// structured remediation guidance for the resources the control alarms for
+	Remediation *ControlRemediation `cty:"remediation" hcl:"remediation,block" column:"remediation,jsonb" json:"remediation,omitempty"`
+	// self-tests declaring the status counts expected when the control is run against fixture data
+	Tests []*ControlTest `cty:"tests" hcl:"test,block" column:"tests,jsonb" json:"tests,omitempty"`
+	// optional type hints for the output columns, keyed by column name - used to format the column values
+	ColumnTypes map[string]string `cty:"column_types" hcl:"column_types,optional" column:"column_types,jsonb" json:"column_types,omitempty"`
+	// optional page size hint for the control query - absent means the default page size is used
+	BatchSize *int `cty:"batch_size" hcl:"batch_size" column:"batch_size,integer" json:"batch_size,omitempty"`
+	// optional output column which identifies a result row - used to match rows across runs (defaults to the resource column)
+	PrimaryKey *string `cty:"primary_key" hcl:"primary_key" column:"primary_key,text" json:"primary_key,omitempty"`
+	// controls which must complete before this control is run
+	DependsOn NamedItemList `cty:"depends_on" hcl:"depends_on,optional" column:"depends_on,jsonb" json:"-"`
 
 	// dashboard specific properties
 	Base    *Control `hcl:"base" json:"-"`
@@ -62,11 +92,31 @@ func (c *Control) Equals(other *Control) bool {
 		typehelpers.SafeString(c.Description) == typehelpers.SafeString(other.Description) &&
 		typehelpers.SafeString(c.Documentation) == typehelpers.SafeString(other.Documentation) &&
 		typehelpers.SafeString(c.Severity) == typehelpers.SafeString(other.Severity) &&
+		typehelpers.SafeString(c.OnError) == typehelpers.SafeString(other.OnError) &&
+		typehelpers.SafeString(c.Connection) == typehelpers.SafeString(other.Connection) &&
+		typehelpers.SafeString(c.Profile) == typehelpers.SafeString(other.Profile) &&
+		typehelpers.SafeString(c.RoleArn) == typehelpers.SafeString(other.RoleArn) &&
 		typehelpers.SafeString(c.SQL) == typehelpers.SafeString(other.SQL) &&
 		typehelpers.SafeString(c.Title) == typehelpers.SafeString(other.Title)
 	if !res {
 		return res
 	}
+	if (c.Remediation == nil) != (other.Remediation == nil) ||
+		(c.Remediation != nil && !c.Remediation.Equals(other.Remediation)) {
+		return false
+	}
+	if !c.testsEqual(other) {
+		return false
+	}
+	if !maps.Equal(c.ColumnTypes, other.ColumnTypes) {
+		return false
+	}
+	if !utils.SafeIntEqual(c.BatchSize, other.BatchSize) {
+		return false
+	}
+	if !utils.SafeStringsEqual(c.PrimaryKey, other.PrimaryKey) {
+		return false
+	}
 	if len(c.Tags) != len(other.Tags) {
 		return false
 	}
@@ -163,8 +213,164 @@ func (c *Control) GetParentNames() []string {
 // OnDecoded implements HclResource
 func (c *Control) OnDecoded(block *hcl.Block, resourceMapProvider ResourceMapsProvider) hcl.Diagnostics {
 	c.setBaseProperties()
+	c.setTitleFromQuery()
+
+	diags := c.validateSqlStatements()
+	diags = append(diags, c.validateOnError()...)
+	diags = append(diags, c.validateCredentialHints()...)
+	diags = append(diags, c.validateTests()...)
+	diags = append(diags, c.validateColumnTypes()...)
+	diags = append(diags, c.validateBatchSize()...)
+	diags = append(diags, c.validatePrimaryKey()...)
+	diags = append(diags, c.validateDependsOn()...)
+	return append(diags, c.QueryProviderImpl.OnDecoded(block, resourceMapProvider)...)
+}
+
+// if the control has no title (either explicit or from its base), use the title of the query it references (if any)
+func (c *Control) setTitleFromQuery() {
+	if c.Title == nil && c.Query != nil {
+		c.Title = c.Query.Title
+	}
+}
 
-	return c.QueryProviderImpl.OnDecoded(block, resourceMapProvider)
+// a control sql may contain multiple statements - all but the last are executed as setup statements
+// validate the sql can be split into statements
+func (c *Control) validateSqlStatements() hcl.Diagnostics {
+	if c.SQL == nil {
+		return nil
+	}
+	if _, err := utils.SplitSqlStatements(*c.SQL); err != nil {
+		return hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("%s has invalid sql", c.Name()),
+			Detail:   err.Error(),
+			Subject:  &c.DeclRange,
+		}}
+	}
+	return nil
+}
+
+// validate the credential hints, if specified, are not empty
+func (c *Control) validateCredentialHints() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	hints := []struct {
+		name  string
+		value *string
+	}{
+		{"profile", c.Profile},
+		{"role_arn", c.RoleArn},
+	}
+	for _, hint := range hints {
+		if hint.value != nil && strings.TrimSpace(*hint.value) == "" {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("%s has an empty %s", c.Name(), hint.name),
+				Subject:  &c.DeclRange,
+			})
+		}
+	}
+	return diags
+}
+
+// validate the batch size, if specified, is positive
+func (c *Control) validateBatchSize() hcl.Diagnostics {
+	if c.BatchSize == nil || *c.BatchSize > 0 {
+		return nil
+	}
+	return hcl.Diagnostics{&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf("%s has invalid batch_size %d", c.Name(), *c.BatchSize),
+		Detail:   "batch_size must be greater than zero",
+		Subject:  &c.DeclRange,
+	}}
+}
+
+// validate the primary key, if specified, is a plausible column name
+// NOTE: whether the column is returned by the control query can only be checked when the control is run
+func (c *Control) validatePrimaryKey() hcl.Diagnostics {
+	if c.PrimaryKey == nil || primaryKeyRegex.MatchString(*c.PrimaryKey) {
+		return nil
+	}
+	return hcl.Diagnostics{&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf("%s has invalid primary_key '%s'", c.Name(), *c.PrimaryKey),
+		Detail:   "primary_key must be the name of a column returned by the control query",
+		Subject:  &c.DeclRange,
+	}}
+}
+
+// validate that depends_on only references other controls
+func (c *Control) validateDependsOn() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, dependency := range c.DependsOn {
+		parsedName, err := ParseResourceName(dependency.Name)
+		if err == nil && parsedName.ItemType == BlockTypeControl && dependency.Name != c.Name() {
+			continue
+		}
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("%s has invalid depends_on '%s'", c.Name(), dependency.Name),
+			Detail:   "depends_on must only reference other controls",
+			Subject:  &c.DeclRange,
+		})
+	}
+	return diags
+}
+
+// validate the control tests - test names must be unique
+func (c *Control) validateTests() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	names := make(map[string]struct{}, len(c.Tests))
+	for _, test := range c.Tests {
+		if _, ok := names[test.Name]; ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("%s has duplicate test '%s'", c.Name(), test.Name),
+				Subject:  &c.DeclRange,
+			})
+			continue
+		}
+		names[test.Name] = struct{}{}
+		diags = append(diags, test.validate(c)...)
+	}
+	return diags
+}
+
+func (c *Control) testsEqual(other *Control) bool {
+	if len(c.Tests) != len(other.Tests) {
+		return false
+	}
+	for i, test := range c.Tests {
+		if !test.Equals(other.Tests[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// validate the on_error policy is one of the supported statuses
+func (c *Control) validateOnError() hcl.Diagnostics {
+	if c.OnError == nil {
+		return nil
+	}
+	switch *c.OnError {
+	case constants.ControlError, constants.ControlSkip, constants.ControlAlarm:
+		return nil
+	}
+	return hcl.Diagnostics{&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf("%s has invalid on_error value '%s'", c.Name(), *c.OnError),
+		Detail:   fmt.Sprintf("on_error must be one of: %s, %s, %s", constants.ControlError, constants.ControlSkip, constants.ControlAlarm),
+		Subject:  &c.DeclRange,
+	}}
+}
+
+// GetOnError returns the status to give the control if its query fails - defaults to error
+func (c *Control) GetOnError() string {
+	if c.OnError == nil {
+		return constants.ControlError
+	}
+	return *c.OnError
 }
 
 // GetWidth implements DashboardLeafNode
@@ -200,6 +406,41 @@ func (c *Control) Diff(other *Control) *DashboardTreeItemDiffs {
 	if !utils.SafeStringsEqual(c.Severity, other.Severity) {
 		res.AddPropertyDiff("Severity")
 	}
+	if (c.SeverityExpression == nil) != (other.SeverityExpression == nil) ||
+		(c.SeverityExpression != nil && !hclhelpers.ExpressionsEqual(c.SeverityExpression, other.SeverityExpression)) {
+		res.AddPropertyDiff("SeverityExpression")
+	}
+	if !utils.SafeStringsEqual(c.OnError, other.OnError) {
+		res.AddPropertyDiff("OnError")
+	}
+	if !utils.SafeStringsEqual(c.Connection, other.Connection) {
+		res.AddPropertyDiff("Connection")
+	}
+	if !utils.SafeStringsEqual(c.Profile, other.Profile) {
+		res.AddPropertyDiff("Profile")
+	}
+	if !utils.SafeStringsEqual(c.RoleArn, other.RoleArn) {
+		res.AddPropertyDiff("RoleArn")
+	}
+	if (c.Remediation == nil) != (other.Remediation == nil) ||
+		(c.Remediation != nil && !c.Remediation.Equals(other.Remediation)) {
+		res.AddPropertyDiff("Remediation")
+	}
+	if !c.testsEqual(other) {
+		res.AddPropertyDiff("Tests")
+	}
+	if !maps.Equal(c.ColumnTypes, other.ColumnTypes) {
+		res.AddPropertyDiff("ColumnTypes")
+	}
+	if !utils.SafeIntEqual(c.BatchSize, other.BatchSize) {
+		res.AddPropertyDiff("BatchSize")
+	}
+	if !utils.SafeStringsEqual(c.PrimaryKey, other.PrimaryKey) {
+		res.AddPropertyDiff("PrimaryKey")
+	}
+	if strings.Join(c.DependsOn.StringList(), ",") != strings.Join(other.DependsOn.StringList(), ",") {
+		res.AddPropertyDiff("DependsOn")
+	}
 	if len(c.Tags) != len(other.Tags) {
 		res.AddPropertyDiff("Tags")
 	} else {
@@ -230,8 +471,42 @@ func (c *Control) setBaseProperties() {
 	// call into parent nested struct setBaseProperties
 	c.QueryProviderImpl.setBaseProperties()
 
-	if c.Severity == nil {
+	if c.Severity == nil && c.SeverityExpression == nil {
 		c.Severity = c.Base.Severity
+		c.SeverityExpression = c.Base.SeverityExpression
+		c.severityEvalCtx = c.Base.severityEvalCtx
+	}
+	if c.OnError == nil {
+		c.OnError = c.Base.OnError
+	}
+	if c.Connection == nil {
+		c.Connection = c.Base.Connection
+	}
+	if c.Profile == nil {
+		c.Profile = c.Base.Profile
+	}
+	if c.RoleArn == nil {
+		c.RoleArn = c.Base.RoleArn
+	}
+	if c.Tests == nil {
+		c.Tests = c.Base.Tests
+	}
+	if c.ColumnTypes == nil {
+		c.ColumnTypes = c.Base.ColumnTypes
+	}
+	if c.BatchSize == nil {
+		c.BatchSize = c.Base.BatchSize
+	}
+	if c.PrimaryKey == nil {
+		c.PrimaryKey = c.Base.PrimaryKey
+	}
+	if c.DependsOn == nil {
+		c.DependsOn = c.Base.DependsOn
+	}
+	if c.Remediation == nil {
+		c.Remediation = c.Base.Remediation
+	} else if c.Base.Remediation != nil {
+		c.Remediation.Merge(c.Base.Remediation)
 	}
 
 	if c.Width == nil {
@@ -244,3 +519,47 @@ func (c *Control) setBaseProperties() {
 		c.Display = c.Base.Display
 	}
 }
+
+// SetSeverityExpression sets the row dependent severity expression and the eval context used to evaluate it
+func (c *Control) SetSeverityExpression(expr hcl.Expression, evalCtx *hcl.EvalContext) {
+	c.SeverityExpression = expr
+	c.severityEvalCtx = evalCtx
+}
+
+// HasRowSeverity returns whether the severity of the control depends on the row data
+func (c *Control) HasRowSeverity() bool {
+	return c.SeverityExpression != nil
+}
+
+// EvaluateRowSeverity evaluates the severity expression for a result row, given the row data keyed by column name
+// row data is referenced in the expression as 'row.<column>'
+func (c *Control) EvaluateRowSeverity(rowData map[string]any) (string, error) {
+	if c.SeverityExpression == nil {
+		return typehelpers.SafeString(c.Severity), nil
+	}
+	rowValues := make(map[string]cty.Value, len(rowData))
+	for column, value := range rowData {
+		// null column values are passed as null
+		if value == nil {
+			rowValues[column] = cty.NullVal(cty.DynamicPseudoType)
+			continue
+		}
+		ctyValue, err := hclhelpers.ConvertInterfaceToCtyValue(value)
+		if err != nil {
+			return "", err
+		}
+		rowValues[column] = ctyValue
+	}
+	// add the row data to a child of the parse eval context, so the expression may also reference variables and functions
+	evalCtx := &hcl.EvalContext{}
+	if c.severityEvalCtx != nil {
+		evalCtx = c.severityEvalCtx.NewChild()
+	}
+	evalCtx.Variables = map[string]cty.Value{"row": cty.ObjectVal(rowValues)}
+
+	var severity *string
+	if diags := gohcl.DecodeExpression(c.SeverityExpression, evalCtx, &severity); diags.HasErrors() {
+		return "", fmt.Errorf("failed to evaluate severity for %s: %s", c.Name(), diags.Error())
+	}
+	return typehelpers.SafeString(severity), nil
+}
@@ -0,0 +1,52 @@
+package modconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"golang.org/x/exp/maps"
+)
+
+// ColumnTypeHints is a map of the supported control column type hints to the sql data type used to format the column
+var ColumnTypeHints = map[string]string{
+	"text":      "TEXT",
+	"number":    "NUMERIC",
+	"boolean":   "BOOL",
+	"timestamp": "TIMESTAMP",
+	"date":      "DATE",
+	"json":      "JSONB",
+}
+
+// GetColumnTypeHint returns the sql data type hinted for the given output column, if any
+func (c *Control) GetColumnTypeHint(column string) (string, bool) {
+	hint, ok := c.ColumnTypes[column]
+	if !ok {
+		return "", false
+	}
+	dataType, ok := ColumnTypeHints[hint]
+	return dataType, ok
+}
+
+// validate the column type hints are all supported
+func (c *Control) validateColumnTypes() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	columns := maps.Keys(c.ColumnTypes)
+	sort.Strings(columns)
+	for _, column := range columns {
+		hint := c.ColumnTypes[column]
+		if _, ok := ColumnTypeHints[hint]; ok {
+			continue
+		}
+		supported := maps.Keys(ColumnTypeHints)
+		sort.Strings(supported)
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("%s has invalid type '%s' for column '%s'", c.Name(), hint, column),
+			Detail:   fmt.Sprintf("column types must be one of: %s", strings.Join(supported, ", ")),
+			Subject:  &c.DeclRange,
+		})
+	}
+	return diags
+}
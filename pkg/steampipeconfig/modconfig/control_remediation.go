@@ -0,0 +1,31 @@
+package modconfig
+
+import (
+	"slices"
+
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// ControlRemediation is structured guidance describing how to remediate the resources a control alarms for
+type ControlRemediation struct {
+	Description *string  `cty:"description" hcl:"description" json:"description,omitempty"`
+	Commands    []string `cty:"commands" hcl:"commands,optional" json:"commands,omitempty"`
+}
+
+func (r *ControlRemediation) Equals(other *ControlRemediation) bool {
+	if other == nil {
+		return false
+	}
+
+	return utils.SafeStringsEqual(r.Description, other.Description) &&
+		slices.Equal(r.Commands, other.Commands)
+}
+
+func (r *ControlRemediation) Merge(other *ControlRemediation) {
+	if r.Description == nil {
+		r.Description = other.Description
+	}
+	if r.Commands == nil {
+		r.Commands = other.Commands
+	}
+}
@@ -0,0 +1,77 @@
+package modconfig
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// ControlTest is a self-test for a control, declaring the status counts expected when the control is run against fixture data
+// a count which is not specified is not asserted
+// NOTE: tests are parsed and exposed for use by a test harness - they are not executed
+type ControlTest struct {
+	Name        string  `cty:"name" hcl:"name,label" json:"name"`
+	Description *string `cty:"description" hcl:"description" json:"description,omitempty"`
+	Alarm       *int    `cty:"alarm" hcl:"alarm" json:"alarm,omitempty"`
+	Ok          *int    `cty:"ok" hcl:"ok" json:"ok,omitempty"`
+	Info        *int    `cty:"info" hcl:"info" json:"info,omitempty"`
+	Skip        *int    `cty:"skip" hcl:"skip" json:"skip,omitempty"`
+	Error       *int    `cty:"error" hcl:"error" json:"error,omitempty"`
+}
+
+func (t *ControlTest) Equals(other *ControlTest) bool {
+	if other == nil {
+		return false
+	}
+	return t.Name == other.Name &&
+		utils.SafeStringsEqual(t.Description, other.Description) &&
+		utils.SafeIntEqual(t.Alarm, other.Alarm) &&
+		utils.SafeIntEqual(t.Ok, other.Ok) &&
+		utils.SafeIntEqual(t.Info, other.Info) &&
+		utils.SafeIntEqual(t.Skip, other.Skip) &&
+		utils.SafeIntEqual(t.Error, other.Error)
+}
+
+// ExpectedCounts returns a map of the expected count for each status, keyed by status
+// only the statuses which are specified by the test are included
+func (t *ControlTest) ExpectedCounts() map[string]int {
+	counts := map[string]*int{
+		constants.ControlAlarm: t.Alarm,
+		constants.ControlOk:    t.Ok,
+		constants.ControlInfo:  t.Info,
+		constants.ControlSkip:  t.Skip,
+		constants.ControlError: t.Error,
+	}
+	res := make(map[string]int)
+	for status, count := range counts {
+		if count != nil {
+			res[status] = *count
+		}
+	}
+	return res
+}
+
+// validate the test specifies at least one count, and that all counts are non-negative
+func (t *ControlTest) validate(control *Control) hcl.Diagnostics {
+	expected := t.ExpectedCounts()
+	if len(expected) == 0 {
+		return hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("%s test '%s' does not specify any expected status counts", control.Name(), t.Name),
+			Subject:  &control.DeclRange,
+		}}
+	}
+	var diags hcl.Diagnostics
+	for _, status := range []string{constants.ControlAlarm, constants.ControlOk, constants.ControlInfo, constants.ControlSkip, constants.ControlError} {
+		if count, ok := expected[status]; ok && count < 0 {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("%s test '%s' has invalid %s count %d - counts must not be negative", control.Name(), t.Name, status, count),
+				Subject:  &control.DeclRange,
+			})
+		}
+	}
+	return diags
+}
@@ -2,16 +2,22 @@ package modconfig
 
 import (
 	"fmt"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/spf13/viper"
 	"github.com/stevenle/topsort"
+	"github.com/turbot/go-kit/helpers"
 	typehelpers "github.com/turbot/go-kit/types"
 	"github.com/turbot/pipe-fittings/hclhelpers"
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/utils"
 	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/exp/maps"
 )
 
 const rootRuntimeDependencyNode = "rootRuntimeDependencyNode"
@@ -26,16 +32,27 @@ type Dashboard struct {
 	// required to allow partial decoding
 	Remain hcl.Body `hcl:",remain" json:"-"`
 
-	Width   *int              `cty:"width" hcl:"width"  column:"width,text"`
-	Display *string           `cty:"display" hcl:"display" column:"display,text"`
-	Inputs  []*DashboardInput `cty:"inputs" column:"inputs,jsonb"`
-	UrlPath string            `cty:"url_path"  column:"url_path,jsonb"`
-	Base    *Dashboard        `hcl:"base"`
+	Width   *int    `cty:"width" hcl:"width"  column:"width,text"`
+	Display *string `cty:"display" hcl:"display" column:"display,text"`
+	// if set, the dashboard tags are inherited by all child panels
+	InheritTags *bool `cty:"inherit_tags" hcl:"inherit_tags" column:"inherit_tags,bool"`
+	// the interval at which the dashboard should be refreshed, as a duration string, e.g. "5m"
+	AutoRefresh *string           `cty:"auto_refresh" hcl:"auto_refresh" column:"auto_refresh,text" json:"auto_refresh,omitempty"`
+	Inputs      []*DashboardInput `cty:"inputs" column:"inputs,jsonb"`
+	UrlPath     string            `cty:"url_path"  column:"url_path,jsonb"`
+	Base        *Dashboard        `hcl:"base"`
 	// store children in a way which can be serialised via cty
 	ChildNames []string `cty:"children" column:"children,jsonb"`
+	// locale specific title and description overrides, keyed by locale
+	LabelsList []*DashboardLabels          `hcl:"labels,block" json:"-"`
+	Labels     map[string]*DashboardLabels `cty:"labels" column:"labels,jsonb" json:"labels,omitempty"`
 	// map of all inputs in our resource tree
 	selfInputsMap          map[string]*DashboardInput
 	runtimeDependencyGraph *topsort.Graph
+	// the names of all inputs, in dependency order
+	inputDependencyOrder []string
+	// map of input name to the names of the inputs it depends on
+	inputDependencies map[string][]string
 }
 
 func NewDashboard(block *hcl.Block, mod *Mod, shortName string) HclResource {
@@ -146,15 +163,83 @@ func (d *Dashboard) Equals(other *Dashboard) bool {
 // OnDecoded implements HclResource
 func (d *Dashboard) OnDecoded(block *hcl.Block, _ ResourceMapsProvider) hcl.Diagnostics {
 	d.setBaseProperties()
+	d.InheritTagsToChildren()
 
 	d.ChildNames = make([]string, len(d.children))
 	for i, child := range d.children {
 		d.ChildNames[i] = child.Name()
 	}
 
+	diags := d.setLabelsMap()
+	diags = append(diags, validateWidth(d, d.Width)...)
+	return append(diags, d.validateAutoRefresh()...)
+}
+
+// validate the auto_refresh interval, if specified, is a positive duration
+func (d *Dashboard) validateAutoRefresh() hcl.Diagnostics {
+	if d.AutoRefresh == nil {
+		return nil
+	}
+	if interval, err := time.ParseDuration(*d.AutoRefresh); err != nil || interval <= 0 {
+		return hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("%s has invalid auto_refresh '%s'", d.Name(), *d.AutoRefresh),
+			Detail:   "auto_refresh must be a positive duration, e.g. \"30s\" or \"5m\"",
+			Subject:  &d.DeclRange,
+		}}
+	}
 	return nil
 }
 
+// GetAutoRefresh returns the auto refresh interval of the dashboard - 0 means the dashboard is not refreshed
+func (d *Dashboard) GetAutoRefresh() time.Duration {
+	if d.AutoRefresh == nil {
+		return 0
+	}
+	// the interval is validated when the dashboard is decoded
+	interval, _ := time.ParseDuration(*d.AutoRefresh)
+	return interval
+}
+
+// populate the labels map, validating each locale is only specified once
+func (d *Dashboard) setLabelsMap() hcl.Diagnostics {
+	if len(d.LabelsList) == 0 {
+		return nil
+	}
+	var diags hcl.Diagnostics
+	d.Labels = make(map[string]*DashboardLabels, len(d.LabelsList))
+	for _, l := range d.LabelsList {
+		if _, ok := d.Labels[l.Locale]; ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("%s has duplicate labels for locale '%s'", d.Name(), l.Locale),
+				Subject:  &d.DeclRange,
+			})
+			continue
+		}
+		d.Labels[l.Locale] = l
+	}
+	return diags
+}
+
+// GetLocalisedTitle returns the title for the given locale
+// if there is no title override for the locale, the default title is returned
+func (d *Dashboard) GetLocalisedTitle(locale string) string {
+	if l, ok := d.Labels[locale]; ok && l.Title != nil {
+		return *l.Title
+	}
+	return d.GetTitle()
+}
+
+// GetLocalisedDescription returns the description for the given locale
+// if there is no description override for the locale, the default description is returned
+func (d *Dashboard) GetLocalisedDescription(locale string) string {
+	if l, ok := d.Labels[locale]; ok && l.Description != nil {
+		return *l.Description
+	}
+	return d.GetDescription()
+}
+
 // GetWidth implements DashboardLeafNode
 func (d *Dashboard) GetWidth() int {
 	if d.Width == nil {
@@ -191,6 +276,10 @@ func (d *Dashboard) Diff(other *Dashboard) *DashboardTreeItemDiffs {
 		res.AddPropertyDiff("Width")
 	}
 
+	if !utils.SafeStringsEqual(d.AutoRefresh, other.AutoRefresh) {
+		res.AddPropertyDiff("AutoRefresh")
+	}
+
 	if len(d.Tags) != len(other.Tags) {
 		res.AddPropertyDiff("Tags")
 	} else {
@@ -209,6 +298,17 @@ func (d *Dashboard) Diff(other *Dashboard) *DashboardTreeItemDiffs {
 		res.AddPropertyDiff("Documentation")
 	}
 
+	if len(d.LabelsList) != len(other.LabelsList) {
+		res.AddPropertyDiff("Labels")
+	} else {
+		for i, l := range d.LabelsList {
+			if !l.Equals(other.LabelsList[i]) {
+				res.AddPropertyDiff("Labels")
+			}
+		}
+	}
+
+	res.populateChildNameDiffs(d.ChildNames, other.ChildNames)
 	res.populateChildDiffs(d, other)
 	return res
 }
@@ -228,6 +328,137 @@ func (d *Dashboard) AddChild(child ModTreeItem) {
 	}
 }
 
+// Clone returns a deep copy of the dashboard, so that state such as input values may be modified
+// (e.g. when generating multiple snapshots from the same workspace) without affecting the original
+// all children of the tree are copied and the parents of the copies refer to the cloned dashboard or container
+// the base dashboard is a separate resource of the workspace so is shared with the original
+// the input map, with map and runtime dependency graph of the clone are rebuilt from the cloned children
+func (d *Dashboard) Clone() *Dashboard {
+	clone := &Dashboard{
+		ResourceWithMetadataImpl: d.ResourceWithMetadataImpl,
+		ModTreeItemImpl:          d.ModTreeItemImpl,
+		Width:                    cloneIntPointer(d.Width),
+		Display:                  cloneStringPointer(d.Display),
+		InheritTags:              d.InheritTags,
+		AutoRefresh:              d.AutoRefresh,
+		UrlPath:                  d.UrlPath,
+		Base:                     d.Base,
+		ChildNames:               slices.Clone(d.ChildNames),
+		LabelsList:               cloneDashboardLabelsList(d.LabelsList),
+		inputDependencyOrder:     slices.Clone(d.inputDependencyOrder),
+		inputDependencies:        maps.Clone(d.inputDependencies),
+	}
+	clone.Tags = maps.Clone(d.Tags)
+	clone.parents = slices.Clone(d.parents)
+
+	// clone the inputs - this includes the inputs of child containers, so build a map of original to clone
+	// which is used to substitute the cloned inputs when cloning the children
+	inputClones := make(map[*DashboardInput]*DashboardInput, len(d.Inputs))
+	for _, input := range d.Inputs {
+		inputClone := input.Clone()
+		inputClone.SetDashboard(clone)
+		inputClones[input] = inputClone
+		clone.Inputs = append(clone.Inputs, inputClone)
+	}
+	clone.children = cloneDashboardChildren(d.children, inputClones, d, clone)
+	clone.setInputMap()
+	// the labels and with blocks were validated when the dashboard was parsed, so rebuilding their maps cannot fail
+	_ = clone.setLabelsMap()
+	for _, child := range clone.children {
+		if with, ok := child.(*DashboardWith); ok {
+			_ = clone.AddWith(with)
+		}
+	}
+
+	// the dashboard was validated when it was parsed, so rebuilding the graph from the same dependencies cannot fail
+	if d.runtimeDependencyGraph != nil {
+		_ = clone.ValidateRuntimeDependencies(nil)
+	}
+	return clone
+}
+
+// cloneDashboardChildren returns a copy of the children of a dashboard or container, substituting cloned inputs,
+// cloning containers and nested dashboards and copying all other panels
+// the parents of the copies which refer to the original parent are updated to refer to the parent clone
+func cloneDashboardChildren(children []ModTreeItem, inputClones map[*DashboardInput]*DashboardInput, parent, parentClone ModTreeItem) []ModTreeItem {
+	if children == nil {
+		return nil
+	}
+	res := make([]ModTreeItem, len(children))
+	for i, child := range children {
+		switch c := child.(type) {
+		case *DashboardInput:
+			if inputClone, ok := inputClones[c]; ok {
+				res[i] = inputClone
+			} else {
+				res[i] = c.Clone()
+			}
+		case *DashboardContainer:
+			res[i] = c.clone(inputClones)
+		case *Dashboard:
+			res[i] = c.Clone()
+		default:
+			res[i] = clonePanel(c)
+		}
+		replaceParent(res[i], parent, parentClone)
+	}
+	return res
+}
+
+// clonePanel returns a copy of the given leaf panel, with its own tags and parents
+func clonePanel(panel ModTreeItem) ModTreeItem {
+	v := reflect.ValueOf(panel).Elem()
+	panelCopy := reflect.New(v.Type())
+	panelCopy.Elem().Set(v)
+	res := panelCopy.Interface().(ModTreeItem)
+
+	impl := res.(HclResource).GetHclResourceImpl()
+	impl.Tags = maps.Clone(impl.Tags)
+	return res
+}
+
+// replaceParent updates the parents of the given item to refer to parentClone rather than parent
+// the parents slice is copied first, as it may be shared with the item the given item was cloned from
+func replaceParent(item, parent, parentClone ModTreeItem) {
+	impl := item.GetModTreeItemImpl()
+	parents := slices.Clone(impl.parents)
+	for i, p := range parents {
+		if p == parent {
+			parents[i] = parentClone
+		}
+	}
+	impl.parents = parents
+}
+
+func cloneDashboardLabelsList(labelsList []*DashboardLabels) []*DashboardLabels {
+	if labelsList == nil {
+		return nil
+	}
+	res := make([]*DashboardLabels, len(labelsList))
+	for i, l := range labelsList {
+		res[i] = &DashboardLabels{
+			Locale:      l.Locale,
+			Title:       cloneStringPointer(l.Title),
+			Description: cloneStringPointer(l.Description),
+		}
+	}
+	return res
+}
+
+func cloneIntPointer(i *int) *int {
+	if i == nil {
+		return nil
+	}
+	return utils.ToIntegerPointer(*i)
+}
+
+func cloneStringPointer(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	return utils.ToStringPointer(*s)
+}
+
 func (d *Dashboard) WalkResources(resourceFunc func(resource HclResource) (bool, error)) error {
 	for _, child := range d.children {
 		continueWalking, err := resourceFunc(child.(HclResource))
@@ -247,6 +478,59 @@ func (d *Dashboard) WalkResources(resourceFunc func(resource HclResource) (bool,
 	return nil
 }
 
+// InheritTagsToChildren merges the dashboard tags into the tags of all child panels, if inherit_tags is set
+// tags defined on a panel take precedence over the dashboard tags
+// NOTE: this must be called after the base properties are set, so children inherited from the base are included
+func (d *Dashboard) InheritTagsToChildren() {
+	if !typehelpers.BoolValue(d.InheritTags) || len(d.Tags) == 0 {
+		return
+	}
+	// children inherited from the base are shared with the base dashboard - replace them with copies
+	// so tagging them does not modify the base panels
+	if d.Base != nil {
+		shared := make(map[HclResource]struct{})
+		_ = d.Base.WalkResources(func(resource HclResource) (bool, error) {
+			shared[resource] = struct{}{}
+			return true, nil
+		})
+		d.children = copySharedPanels(d.children, shared)
+	}
+	resourceFunc := func(resource HclResource) (bool, error) {
+		// with blocks are not panels
+		if _, ok := resource.(*DashboardWith); !ok {
+			impl := resource.GetHclResourceImpl()
+			impl.Tags = utils.MergeMaps(impl.Tags, maps.Clone(d.Tags))
+		}
+		// continue walking
+		return true, nil
+	}
+	// resourceFunc does not return an error
+	_ = d.WalkResources(resourceFunc)
+}
+
+// copySharedPanels returns the given children, with any panels in the shared set replaced by shallow copies
+// the children of copied containers are copied in turn
+func copySharedPanels(children []ModTreeItem, shared map[HclResource]struct{}) []ModTreeItem {
+	res := make([]ModTreeItem, len(children))
+	for i, child := range children {
+		if _, ok := shared[child.(HclResource)]; !ok {
+			res[i] = child
+			continue
+		}
+		if container, ok := child.(*DashboardContainer); ok {
+			containerCopy := container.clone(nil)
+			containerCopy.children = copySharedPanels(container.children, shared)
+			res[i] = containerCopy
+			continue
+		}
+		v := reflect.ValueOf(child).Elem()
+		panelCopy := reflect.New(v.Type())
+		panelCopy.Elem().Set(v)
+		res[i] = panelCopy.Interface().(ModTreeItem)
+	}
+	return res
+}
+
 func (d *Dashboard) ValidateRuntimeDependencies(workspace ResourceMapsProvider) error {
 	d.runtimeDependencyGraph = topsort.NewGraph()
 	// add root node - this will depend on all other nodes
@@ -255,19 +539,40 @@ func (d *Dashboard) ValidateRuntimeDependencies(workspace ResourceMapsProvider)
 	// define a walk function which determines whether the resource has runtime dependencies and if so,
 	// add to the graph
 	resourceFunc := func(resource HclResource) (bool, error) {
-		wp, ok := resource.(WithProvider)
-		if !ok {
-			// continue walking
+		// nested dashboards resolve their dependencies from their own inputs and are validated separately
+		if _, ok := resource.(*Dashboard); ok {
 			return true, nil
 		}
+		// the 'with' blocks and params a resource depends on may be defined by the resource itself or,
+		// if the resource inherits from a base, by the base
+		var owners []HclResource
+		for _, r := range []HclResource{resource, resource.GetBase()} {
+			if !helpers.IsNil(r) {
+				owners = append(owners, r)
+			}
+		}
 
-		if err := d.validateRuntimeDependenciesForResource(resource, workspace); err != nil {
-			return false, err
+		// build the list of resources whose dependencies must be validated
+		// - the resource, its 'with' blocks and, for graphs, flows and hierarchies, its nodes and edges
+		toValidate := []HclResource{resource}
+		for _, owner := range owners {
+			if wp, ok := owner.(WithProvider); ok {
+				for _, w := range wp.GetWiths() {
+					toValidate = append(toValidate, w)
+				}
+			}
+		}
+		if nep, ok := resource.(NodeAndEdgeProvider); ok {
+			for _, n := range nep.GetNodes() {
+				toValidate = append(toValidate, n)
+			}
+			for _, e := range nep.GetEdges() {
+				toValidate = append(toValidate, e)
+			}
 		}
 
-		// if the query provider has any 'with' blocks, add these dependencies as well
-		for _, with := range wp.GetWiths() {
-			if err := d.validateRuntimeDependenciesForResource(with, workspace); err != nil {
+		for _, r := range toValidate {
+			if err := d.validateRuntimeDependenciesForResource(r, owners); err != nil {
 				return false, err
 			}
 		}
@@ -280,42 +585,76 @@ func (d *Dashboard) ValidateRuntimeDependencies(workspace ResourceMapsProvider)
 	}
 
 	// ensure that dependencies can be resolved
+	return d.validateRuntimeDependencyGraph()
+}
+
+// validateRuntimeDependencyGraph ensures the runtime dependency graph can be resolved
+// if the graph contains a cycle, the returned error lists the names of the resources in the cycle
+func (d *Dashboard) validateRuntimeDependencyGraph() error {
 	if _, err := d.runtimeDependencyGraph.TopSort(rootRuntimeDependencyNode); err != nil {
-		return fmt.Errorf("runtime depedencies cannot be resolved: %s", err.Error())
+		// the topsort error is of the form "Cycle error: a -> b -> a"
+		if cycle, isCycle := strings.CutPrefix(err.Error(), "Cycle error: "); isCycle {
+			return fmt.Errorf("runtime dependencies cannot be resolved - dependency cycle: %s", cycle)
+		}
+		return fmt.Errorf("runtime dependencies cannot be resolved: %s", err.Error())
 	}
 	return nil
 }
 
-func (d *Dashboard) validateRuntimeDependenciesForResource(resource HclResource, workspace ResourceMapsProvider) error {
-	// TODO  [node_reuse] re-add parse time validation https://github.com/turbot/steampipe/issues/2925
+// RuntimeDependencyProviders returns all descendants of the dashboard which may have runtime dependencies
+func (d *Dashboard) RuntimeDependencyProviders() []RuntimeDependencyProvider {
+	res := []RuntimeDependencyProvider{}
+	resourceFunc := func(resource HclResource) (bool, error) {
+		if rdp, ok := resource.(RuntimeDependencyProvider); ok {
+			res = append(res, rdp)
+		}
+		// continue walking
+		return true, nil
+	}
+	// NOTE: resourceFunc never returns an error
+	_ = d.WalkResources(resourceFunc)
+	return res
+}
+
+// validateRuntimeDependenciesForResource adds the resource and the sources of its runtime dependencies to the
+// runtime dependency graph, returning an error if the source of a dependency cannot be resolved
+// 'with' and param dependencies are resolved from the given owners (see RuntimeDependency.ValidateSource)
+func (d *Dashboard) validateRuntimeDependenciesForResource(resource HclResource, owners []HclResource) error {
+	rdp, ok := resource.(RuntimeDependencyProvider)
+	if !ok {
+		return nil
+	}
+	runtimeDependencies := rdp.GetRuntimeDependencies()
+	if len(runtimeDependencies) == 0 {
+		return nil
+	}
+	name := resource.Name()
+	d.runtimeDependencyGraph.AddNode(name)
+	// NOTE: AddEdge never returns an error
+	_ = d.runtimeDependencyGraph.AddEdge(rootRuntimeDependencyNode, name)
+
+	for _, dependency := range runtimeDependencies {
+		// try to resolve the dependency source resource
+		source, found := dependency.resolveSource(d, owners...)
+		if !found {
+			// dependencies inherited from a base are provided by the base, so need not be resolvable from this dashboard
+			if !isOwnRuntimeDependency(rdp, dependency) {
+				continue
+			}
+			return fmt.Errorf("cannot resolve runtime dependency '%s' of %s", dependency.PropertyPath.String(), name)
+		}
+		_ = d.runtimeDependencyGraph.AddEdge(name, source.Name())
+	}
 	return nil
-	//rdp := resource.(RuntimeDependencyProvider)
-	//// WHAT ABOUT CHILDREN
-	//if len(runtimeDependencies) == 0 {
-	//	return nil
-	//}
-	//name := resource.Name()
-	//if !d.runtimeDependencyGraph.ContainsNode(name) {
-	//	d.runtimeDependencyGraph.AddNode(name)
-	//}
-	//
-	//for _, dependency := range runtimeDependencies {
-	//	// try to resolve the dependency source resource
-	//	if err := dependency.ValidateSource(d, workspace); err != nil {
-	//		return err
-	//	}
-	//	if err := d.runtimeDependencyGraph.AddEdge(rootRuntimeDependencyNode, name); err != nil {
-	//		return err
-	//	}
-	//	depString := dependency.String()
-	//	if !d.runtimeDependencyGraph.ContainsNode(depString) {
-	//		d.runtimeDependencyGraph.AddNode(depString)
-	//	}
-	//	if err := d.runtimeDependencyGraph.AddEdge(name, dependency.String()); err != nil {
-	//		return err
-	//	}
-	//}
-	//return nil
+}
+
+// isOwnRuntimeDependency returns whether the dependency was declared by the resource, rather than inherited from its base
+func isOwnRuntimeDependency(rdp RuntimeDependencyProvider, dependency *RuntimeDependency) bool {
+	qp, ok := rdp.(QueryProvider)
+	if !ok {
+		return true
+	}
+	return dependency.Provider == &qp.GetQueryProviderImpl().RuntimeDependencyProviderImpl
 }
 
 func (d *Dashboard) GetInput(name string) (*DashboardInput, bool) {
@@ -327,6 +666,65 @@ func (d *Dashboard) GetInputs() map[string]*DashboardInput {
 	return d.selfInputsMap
 }
 
+// ValidateInputValues validates the given input values (keyed by unqualified input name) against the inputs of the dashboard
+// it reports values for unknown inputs, values which are not valid for the input type and options,
+// and required inputs which have no value
+func (d *Dashboard) ValidateInputValues(values map[string]string) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	// validate in name order so the diagnostics are deterministic
+	names := maps.Keys(values)
+	sort.Strings(names)
+	for _, name := range names {
+		input, ok := d.selfInputsMap[name]
+		if !ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("%s has no input '%s'", d.Name(), name),
+				Subject:  &d.DeclRange,
+			})
+			continue
+		}
+		diags = append(diags, input.ValidateValue(values[name])...)
+	}
+
+	inputNames := maps.Keys(d.selfInputsMap)
+	sort.Strings(inputNames)
+	for _, name := range inputNames {
+		input := d.selfInputsMap[name]
+		if input.IsRequired() && strings.TrimSpace(values[name]) == "" {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("missing value for required input '%s'", name),
+				Subject:  &input.DeclRange,
+			})
+		}
+	}
+	return diags
+}
+
+// ResolveUrlParamInputValues returns the input values to use for an execution,
+// populating any inputs which specify a url_param from the given URL query parameters.
+// Explicitly provided input values take precedence over URL query parameter values
+func (d *Dashboard) ResolveUrlParamInputValues(inputValues map[string]any, urlParams map[string]string) map[string]any {
+	if len(urlParams) == 0 {
+		return inputValues
+	}
+	res := make(map[string]any, len(inputValues))
+	for _, input := range d.Inputs {
+		if input.UrlParam == nil {
+			continue
+		}
+		if value, ok := urlParams[*input.UrlParam]; ok {
+			res[input.UnqualifiedName] = value
+		}
+	}
+	for name, value := range inputValues {
+		res[name] = value
+	}
+	return res
+}
+
 func (d *Dashboard) InitInputs() hcl.Diagnostics {
 	// add all our direct child inputs to a map
 	// (we must do this before adding child container inputs to detect dupes)
@@ -365,6 +763,11 @@ func (d *Dashboard) InitInputs() hcl.Diagnostics {
 		}}
 	}
 
+	// ensure the inputs referenced by input defaults exist
+	if diags := d.validateInputDefaults(); diags.HasErrors() {
+		return diags
+	}
+
 	var diags hcl.Diagnostics
 	//  ensure they inputs not have cyclical dependencies
 	if err := d.validateInputDependencies(d.Inputs); err != nil {
@@ -385,6 +788,53 @@ func (d *Dashboard) InitInputs() hcl.Diagnostics {
 	return diags
 }
 
+// validate that any input referenced by the default of an input exists in the dashboard
+func (d *Dashboard) validateInputDefaults() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, input := range d.Inputs {
+		if input.DefaultDependency == nil {
+			continue
+		}
+		if _, ok := d.selfInputsMap[input.DefaultDependency.SourceResourceName()]; !ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("default of %s references input '%s' which does not exist in dashboard '%s'", input.UnqualifiedName, input.DefaultDependency.SourceResourceName(), d.Name()),
+				Subject:  &input.DeclRange,
+			})
+		}
+	}
+	return diags
+}
+
+// ResolveDefaultInputValues returns the input values to use for an execution,
+// populating any inputs which have no value from their default
+// a default which references another input takes the value of that input (which may itself be a default)
+func (d *Dashboard) ResolveDefaultInputValues(inputValues map[string]any) map[string]any {
+	res := maps.Clone(inputValues)
+	if res == nil {
+		res = make(map[string]any)
+	}
+	// resolve in dependency order so the value of any input referenced by a default is resolved first
+	for _, name := range d.inputDependencyOrder {
+		input, ok := d.selfInputsMap[name]
+		if !ok {
+			continue
+		}
+		if _, hasValue := res[name]; hasValue {
+			continue
+		}
+		switch {
+		case input.Default != nil:
+			res[name] = input.Default
+		case input.DefaultDependency != nil:
+			if value, ok := res[input.DefaultDependency.SourceResourceName()]; ok {
+				res[name] = value
+			}
+		}
+	}
+	return res
+}
+
 // populate our input map
 func (d *Dashboard) setInputMap() []string {
 	var duplicates []string
@@ -417,6 +867,18 @@ func (d *Dashboard) setBaseProperties() {
 		d.Width = d.Base.Width
 	}
 
+	if d.InheritTags == nil {
+		d.InheritTags = d.Base.InheritTags
+	}
+
+	if d.AutoRefresh == nil {
+		d.AutoRefresh = d.Base.AutoRefresh
+	}
+
+	if d.LabelsList == nil {
+		d.LabelsList = d.Base.LabelsList
+	}
+
 	if len(d.children) == 0 {
 		d.children = d.Base.children
 		d.ChildNames = d.Base.ChildNames
@@ -451,15 +913,17 @@ func (d *Dashboard) addBaseInputs(baseInputs []*DashboardInput) {
 }
 
 // ensure that dependencies between inputs are resolveable
+// if so, store the input dependency order and the inputs each input depends on
 func (d *Dashboard) validateInputDependencies(inputs []*DashboardInput) error {
 	dependencyGraph := topsort.NewGraph()
 	rootDependencyNode := "dashboard"
 	dependencyGraph.AddNode(rootDependencyNode)
-	for _, i := range inputs {
-		for _, runtimeDep := range i.GetRuntimeDependencies() {
-			depName := runtimeDep.PropertyPath.ToResourceName()
-			to := depName
-			from := i.UnqualifiedName
+	// map of the direct dependencies of each node
+	edges := make(map[string][]string)
+
+	addDependencies := func(from string, runtimeDependencies map[string]*RuntimeDependency) error {
+		for _, runtimeDep := range runtimeDependencies {
+			to := runtimeDep.PropertyPath.ToResourceName()
 			if !dependencyGraph.ContainsNode(from) {
 				dependencyGraph.AddNode(from)
 			}
@@ -469,13 +933,132 @@ func (d *Dashboard) validateInputDependencies(inputs []*DashboardInput) error {
 			if err := dependencyGraph.AddEdge(from, to); err != nil {
 				return err
 			}
-			if err := dependencyGraph.AddEdge(rootDependencyNode, i.UnqualifiedName); err != nil {
+			if err := dependencyGraph.AddEdge(rootDependencyNode, from); err != nil {
+				return err
+			}
+			edges[from] = append(edges[from], to)
+		}
+		return nil
+	}
+
+	inputNames := make(map[string]bool, len(inputs))
+	for _, i := range inputs {
+		inputNames[i.UnqualifiedName] = true
+		// add all inputs to the graph so the dependency order includes inputs with no dependencies
+		if err := dependencyGraph.AddEdge(rootDependencyNode, i.UnqualifiedName); err != nil {
+			return err
+		}
+		if err := addDependencies(i.UnqualifiedName, i.GetRuntimeDependencies()); err != nil {
+			return err
+		}
+		// a default which references another input is also a dependency on that input
+		if i.DefaultDependency != nil {
+			if err := addDependencies(i.UnqualifiedName, map[string]*RuntimeDependency{"default": i.DefaultDependency}); err != nil {
 				return err
 			}
 		}
 	}
+	// dashboard level 'with' blocks may be used by input queries, and may themselves depend on inputs
+	// - include them in the graph so that any cycle between a 'with' and an input is detected
+	for _, w := range d.GetWiths() {
+		if err := addDependencies(w.UnqualifiedName, w.GetRuntimeDependencies()); err != nil {
+			return err
+		}
+	}
 
 	// now verify we can get a dependency order
-	_, err := dependencyGraph.TopSort(rootDependencyNode)
-	return err
+	if _, err := dependencyGraph.TopSort(rootDependencyNode); err != nil {
+		return err
+	}
+
+	d.inputDependencies = make(map[string][]string, len(inputNames))
+	for name := range inputNames {
+		d.inputDependencies[name] = getInputDependencies(name, edges, inputNames)
+	}
+	d.inputDependencyOrder = orderInputsByDependency(inputs, d.inputDependencies)
+	return nil
+}
+
+// orderInputsByDependency returns the unqualified names of the inputs, ordered such that each input appears after
+// all inputs it depends on - where there is a choice, the input declared first is taken
+// NOTE: the dependencies must have been verified to be acyclic
+func orderInputsByDependency(inputs []*DashboardInput, inputDependencies map[string][]string) []string {
+	res := make([]string, 0, len(inputs))
+	added := make(map[string]bool, len(inputs))
+	for len(res) < len(inputs) {
+		progressed := false
+		for _, i := range inputs {
+			name := i.UnqualifiedName
+			if added[name] || !allAdded(inputDependencies[name], added) {
+				continue
+			}
+			res = append(res, name)
+			added[name] = true
+			progressed = true
+			// restart from the first declared input, as it may now be ready
+			break
+		}
+		if !progressed {
+			break
+		}
+	}
+	return res
+}
+
+func allAdded(names []string, added map[string]bool) bool {
+	for _, name := range names {
+		if !added[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// getInputDependencies returns the sorted names of the inputs the given node depends on,
+// either directly or via intermediate (non-input) nodes, e.g. dashboard level 'with' blocks
+func getInputDependencies(name string, edges map[string][]string, inputNames map[string]bool) []string {
+	var res []string
+	visited := map[string]bool{name: true}
+	toVisit := append([]string{}, edges[name]...)
+	for len(toVisit) > 0 {
+		dep := toVisit[0]
+		toVisit = toVisit[1:]
+		if visited[dep] {
+			continue
+		}
+		visited[dep] = true
+		if inputNames[dep] {
+			res = append(res, dep)
+			continue
+		}
+		toVisit = append(toVisit, edges[dep]...)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// InputDependencyOrder returns the unqualified names of all dashboard inputs,
+// ordered such that each input appears after all inputs it depends on
+// this may be used to render cascading inputs, where the options of an input depend on the value of another input
+func (d *Dashboard) InputDependencyOrder() []string {
+	return d.inputDependencyOrder
+}
+
+// InputDependencies returns the unqualified names of the inputs the given input depends on,
+// either directly or via a dashboard level 'with'
+func (d *Dashboard) InputDependencies(inputName string) []string {
+	return d.inputDependencies[inputName]
+}
+
+// GetInputsDependingOn returns the unqualified names of the inputs which depend on the given input,
+// either directly or via a dashboard level 'with'
+// when the value of the given input changes, the options of these inputs must be re-evaluated
+func (d *Dashboard) GetInputsDependingOn(changedInputName string) []string {
+	var res []string
+	for _, input := range d.Inputs {
+		if input.DependsOnInput(changedInputName) || helpers.StringSliceContains(d.inputDependencies[input.UnqualifiedName], changedInputName) {
+			res = append(res, input.UnqualifiedName)
+		}
+	}
+	return res
 }
@@ -62,7 +62,8 @@ func (c *DashboardCard) Equals(other *DashboardCard) bool {
 // OnDecoded implements HclResource
 func (c *DashboardCard) OnDecoded(block *hcl.Block, resourceMapProvider ResourceMapsProvider) hcl.Diagnostics {
 	c.setBaseProperties()
-	return c.QueryProviderImpl.OnDecoded(block, resourceMapProvider)
+	diags := validateWidth(c, c.Width)
+	return append(diags, c.QueryProviderImpl.OnDecoded(block, resourceMapProvider)...)
 }
 
 func (c *DashboardCard) Diff(other *DashboardCard) *DashboardTreeItemDiffs {
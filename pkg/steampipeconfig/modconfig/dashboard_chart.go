@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	typehelpers "github.com/turbot/go-kit/types"
 	"github.com/turbot/pipe-fittings/hclhelpers"
 	"github.com/turbot/steampipe/pkg/utils"
@@ -62,15 +63,39 @@ func (c *DashboardChart) Equals(other *DashboardChart) bool {
 // OnDecoded implements HclResource
 func (c *DashboardChart) OnDecoded(block *hcl.Block, resourceMapProvider ResourceMapsProvider) hcl.Diagnostics {
 	c.setBaseProperties()
+	diags := validateWidth(c, c.Width)
 	// populate series map
 	if len(c.SeriesList) > 0 {
 		c.Series = make(map[string]*DashboardChartSeries, len(c.SeriesList))
 		for _, s := range c.SeriesList {
-			s.OnDecoded()
+			diags = append(diags, s.OnDecoded(seriesColorRange(block, s.Name, c.GetDeclRange()))...)
 			c.Series[s.Name] = s
 		}
 	}
-	return c.QueryProviderImpl.OnDecoded(block, resourceMapProvider)
+	return append(diags, c.QueryProviderImpl.OnDecoded(block, resourceMapProvider)...)
+}
+
+// seriesColorRange returns the range of the color attribute of the named series block
+// if the series is not declared in the given block (e.g. it is inherited from the base) the default range is returned
+func seriesColorRange(block *hcl.Block, seriesName string, defaultRange *hcl.Range) *hcl.Range {
+	if block == nil {
+		return defaultRange
+	}
+	body, ok := block.Body.(*hclsyntax.Body)
+	if !ok {
+		return defaultRange
+	}
+	for _, b := range body.Blocks {
+		if b.Type != "series" || len(b.Labels) == 0 || b.Labels[0] != seriesName {
+			continue
+		}
+		if attr, ok := b.Body.Attributes["color"]; ok {
+			return &attr.SrcRange
+		}
+		r := b.DefRange()
+		return &r
+	}
+	return defaultRange
 }
 
 func (c *DashboardChart) Diff(other *DashboardChart) *DashboardTreeItemDiffs {
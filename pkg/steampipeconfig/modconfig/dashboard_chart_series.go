@@ -1,10 +1,21 @@
 package modconfig
 
-import "github.com/turbot/steampipe/pkg/utils"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// hexColorRegex matches #rgb, #rrggbb and #rrggbbaa colors
+var hexColorRegex = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
 
 type DashboardChartSeries struct {
 	Name       string                                `hcl:"name,label" json:"name"`
 	Title      *string                               `cty:"title" hcl:"title" json:"title,omitempty"`
+	Label      *string                               `cty:"label" hcl:"label" json:"label,omitempty"`
 	Color      *string                               `cty:"color" hcl:"color" json:"color,omitempty"`
 	Points     map[string]*DashboardChartSeriesPoint `cty:"points" json:"points,omitempty"`
 	PointsList []*DashboardChartSeriesPoint          `hcl:"point,block" json:"-"`
@@ -26,14 +37,32 @@ func (s *DashboardChartSeries) Equals(other *DashboardChartSeries) bool {
 
 	return utils.SafeStringsEqual(s.Name, other.Name) &&
 		utils.SafeStringsEqual(s.Title, other.Title) &&
+		utils.SafeStringsEqual(s.Label, other.Label) &&
 		utils.SafeStringsEqual(s.Color, other.Color)
 }
 
-func (s *DashboardChartSeries) OnDecoded() {
+// OnDecoded populates the points map and validates the series color
+// colorRange is used as the subject of any color diagnostic
+func (s *DashboardChartSeries) OnDecoded(colorRange *hcl.Range) hcl.Diagnostics {
 	if len(s.PointsList) > 0 {
 		s.Points = make(map[string]*DashboardChartSeriesPoint, len(s.PointsList))
 		for _, p := range s.PointsList {
 			s.Points[p.Name] = p
 		}
 	}
+	return s.validateColor(colorRange)
+}
+
+// validateColor verifies that if the color is specified as a hex value, it is well-formed
+// (named colors such as 'red' or 'alert' are passed through to the UI unchanged)
+func (s *DashboardChartSeries) validateColor(colorRange *hcl.Range) hcl.Diagnostics {
+	if s.Color == nil || !strings.HasPrefix(*s.Color, "#") || hexColorRegex.MatchString(*s.Color) {
+		return nil
+	}
+	return hcl.Diagnostics{&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf("series '%s' has invalid color '%s'", s.Name, *s.Color),
+		Detail:   "Hex colors must be of the form #rgb, #rrggbb or #rrggbbaa.",
+		Subject:  colorRange,
+	}}
 }
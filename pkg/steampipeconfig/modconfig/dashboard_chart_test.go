@@ -0,0 +1,56 @@
+package modconfig
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+type seriesColorRangeTest struct {
+	seriesName string
+	// the expected start line of the diagnostic subject
+	expectedLine int
+}
+
+const seriesColorRangeSource = `chart "c1" {
+  sql = "select 1"
+  series "s1" {
+    label = "Series 1"
+    color = "#ff00zz"
+  }
+  series "s2" {
+    label = "Series 2"
+  }
+}`
+
+var testCasesSeriesColorRange = map[string]seriesColorRangeTest{
+	"color attribute": {
+		seriesName:   "s1",
+		expectedLine: 5,
+	},
+	"no color attribute": {
+		seriesName:   "s2",
+		expectedLine: 7,
+	},
+	"series not declared in block": {
+		seriesName:   "s3",
+		expectedLine: 1,
+	},
+}
+
+func TestSeriesColorRange(t *testing.T) {
+	file, diags := hclsyntax.ParseConfig([]byte(seriesColorRangeSource), "test.sp", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	block := file.Body.(*hclsyntax.Body).Blocks[0].AsHCLBlock()
+	chartRange := block.DefRange
+
+	for name, test := range testCasesSeriesColorRange {
+		subject := seriesColorRange(block, test.seriesName, &chartRange)
+		if subject.Start.Line != test.expectedLine {
+			t.Errorf("Test %s FAILED. Expected subject to start on line %d, got %d", name, test.expectedLine, subject.Start.Line)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package modconfig
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/stevenle/topsort"
@@ -9,10 +10,33 @@ import (
 	"github.com/turbot/pipe-fittings/hclhelpers"
 	"github.com/turbot/steampipe/pkg/utils"
 	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/exp/maps"
 )
 
 // TODO [node_reuse] add DashboardLeafNodeImpl
 
+// the number of columns in the dashboard layout grid - a panel with no width occupies the full grid width
+const dashboardGridWidth = 12
+
+// the bounds of the width of a dashboard, container or panel
+const (
+	DashboardMinWidth = 1
+	DashboardMaxWidth = dashboardGridWidth
+)
+
+// validateWidth validates the width of a dashboard, container or panel, if specified, is within the bounds of the grid
+func validateWidth(resource HclResource, width *int) hcl.Diagnostics {
+	if width == nil || (*width >= DashboardMinWidth && *width <= DashboardMaxWidth) {
+		return nil
+	}
+	return hcl.Diagnostics{&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf("%s has invalid width %d", resource.Name(), *width),
+		Detail:   fmt.Sprintf("width must be between %d and %d", DashboardMinWidth, DashboardMaxWidth),
+		Subject:  resource.GetDeclRange(),
+	}}
+}
+
 // DashboardContainer is a struct representing the Dashboard and Container resource
 type DashboardContainer struct {
 	ResourceWithMetadataImpl
@@ -21,9 +45,13 @@ type DashboardContainer struct {
 	// required to allow partial decoding
 	Remain hcl.Body `hcl:",remain" json:"-"`
 
-	Width   *int              `cty:"width" hcl:"width"  column:"width,text"`
-	Display *string           `cty:"display" hcl:"display"`
-	Inputs  []*DashboardInput `cty:"inputs" column:"inputs,jsonb"`
+	Width   *int    `cty:"width" hcl:"width"  column:"width,text"`
+	Display *string `cty:"display" hcl:"display"`
+	// if set, the children of the container are laid out horizontally in a single row
+	Row *bool `cty:"row" hcl:"row" column:"row,bool"`
+	// if set, the panels of the container are not executed until the container is loaded (e.g. when scrolled into view)
+	Lazy   *bool             `cty:"lazy" hcl:"lazy" column:"lazy,bool"`
+	Inputs []*DashboardInput `cty:"inputs" column:"inputs,jsonb"`
 	// store children in a way which can be serialised via cty
 	ChildNames []string `cty:"children" column:"children,jsonb"`
 
@@ -61,7 +89,42 @@ func (c *DashboardContainer) OnDecoded(block *hcl.Block, _ ResourceMapsProvider)
 	for i, child := range c.children {
 		c.ChildNames[i] = child.Name()
 	}
-	return nil
+	diags := validateWidth(c, c.Width)
+	return append(diags, c.validateRowWidths()...)
+}
+
+// IsRow returns whether the children of the container are laid out in a single row
+func (c *DashboardContainer) IsRow() bool {
+	return c.Row != nil && *c.Row
+}
+
+// IsLazy returns whether execution of the panels of the container is deferred until the container is loaded
+func (c *DashboardContainer) IsLazy() bool {
+	return c.Lazy != nil && *c.Lazy
+}
+
+// if the container is a row, validate the combined widths of its children do not exceed the grid width
+func (c *DashboardContainer) validateRowWidths() hcl.Diagnostics {
+	if !c.IsRow() {
+		return nil
+	}
+	totalWidth := 0
+	for _, child := range c.children {
+		width := dashboardGridWidth
+		if leafNode, ok := child.(DashboardLeafNode); ok && leafNode.GetWidth() > 0 {
+			width = leafNode.GetWidth()
+		}
+		totalWidth += width
+	}
+	if totalWidth <= dashboardGridWidth {
+		return nil
+	}
+	return hcl.Diagnostics{&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf("%s is a row but the combined width of its children is %d, which exceeds the grid width of %d", c.Name(), totalWidth, dashboardGridWidth),
+		Detail:   "a child with no width occupies the full grid width",
+		Subject:  &c.DeclRange,
+	}}
 }
 
 // GetWidth implements DashboardLeafNode
@@ -104,6 +167,14 @@ func (c *DashboardContainer) Diff(other *DashboardContainer) *DashboardTreeItemD
 		res.AddPropertyDiff("Display")
 	}
 
+	if c.IsRow() != other.IsRow() {
+		res.AddPropertyDiff("Row")
+	}
+
+	if c.IsLazy() != other.IsLazy() {
+		res.AddPropertyDiff("Lazy")
+	}
+
 	res.populateChildDiffs(c, other)
 	return res
 }
@@ -116,6 +187,30 @@ func (c *DashboardContainer) AddChild(child ModTreeItem) {
 	c.children = append(c.children, child)
 }
 
+// clone returns a copy of the container, substituting the cloned inputs of the parent dashboard
+// (see Dashboard.Clone)
+func (c *DashboardContainer) clone(inputClones map[*DashboardInput]*DashboardInput) *DashboardContainer {
+	clone := &DashboardContainer{
+		ResourceWithMetadataImpl: c.ResourceWithMetadataImpl,
+		ModTreeItemImpl:          c.ModTreeItemImpl,
+		Width:                    cloneIntPointer(c.Width),
+		Display:                  cloneStringPointer(c.Display),
+		Row:                      c.Row,
+		Lazy:                     c.Lazy,
+		ChildNames:               slices.Clone(c.ChildNames),
+	}
+	clone.Tags = maps.Clone(c.Tags)
+	clone.parents = slices.Clone(c.parents)
+	for _, input := range c.Inputs {
+		if inputClone, ok := inputClones[input]; ok {
+			input = inputClone
+		}
+		clone.Inputs = append(clone.Inputs, input)
+	}
+	clone.children = cloneDashboardChildren(c.children, inputClones, c, clone)
+	return clone
+}
+
 func (c *DashboardContainer) WalkResources(resourceFunc func(resource HclResource) (bool, error)) error {
 	for _, child := range c.children {
 		continueWalking, err := resourceFunc(child.(HclResource))
@@ -0,0 +1,155 @@
+package modconfig
+
+import (
+	"fmt"
+	"sort"
+
+	"golang.org/x/exp/maps"
+)
+
+// ExtractPanel returns a new dashboard containing just the panel with the given unqualified name (and its children),
+// along with the dashboard inputs and 'with' blocks the panel depends on, either directly or transitively
+// this is used to display a single panel of a dashboard as a standalone dashboard
+func (d *Dashboard) ExtractPanel(unqualifiedName string) (*Dashboard, error) {
+	panel := d.getPanel(unqualifiedName)
+	if panel == nil {
+		return nil, fmt.Errorf("%s has no panel '%s'", d.Name(), unqualifiedName)
+	}
+
+	inputs, withs, err := d.getPanelDependencies(panel)
+	if err != nil {
+		return nil, err
+	}
+
+	panelImpl := panel.GetHclResourceImpl()
+	shortName := fmt.Sprintf("%s_%s", d.ShortName, panelImpl.ShortName)
+	title := d.Title
+	if panelImpl.Title != nil {
+		title = panelImpl.Title
+	}
+	extracted := &Dashboard{
+		ResourceWithMetadataImpl: ResourceWithMetadataImpl{
+			metadata: d.metadata,
+		},
+		ModTreeItemImpl: ModTreeItemImpl{
+			HclResourceImpl: HclResourceImpl{
+				ShortName:       shortName,
+				FullName:        fmt.Sprintf("%s.%s.%s", d.Mod.ShortName, BlockTypeDashboard, shortName),
+				UnqualifiedName: fmt.Sprintf("%s.%s", BlockTypeDashboard, shortName),
+				Title:           title,
+				Description:     d.Description,
+				Tags:            d.Tags,
+				DeclRange:       d.DeclRange,
+				blockType:       BlockTypeDashboard,
+			},
+			Mod: d.Mod,
+		},
+		Display: d.Display,
+	}
+	extracted.setUrlPath()
+
+	// add the withs in name order, then the inputs in the order they are declared in the source dashboard
+	withNames := maps.Keys(withs)
+	sort.Strings(withNames)
+	for _, name := range withNames {
+		extracted.AddChild(withs[name])
+	}
+	for _, input := range d.Inputs {
+		if _, ok := inputs[input.UnqualifiedName]; ok {
+			extracted.AddChild(input)
+		}
+	}
+	extracted.AddChild(panel)
+
+	extracted.setInputMap()
+	if err := extracted.validateInputDependencies(extracted.Inputs); err != nil {
+		return nil, fmt.Errorf("failed to resolve input dependency order for panel '%s': %s", unqualifiedName, err.Error())
+	}
+	return extracted, nil
+}
+
+// getPanel returns the descendant panel of the dashboard with the given unqualified name, if any
+// inputs and 'with' blocks are not panels
+func (d *Dashboard) getPanel(unqualifiedName string) ModTreeItem {
+	var panel ModTreeItem
+	resourceFunc := func(resource HclResource) (bool, error) {
+		switch resource.(type) {
+		case *DashboardInput, *DashboardWith:
+			return true, nil
+		}
+		if resource.GetUnqualifiedName() == unqualifiedName {
+			panel = resource.(ModTreeItem)
+			// stop walking
+			return false, nil
+		}
+		return true, nil
+	}
+	// NOTE: resourceFunc never returns an error
+	_ = d.WalkResources(resourceFunc)
+	return panel
+}
+
+// getPanelDependencies returns the dashboard inputs and dashboard level 'with' blocks which the panel
+// (or any of its children) depends on, either directly or via another input or 'with'
+// an error is returned if a dependency cannot be resolved from the dashboard
+func (d *Dashboard) getPanelDependencies(panel ModTreeItem) (map[string]*DashboardInput, map[string]*DashboardWith, error) {
+	inputs := make(map[string]*DashboardInput)
+	withs := make(map[string]*DashboardWith)
+
+	// build the list of resources whose dependencies must be resolved - the panel, its children and their 'with' blocks
+	// the 'with' blocks of the panel and its children are resolved from the panel itself
+	var toResolve []HclResource
+	panelWiths := make(map[string]bool)
+	addResource := func(resource HclResource) {
+		toResolve = append(toResolve, resource)
+		if wp, ok := resource.(WithProvider); ok {
+			for _, w := range wp.GetWiths() {
+				toResolve = append(toResolve, w)
+				panelWiths[w.UnqualifiedName] = true
+			}
+		}
+	}
+	addResource(panel.(HclResource))
+	if container, ok := panel.(*DashboardContainer); ok {
+		// NOTE: resourceFunc never returns an error
+		_ = container.WalkResources(func(resource HclResource) (bool, error) {
+			addResource(resource)
+			return true, nil
+		})
+	}
+
+	for len(toResolve) > 0 {
+		resource := toResolve[0]
+		toResolve = toResolve[1:]
+		rdp, ok := resource.(RuntimeDependencyProvider)
+		if !ok {
+			continue
+		}
+		for _, dep := range rdp.GetRuntimeDependencies() {
+			name := dep.SourceResourceName()
+			switch dep.PropertyPath.ItemType {
+			case BlockTypeInput:
+				if _, ok := inputs[name]; ok {
+					continue
+				}
+				input, ok := d.GetInput(name)
+				if !ok {
+					return nil, nil, fmt.Errorf("cannot resolve dependency '%s' of %s", dep.PropertyPath.String(), resource.Name())
+				}
+				inputs[name] = input
+				toResolve = append(toResolve, input)
+			case BlockTypeWith:
+				if _, ok := withs[name]; ok || panelWiths[name] {
+					continue
+				}
+				with, ok := d.GetWith(name)
+				if !ok {
+					return nil, nil, fmt.Errorf("cannot resolve dependency '%s' of %s", dep.PropertyPath.String(), resource.Name())
+				}
+				withs[name] = with
+				toResolve = append(toResolve, with)
+			}
+		}
+	}
+	return inputs, withs, nil
+}
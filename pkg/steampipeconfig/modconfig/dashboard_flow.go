@@ -71,7 +71,8 @@ func (f *DashboardFlow) OnDecoded(block *hcl.Block, resourceMapProvider Resource
 	if len(f.Edges) > 0 {
 		f.EdgeNames = f.Edges.Names()
 	}
-	return f.QueryProviderImpl.OnDecoded(block, resourceMapProvider)
+	diags := validateWidth(f, f.Width)
+	return append(diags, f.QueryProviderImpl.OnDecoded(block, resourceMapProvider)...)
 }
 
 // TODO [node_reuse] Add DashboardLeafNodeImpl and move this there https://github.com/turbot/steampipe/issues/2926
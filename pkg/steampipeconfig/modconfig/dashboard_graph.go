@@ -73,7 +73,8 @@ func (g *DashboardGraph) OnDecoded(block *hcl.Block, resourceMapProvider Resourc
 	if len(g.Edges) > 0 {
 		g.EdgeNames = g.Edges.Names()
 	}
-	return g.QueryProviderImpl.OnDecoded(block, resourceMapProvider)
+	diags := validateWidth(g, g.Width)
+	return append(diags, g.QueryProviderImpl.OnDecoded(block, resourceMapProvider)...)
 }
 
 // TODO [node_reuse] Add DashboardLeafNodeImpl and move this there https://github.com/turbot/steampipe/issues/2926
@@ -72,7 +72,8 @@ func (h *DashboardHierarchy) OnDecoded(block *hcl.Block, resourceMapProvider Res
 	if len(h.Edges) > 0 {
 		h.EdgeNames = h.Edges.Names()
 	}
-	return h.QueryProviderImpl.OnDecoded(block, resourceMapProvider)
+	diags := validateWidth(h, h.Width)
+	return append(diags, h.QueryProviderImpl.OnDecoded(block, resourceMapProvider)...)
 }
 
 // TODO [node_reuse] Add DashboardLeafNodeImpl and move this there https://github.com/turbot/steampipe/issues/2926
@@ -57,7 +57,8 @@ func (i *DashboardImage) Equals(other *DashboardImage) bool {
 // OnDecoded implements HclResource
 func (i *DashboardImage) OnDecoded(block *hcl.Block, resourceMapProvider ResourceMapsProvider) hcl.Diagnostics {
 	i.setBaseProperties()
-	return i.QueryProviderImpl.OnDecoded(block, resourceMapProvider)
+	diags := validateWidth(i, i.Width)
+	return append(diags, i.QueryProviderImpl.OnDecoded(block, resourceMapProvider)...)
 }
 
 func (i *DashboardImage) Diff(other *DashboardImage) *DashboardTreeItemDiffs {
@@ -2,6 +2,9 @@ package modconfig
 
 import (
 	"fmt"
+	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	typehelpers "github.com/turbot/go-kit/types"
@@ -10,6 +13,24 @@ import (
 	"github.com/zclconf/go-cty/cty"
 )
 
+// supported input types
+const (
+	DashboardInputTypeSelect      = "select"
+	DashboardInputTypeMultiSelect = "multiselect"
+	// combo inputs accept either one of the options or free text
+	DashboardInputTypeCombo      = "combo"
+	DashboardInputTypeMultiCombo = "multicombo"
+	DashboardInputTypeText       = "text"
+)
+
+var dashboardInputTypes = []string{
+	DashboardInputTypeSelect,
+	DashboardInputTypeMultiSelect,
+	DashboardInputTypeCombo,
+	DashboardInputTypeMultiCombo,
+	DashboardInputTypeText,
+}
+
 // DashboardInput is a struct representing a leaf dashboard node
 type DashboardInput struct {
 	ResourceWithMetadataImpl
@@ -18,10 +39,22 @@ type DashboardInput struct {
 	// required to allow partial decoding
 	Remain hcl.Body `hcl:",remain" json:"-"`
 
-	DashboardName string                  `column:"dashboard,text" json:"-"`
-	Label         *string                 `cty:"label" hcl:"label" column:"label,text" json:"label,omitempty"`
-	Placeholder   *string                 `cty:"placeholder" hcl:"placeholder" column:"placeholder,text" json:"placeholder,omitempty"`
-	Options       []*DashboardInputOption `cty:"options" hcl:"option,block" json:"options,omitempty"`
+	DashboardName string  `column:"dashboard,text" json:"-"`
+	Label         *string `cty:"label" hcl:"label" column:"label,text" json:"label,omitempty"`
+	Placeholder   *string `cty:"placeholder" hcl:"placeholder" column:"placeholder,text" json:"placeholder,omitempty"`
+	Help          *string `cty:"help" hcl:"help" column:"help,text" json:"help,omitempty"`
+	// the name of a URL query parameter which may be used to provide the input value
+	UrlParam *string                 `cty:"url_param" hcl:"url_param" column:"url_param,text" json:"url_param,omitempty"`
+	Options  []*DashboardInputOption `cty:"options" hcl:"option,block" json:"options,omitempty"`
+	// if set, a value must be provided for the input before the dashboard is executed
+	Required *bool `cty:"required" hcl:"required" column:"required,bool" json:"required,omitempty"`
+	// the value of the input if no value is provided - either a string or, for multi-value inputs, a list of strings
+	// this is decoded manually as it may reference another input
+	Default any `column:"default_value,jsonb" json:"default,omitempty"`
+	// if the default references another input, e.g. default = self.input.region.value, the dependency on that input
+	DefaultDependency *RuntimeDependency `json:"-"`
+	// validation rules applied to the supplied value of the input
+	Validations []*DashboardInputValidation `hcl:"validation,block" json:"-"`
 	// tactical - exists purely so we can put "unqualified_name" in the snbapshot panel for the input
 	// TODO remove when input names are refactored https://github.com/turbot/steampipe/issues/2863
 	InputName string `cty:"input_name" json:"unqualified_name"`
@@ -69,8 +102,14 @@ func (i *DashboardInput) Clone() *DashboardInput {
 		Type:                     i.Type,
 		Label:                    i.Label,
 		Placeholder:              i.Placeholder,
+		Help:                     i.Help,
+		UrlParam:                 i.UrlParam,
+		Required:                 i.Required,
+		Default:                  i.Default,
+		DefaultDependency:        i.DefaultDependency,
 		Display:                  i.Display,
 		Options:                  i.Options,
+		Validations:              slices.Clone(i.Validations),
 		InputName:                i.InputName,
 		dashboard:                i.dashboard,
 	}
@@ -84,7 +123,134 @@ func (i *DashboardInput) Equals(other *DashboardInput) bool {
 // OnDecoded implements HclResource
 func (i *DashboardInput) OnDecoded(block *hcl.Block, resourceMapProvider ResourceMapsProvider) hcl.Diagnostics {
 	i.setBaseProperties()
-	return i.QueryProviderImpl.OnDecoded(block, resourceMapProvider)
+	diags := i.validateUrlParam()
+	diags = append(diags, i.validateType()...)
+	diags = append(diags, i.validateDefault()...)
+	diags = append(diags, validateWidth(i, i.Width)...)
+	return append(diags, i.QueryProviderImpl.OnDecoded(block, resourceMapProvider)...)
+}
+
+// validate that the type, if specified, is a supported input type
+func (i *DashboardInput) validateType() hcl.Diagnostics {
+	if i.Type == nil || slices.Contains(dashboardInputTypes, *i.Type) {
+		return nil
+	}
+	return hcl.Diagnostics{&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf("%s has invalid type '%s'", i.Name(), *i.Type),
+		Detail:   fmt.Sprintf("type must be one of: %s", strings.Join(dashboardInputTypes, ", ")),
+		Subject:  &i.DeclRange,
+	}}
+}
+
+// validate that the default, if specified, is a string - or for multi-value inputs, a string or a list of strings
+func (i *DashboardInput) validateDefault() hcl.Diagnostics {
+	switch d := i.Default.(type) {
+	case nil, string:
+		return nil
+	case []any:
+		if i.AllowsMultipleValues() {
+			return nil
+		}
+		return hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("%s has invalid default", i.Name()),
+			Detail:   fmt.Sprintf("a list default is only supported for inputs of type %s or %s", DashboardInputTypeMultiSelect, DashboardInputTypeMultiCombo),
+			Subject:  &i.DeclRange,
+		}}
+	default:
+		return hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("%s has invalid default", i.Name()),
+			Detail:   fmt.Sprintf("unsupported default value %v", d),
+			Subject:  &i.DeclRange,
+		}}
+	}
+}
+
+// AllowsMultipleValues returns whether the input accepts a list of values
+func (i *DashboardInput) AllowsMultipleValues() bool {
+	switch typehelpers.SafeString(i.Type) {
+	case DashboardInputTypeMultiSelect, DashboardInputTypeMultiCombo:
+		return true
+	}
+	return false
+}
+
+// AllowsFreeText returns whether the input accepts values other than its options
+func (i *DashboardInput) AllowsFreeText() bool {
+	switch typehelpers.SafeString(i.Type) {
+	case DashboardInputTypeText, DashboardInputTypeCombo, DashboardInputTypeMultiCombo:
+		return true
+	}
+	return false
+}
+
+// IsRequired returns whether a value must be provided for the input
+func (i *DashboardInput) IsRequired() bool {
+	return i.Required != nil && *i.Required
+}
+
+// ValidateValue validates the given value against the type, options and validation rules of the input
+// values of multi-value inputs are comma separated
+// NOTE: options provided by the input query are not known until execution so only static options are validated
+func (i *DashboardInput) ValidateValue(value string) hcl.Diagnostics {
+	diags := i.validateValueOptions(value)
+
+	// a multi-value input is validated as a list of values
+	validationValue := cty.StringVal(value)
+	if i.AllowsMultipleValues() {
+		var values []cty.Value
+		for _, v := range strings.Split(value, ",") {
+			values = append(values, cty.StringVal(strings.TrimSpace(v)))
+		}
+		validationValue = cty.ListVal(values)
+	}
+	for _, validation := range i.Validations {
+		diags = append(diags, validation.Validate(i, validationValue)...)
+	}
+	return diags
+}
+
+// validate the value is one of the static options of the input (unless the input accepts free text)
+func (i *DashboardInput) validateValueOptions(value string) hcl.Diagnostics {
+	if len(i.Options) == 0 || i.AllowsFreeText() {
+		return nil
+	}
+
+	values := []string{value}
+	if i.AllowsMultipleValues() {
+		values = strings.Split(value, ",")
+	}
+
+	optionNames := make([]string, len(i.Options))
+	for idx, o := range i.Options {
+		optionNames[idx] = o.Name
+	}
+	var diags hcl.Diagnostics
+	for _, v := range values {
+		if !slices.Contains(optionNames, strings.TrimSpace(v)) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("invalid value '%s' for input '%s'", strings.TrimSpace(v), i.UnqualifiedName),
+				Detail:   fmt.Sprintf("value must be one of: %s", strings.Join(optionNames, ", ")),
+				Subject:  &i.DeclRange,
+			})
+		}
+	}
+	return diags
+}
+
+// validate that a url_param, if specified, is not empty
+func (i *DashboardInput) validateUrlParam() hcl.Diagnostics {
+	if i.UrlParam == nil || strings.TrimSpace(*i.UrlParam) != "" {
+		return nil
+	}
+	return hcl.Diagnostics{&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf("%s has an empty url_param", i.Name()),
+		Subject:  &i.DeclRange,
+	}}
 }
 
 func (i *DashboardInput) Diff(other *DashboardInput) *DashboardTreeItemDiffs {
@@ -105,6 +271,32 @@ func (i *DashboardInput) Diff(other *DashboardInput) *DashboardTreeItemDiffs {
 		res.AddPropertyDiff("Placeholder")
 	}
 
+	if !utils.SafeStringsEqual(i.Help, other.Help) {
+		res.AddPropertyDiff("Help")
+	}
+
+	if !utils.SafeStringsEqual(i.UrlParam, other.UrlParam) {
+		res.AddPropertyDiff("UrlParam")
+	}
+
+	if i.IsRequired() != other.IsRequired() {
+		res.AddPropertyDiff("Required")
+	}
+
+	if !reflect.DeepEqual(i.Default, other.Default) || !i.defaultDependencyEquals(other) {
+		res.AddPropertyDiff("Default")
+	}
+
+	if len(i.Validations) != len(other.Validations) {
+		res.AddPropertyDiff("Validations")
+	} else {
+		for idx, v := range i.Validations {
+			if !v.Equals(other.Validations[idx]) {
+				res.AddPropertyDiff("Validations")
+			}
+		}
+	}
+
 	if len(i.Options) != len(other.Options) {
 		res.AddPropertyDiff("Options")
 	} else {
@@ -148,10 +340,10 @@ func (i *DashboardInput) SetDashboard(dashboard *Dashboard) {
 
 // ValidateQuery implements QueryProvider
 func (i *DashboardInput) ValidateQuery() hcl.Diagnostics {
-	// inputs with placeholder or options, or text type do not need a query
+	// inputs with placeholder or options, or which accept free text do not need a query
 	if i.Placeholder != nil ||
 		len(i.Options) > 0 ||
-		typehelpers.SafeString(i.Type) == "text" {
+		i.AllowsFreeText() {
 		return nil
 	}
 
@@ -173,6 +365,13 @@ func (i *DashboardInput) CtyValue() (cty.Value, error) {
 	return GetCtyValue(i)
 }
 
+func (i *DashboardInput) defaultDependencyEquals(other *DashboardInput) bool {
+	if i.DefaultDependency == nil || other.DefaultDependency == nil {
+		return i.DefaultDependency == nil && other.DefaultDependency == nil
+	}
+	return i.DefaultDependency.Equals(other.DefaultDependency)
+}
+
 func (i *DashboardInput) setBaseProperties() {
 	if i.Base == nil {
 		return
@@ -198,6 +397,19 @@ func (i *DashboardInput) setBaseProperties() {
 		i.Placeholder = i.Base.Placeholder
 	}
 
+	if i.Help == nil {
+		i.Help = i.Base.Help
+	}
+
+	if i.UrlParam == nil {
+		i.UrlParam = i.Base.UrlParam
+	}
+
+	if i.Default == nil && i.DefaultDependency == nil {
+		i.Default = i.Base.Default
+		i.DefaultDependency = i.Base.DefaultDependency
+	}
+
 	if i.Width == nil {
 		i.Width = i.Base.Width
 	}
@@ -205,4 +417,12 @@ func (i *DashboardInput) setBaseProperties() {
 	if i.Options == nil {
 		i.Options = i.Base.Options
 	}
+
+	if i.Required == nil {
+		i.Required = i.Base.Required
+	}
+
+	if i.Validations == nil {
+		i.Validations = i.Base.Validations
+	}
 }
@@ -0,0 +1,69 @@
+package modconfig
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/tryfunc"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/turbot/pipe-fittings/hclhelpers"
+	"github.com/turbot/terraform-components/lang/funcs"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+// the functions which may be used in the condition of an input validation
+// (a subset of the functions available when parsing the mod)
+var inputValidationFunctions = map[string]function.Function{
+	"can":       tryfunc.CanFunc,
+	"contains":  stdlib.ContainsFunc,
+	"length":    funcs.LengthFunc,
+	"lower":     stdlib.LowerFunc,
+	"regex":     stdlib.RegexFunc,
+	"regexall":  stdlib.RegexAllFunc,
+	"tonumber":  stdlib.MakeToFunc(cty.Number),
+	"trimspace": stdlib.TrimSpaceFunc,
+	"upper":     stdlib.UpperFunc,
+}
+
+// DashboardInputValidation is a validation rule for the values of a dashboard input, given as a "validation" block
+// The condition may reference the value being validated as 'self.value' and must return true if the value is valid
+type DashboardInputValidation struct {
+	Condition    hcl.Expression `hcl:"condition" json:"-"`
+	ErrorMessage string         `hcl:"error_message" json:"error_message"`
+}
+
+func (v *DashboardInputValidation) Equals(other *DashboardInputValidation) bool {
+	if other == nil {
+		return false
+	}
+	return v.ErrorMessage == other.ErrorMessage && hclhelpers.ExpressionsEqual(v.Condition, other.Condition)
+}
+
+// Validate evaluates the condition for the given value, returning an error diagnostic containing the
+// error message if the condition is false
+func (v *DashboardInputValidation) Validate(input *DashboardInput, value cty.Value) hcl.Diagnostics {
+	evalCtx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{"self": cty.ObjectVal(map[string]cty.Value{"value": value})},
+		Functions: inputValidationFunctions,
+	}
+	var valid bool
+	if diags := gohcl.DecodeExpression(v.Condition, evalCtx, &valid); diags.HasErrors() {
+		return hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("failed to evaluate validation condition for input '%s'", input.UnqualifiedName),
+			Detail:   diags.Error(),
+			Subject:  v.Condition.Range().Ptr(),
+		}}
+	}
+	if valid {
+		return nil
+	}
+	return hcl.Diagnostics{&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf("invalid value for input '%s'", input.UnqualifiedName),
+		Detail:   v.ErrorMessage,
+		Subject:  v.Condition.Range().Ptr(),
+	}}
+}
@@ -0,0 +1,22 @@
+package modconfig
+
+import (
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// DashboardLabels is a set of locale specific overrides for the title and description of a dashboard
+type DashboardLabels struct {
+	Locale      string  `hcl:"locale,label" json:"locale"`
+	Title       *string `cty:"title" hcl:"title" json:"title,omitempty"`
+	Description *string `cty:"description" hcl:"description" json:"description,omitempty"`
+}
+
+func (l *DashboardLabels) Equals(other *DashboardLabels) bool {
+	if other == nil {
+		return false
+	}
+
+	return l.Locale == other.Locale &&
+		utils.SafeStringsEqual(l.Title, other.Title) &&
+		utils.SafeStringsEqual(l.Description, other.Description)
+}
@@ -102,7 +102,8 @@ func (t *DashboardTable) OnDecoded(block *hcl.Block, resourceMapProvider Resourc
 			t.Columns[c.Name] = c
 		}
 	}
-	return t.QueryProviderImpl.OnDecoded(block, resourceMapProvider)
+	diags := validateWidth(t, t.Width)
+	return append(diags, t.QueryProviderImpl.OnDecoded(block, resourceMapProvider)...)
 }
 
 func (t *DashboardTable) Diff(other *DashboardTable) *DashboardTreeItemDiffs {
@@ -0,0 +1,285 @@
+package modconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/stevenle/topsort"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+type runtimeDependencyGraphTest struct {
+	edges [][2]string
+	// the names of the resources expected to appear in the cycle error
+	// (the cycle may start at any of these as the graph edges are unordered)
+	expectedCycle []string
+}
+
+var testCasesRuntimeDependencyGraph = map[string]runtimeDependencyGraphTest{
+	"no cycle": {
+		edges: [][2]string{
+			{"dashboard.d1.chart.c1", "self.input.i1"},
+			{"dashboard.d1.chart.c2", "self.input.i1"},
+		},
+	},
+	"cycle": {
+		edges: [][2]string{
+			{"self.input.i1", "self.input.i2"},
+			{"self.input.i2", "self.input.i1"},
+		},
+		expectedCycle: []string{"self.input.i1", "self.input.i2"},
+	},
+}
+
+func TestValidateRuntimeDependencyGraph(t *testing.T) {
+	for name, test := range testCasesRuntimeDependencyGraph {
+		graph := topsort.NewGraph()
+		graph.AddNode(rootRuntimeDependencyNode)
+		for _, edge := range test.edges {
+			for _, node := range edge {
+				if !graph.ContainsNode(node) {
+					graph.AddNode(node)
+				}
+			}
+			if err := graph.AddEdge(rootRuntimeDependencyNode, edge[0]); err != nil {
+				t.Fatal(err)
+			}
+			if err := graph.AddEdge(edge[0], edge[1]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		d := &Dashboard{runtimeDependencyGraph: graph}
+
+		err := d.validateRuntimeDependencyGraph()
+		if len(test.expectedCycle) == 0 {
+			if err != nil {
+				t.Errorf("Test %s FAILED with unexpected error: %v", name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), "runtime dependencies cannot be resolved - dependency cycle: ") {
+			t.Errorf("Test %s FAILED. Expected dependency cycle error, got %v", name, err)
+			continue
+		}
+		for _, expectedName := range test.expectedCycle {
+			if !strings.Contains(err.Error(), expectedName) {
+				t.Errorf("Test %s FAILED. Expected error to contain '%s', got %v", name, expectedName, err)
+			}
+		}
+	}
+}
+
+func TestDashboardClone(t *testing.T) {
+	mod := NewMod("test", "", hcl.Range{})
+	d := NewDashboard(&hcl.Block{Type: BlockTypeDashboard, Labels: []string{"d1"}}, mod, "d1").(*Dashboard)
+	d.Width = utils.ToIntegerPointer(6)
+	d.Tags = map[string]string{"service": "s3"}
+	d.AddChild(NewDashboardInput(&hcl.Block{Type: BlockTypeInput, Labels: []string{"i1"}}, mod, "i1").(*DashboardInput))
+	container := NewDashboardContainer(&hcl.Block{Type: BlockTypeContainer}, mod, "container_1").(*DashboardContainer)
+	containerInput := NewDashboardInput(&hcl.Block{Type: BlockTypeInput, Labels: []string{"i2"}}, mod, "i2").(*DashboardInput)
+	container.AddChild(containerInput)
+	container.Inputs = append(container.Inputs, containerInput)
+	card := NewDashboardCard(&hcl.Block{Type: BlockTypeCard, Labels: []string{"card1"}}, mod, "card1").(*DashboardCard)
+	card.Tags = map[string]string{"service": "s3"}
+	container.AddChild(card)
+	_ = card.AddParent(container)
+	d.AddChild(container)
+	_ = container.AddParent(d)
+	if diags := d.InitInputs(); diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	if err := d.ValidateRuntimeDependencies(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	clone := d.Clone()
+
+	// modify the clone
+	*clone.Width = 12
+	clone.Tags["service"] = "ec2"
+	for _, input := range clone.Inputs {
+		input.Label = utils.ToStringPointer("changed")
+	}
+	clone.AddChild(NewDashboardCard(&hcl.Block{Type: BlockTypeCard, Labels: []string{"card2"}}, mod, "card2").(*DashboardCard))
+	clone.children[1].(*DashboardContainer).children[1].(*DashboardCard).Tags["service"] = "ec2"
+
+	// the original must be unchanged
+	if *d.Width != 6 {
+		t.Errorf("Test TestDashboardClone FAILED. Expected original width 6, got %d", *d.Width)
+	}
+	if d.Tags["service"] != "s3" {
+		t.Errorf("Test TestDashboardClone FAILED. Expected original tag 's3', got '%s'", d.Tags["service"])
+	}
+	for _, input := range d.Inputs {
+		if input.Label != nil {
+			t.Errorf("Test TestDashboardClone FAILED. Expected original input %s to have no label, got '%s'", input.Name(), *input.Label)
+		}
+		if input.dashboard != d {
+			t.Errorf("Test TestDashboardClone FAILED. Expected original input %s to belong to the original dashboard", input.Name())
+		}
+	}
+	if len(d.children) != 2 {
+		t.Errorf("Test TestDashboardClone FAILED. Expected original to have 2 children, got %d", len(d.children))
+	}
+	if card.Tags["service"] != "s3" {
+		t.Errorf("Test TestDashboardClone FAILED. Expected original card tag 's3', got '%s'", card.Tags["service"])
+	}
+	if card.GetParents()[0] != container || container.GetParents()[0] != d {
+		t.Errorf("Test TestDashboardClone FAILED. Expected original panels to keep their original parents")
+	}
+
+	// the clone inputs belong to the clone, and the container of the clone references the cloned input
+	if len(clone.Inputs) != 2 {
+		t.Fatalf("Test TestDashboardClone FAILED. Expected clone to have 2 inputs, got %d", len(clone.Inputs))
+	}
+	for _, input := range clone.Inputs {
+		if input.dashboard != clone {
+			t.Errorf("Test TestDashboardClone FAILED. Expected cloned input %s to belong to the clone", input.Name())
+		}
+		if clone.selfInputsMap[input.UnqualifiedName] != input {
+			t.Errorf("Test TestDashboardClone FAILED. Expected input map of the clone to contain the cloned input %s", input.Name())
+		}
+	}
+	clonedContainer := clone.children[1].(*DashboardContainer)
+	if clonedContainer == container || clonedContainer.Inputs[0] == containerInput || clonedContainer.children[0] == containerInput {
+		t.Errorf("Test TestDashboardClone FAILED. Expected the container and its input to be cloned")
+	}
+	if clonedContainer.GetParents()[0] != clone {
+		t.Errorf("Test TestDashboardClone FAILED. Expected the parent of the cloned container to be the clone")
+	}
+	clonedCard := clonedContainer.children[1].(*DashboardCard)
+	if clonedCard == card {
+		t.Errorf("Test TestDashboardClone FAILED. Expected the card to be cloned")
+	}
+	if clonedCard.GetParents()[0] != clonedContainer {
+		t.Errorf("Test TestDashboardClone FAILED. Expected the parent of the cloned card to be the cloned container")
+	}
+	if clone.runtimeDependencyGraph == nil || clone.runtimeDependencyGraph == d.runtimeDependencyGraph {
+		t.Errorf("Test TestDashboardClone FAILED. Expected the runtime dependency graph to be rebuilt")
+	}
+}
+
+type dashboardChildDiffTest struct {
+	oldChildren []string
+	newChildren []string
+	expected    []string
+}
+
+var testCasesDashboardChildDiff = map[string]dashboardChildDiffTest{
+	"unchanged": {
+		oldChildren: []string{"chart.c1", "card.c2"},
+		newChildren: []string{"chart.c1", "card.c2"},
+	},
+	"child added": {
+		oldChildren: []string{"chart.c1"},
+		newChildren: []string{"chart.c1", "card.c2"},
+		expected:    []string{"ChildAdded:card.c2"},
+	},
+	"child removed": {
+		oldChildren: []string{"chart.c1", "card.c2"},
+		newChildren: []string{"card.c2"},
+		expected:    []string{"ChildRemoved:chart.c1"},
+	},
+	"children reordered": {
+		oldChildren: []string{"chart.c1", "card.c2"},
+		newChildren: []string{"card.c2", "chart.c1"},
+		expected:    []string{"ChildrenReordered"},
+	},
+	"child added at start is not a reorder": {
+		oldChildren: []string{"chart.c1", "card.c2"},
+		newChildren: []string{"table.t1", "chart.c1", "card.c2"},
+		expected:    []string{"ChildAdded:table.t1"},
+	},
+	"child replaced and reordered": {
+		oldChildren: []string{"chart.c1", "card.c2", "table.t1"},
+		newChildren: []string{"table.t1", "chart.c1", "table.t2"},
+		expected:    []string{"ChildRemoved:card.c2", "ChildAdded:table.t2", "ChildrenReordered"},
+	},
+}
+
+func TestDashboardChildDiff(t *testing.T) {
+	mod := NewMod("test", "", hcl.Range{})
+	for name, test := range testCasesDashboardChildDiff {
+		oldDashboard := NewDashboard(&hcl.Block{Type: BlockTypeDashboard, Labels: []string{"d1"}}, mod, "d1").(*Dashboard)
+		oldDashboard.ChildNames = test.oldChildren
+		newDashboard := NewDashboard(&hcl.Block{Type: BlockTypeDashboard, Labels: []string{"d1"}}, mod, "d1").(*Dashboard)
+		newDashboard.ChildNames = test.newChildren
+
+		diff := oldDashboard.Diff(newDashboard)
+		if !reflect.DeepEqual(diff.ChangedProperties, test.expected) {
+			t.Errorf("Test %s FAILED. Expected %v, got %v", name, test.expected, diff.ChangedProperties)
+		}
+	}
+}
+
+type walkResourcesTest struct {
+	opts WalkOptions
+	// the name of the resource after which walking stops
+	stopAt   string
+	expected []string
+}
+
+var testCasesWalkResourcesWithOptions = map[string]walkResourcesTest{
+	"all resources": {
+		expected: []string{"test.chart.chart1", "test.container.container_1", "test.card.card1", "test.graph.graph1", "test.node.node1", "test.edge.edge1", "test.card.card2"},
+	},
+	"max depth 1": {
+		opts:     WalkOptions{MaxDepth: 1},
+		expected: []string{"test.chart.chart1", "test.container.container_1", "test.card.card2"},
+	},
+	"max depth 2": {
+		opts:     WalkOptions{MaxDepth: 2},
+		expected: []string{"test.chart.chart1", "test.container.container_1", "test.card.card1", "test.graph.graph1", "test.card.card2"},
+	},
+	"block type filter": {
+		opts:     WalkOptions{BlockTypes: []string{BlockTypeCard, BlockTypeNode}},
+		expected: []string{"test.card.card1", "test.node.node1", "test.card.card2"},
+	},
+	"block type filter and max depth": {
+		opts:     WalkOptions{MaxDepth: 2, BlockTypes: []string{BlockTypeCard, BlockTypeNode}},
+		expected: []string{"test.card.card1", "test.card.card2"},
+	},
+	"stop walking": {
+		stopAt: "test.card.card1",
+		// the remaining siblings of card1 are not walked
+		expected: []string{"test.chart.chart1", "test.container.container_1", "test.card.card1", "test.card.card2"},
+	},
+}
+
+func TestWalkResourcesWithOptions(t *testing.T) {
+	// chart1
+	// container_1
+	//   card1
+	//   graph1
+	//     node1
+	//     edge1
+	// card2
+	mod := NewMod("test", "", hcl.Range{})
+	d := NewDashboard(&hcl.Block{Type: BlockTypeDashboard, Labels: []string{"d1"}}, mod, "d1").(*Dashboard)
+	d.AddChild(NewDashboardChart(&hcl.Block{Type: BlockTypeChart, Labels: []string{"chart1"}}, mod, "chart1").(*DashboardChart))
+	container := NewDashboardContainer(&hcl.Block{Type: BlockTypeContainer}, mod, "container_1").(*DashboardContainer)
+	container.AddChild(NewDashboardCard(&hcl.Block{Type: BlockTypeCard, Labels: []string{"card1"}}, mod, "card1").(*DashboardCard))
+	graph := NewDashboardGraph(&hcl.Block{Type: BlockTypeGraph, Labels: []string{"graph1"}}, mod, "graph1").(*DashboardGraph)
+	graph.AddChild(NewDashboardNode(&hcl.Block{Type: BlockTypeNode, Labels: []string{"node1"}}, mod, "node1"))
+	graph.AddChild(NewDashboardEdge(&hcl.Block{Type: BlockTypeEdge, Labels: []string{"edge1"}}, mod, "edge1"))
+	container.AddChild(graph)
+	d.AddChild(container)
+	d.AddChild(NewDashboardCard(&hcl.Block{Type: BlockTypeCard, Labels: []string{"card2"}}, mod, "card2").(*DashboardCard))
+
+	for name, test := range testCasesWalkResourcesWithOptions {
+		var walked []string
+		err := d.WalkResourcesWithOptions(test.opts, func(resource HclResource) (bool, error) {
+			walked = append(walked, resource.Name())
+			return resource.Name() != test.stopAt, nil
+		})
+		if err != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(walked, test.expected) {
+			t.Errorf("Test %s FAILED. Expected %v, got %v", name, test.expected, walked)
+		}
+	}
+}
@@ -54,7 +54,7 @@ func (t *DashboardText) Equals(other *DashboardText) bool {
 // OnDecoded implements HclResource
 func (t *DashboardText) OnDecoded(*hcl.Block, ResourceMapsProvider) hcl.Diagnostics {
 	t.setBaseProperties()
-	return nil
+	return validateWidth(t, t.Width)
 }
 
 func (t *DashboardText) Diff(other *DashboardText) *DashboardTreeItemDiffs {
@@ -1,6 +1,8 @@
 package modconfig
 
 import (
+	"slices"
+
 	"github.com/turbot/go-kit/helpers"
 	"github.com/turbot/steampipe/pkg/utils"
 	"golang.org/x/exp/maps"
@@ -78,6 +80,30 @@ func (d *DashboardTreeItemDiffs) populateChildDiffs(old ModTreeItem, new ModTree
 	}
 }
 
+// populateChildNameDiffs compares the child names of the old and new items, adding a property diff for each added child
+// ("ChildAdded:<name>") and removed child ("ChildRemoved:<name>"), and a "ChildrenReordered" diff if the children
+// which exist in both have a different order
+func (d *DashboardTreeItemDiffs) populateChildNameDiffs(oldNames, newNames []string) {
+	var oldCommon, newCommon []string
+	for _, name := range oldNames {
+		if helpers.StringSliceContains(newNames, name) {
+			oldCommon = append(oldCommon, name)
+		} else {
+			d.AddPropertyDiff("ChildRemoved:" + name)
+		}
+	}
+	for _, name := range newNames {
+		if helpers.StringSliceContains(oldNames, name) {
+			newCommon = append(newCommon, name)
+		} else {
+			d.AddPropertyDiff("ChildAdded:" + name)
+		}
+	}
+	if !slices.Equal(oldCommon, newCommon) {
+		d.AddPropertyDiff("ChildrenReordered")
+	}
+}
+
 func (d *DashboardTreeItemDiffs) HasChanges() bool {
 	return len(d.ChangedProperties)+
 		len(d.AddedItems)+
@@ -0,0 +1,54 @@
+package modconfig
+
+import "slices"
+
+// WalkOptions determines how the resources of a dashboard or container are traversed by WalkResourcesWithOptions
+type WalkOptions struct {
+	// the maximum depth to walk - the direct children of the dashboard or container are at depth 1
+	// zero means no limit
+	MaxDepth int
+	// if set, only resources with one of these block types are passed to the resource func
+	// NOTE: resources of other types are still traversed, so their descendants may be passed to the resource func
+	BlockTypes []string
+}
+
+func (o WalkOptions) includes(resource HclResource) bool {
+	return len(o.BlockTypes) == 0 || slices.Contains(o.BlockTypes, resource.BlockType())
+}
+
+// WalkResourcesWithOptions walks the resources of the dashboard, limited by the depth and block types of the options
+// unlike WalkResources, this recurses into the children of all resources, i.e. the contents of nested dashboards
+// and the nodes and edges of graphs, flows and hierarchies are also walked
+// as with WalkResources, if the resource func returns false, the remaining siblings of the resource are not walked
+func (d *Dashboard) WalkResourcesWithOptions(opts WalkOptions, resourceFunc func(resource HclResource) (bool, error)) error {
+	return walkChildResources(d.children, opts, 1, resourceFunc)
+}
+
+// WalkResourcesWithOptions walks the resources of the container, limited by the depth and block types of the options
+// (see Dashboard.WalkResourcesWithOptions)
+func (c *DashboardContainer) WalkResourcesWithOptions(opts WalkOptions, resourceFunc func(resource HclResource) (bool, error)) error {
+	return walkChildResources(c.children, opts, 1, resourceFunc)
+}
+
+func walkChildResources(children []ModTreeItem, opts WalkOptions, depth int, resourceFunc func(resource HclResource) (bool, error)) error {
+	if opts.MaxDepth > 0 && depth > opts.MaxDepth {
+		return nil
+	}
+	for _, child := range children {
+		resource := child.(HclResource)
+		if opts.includes(resource) {
+			continueWalking, err := resourceFunc(resource)
+			if err != nil {
+				return err
+			}
+			if !continueWalking {
+				break
+			}
+		}
+
+		if err := walkChildResources(child.GetChildren(), opts, depth+1, resourceFunc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -19,6 +19,9 @@ type HclResourceImpl struct {
 	Documentation   *string           `cty:"documentation" hcl:"documentation" column:"documentation,text" json:"-"`
 	DeclRange       hcl.Range         `json:"-"`
 	Tags            map[string]string `cty:"tags" hcl:"tags,optional" column:"tags,jsonb" json:"-"`
+	// may be set to true, or to a message describing the deprecation
+	// (a bool value is converted to a string when decoding)
+	Deprecated *string `cty:"deprecated" hcl:"deprecated" column:"deprecated,text" json:"deprecated,omitempty"`
 
 	base                HclResource
 	blockType           string
@@ -75,6 +78,19 @@ func (b *HclResourceImpl) GetTags() map[string]string {
 	return map[string]string{}
 }
 
+// IsDeprecated returns whether the resource has been marked as deprecated
+func (b *HclResourceImpl) IsDeprecated() bool {
+	return b.Deprecated != nil && *b.Deprecated != "false"
+}
+
+// GetDeprecationMessage returns the deprecation message, if one was provided
+func (b *HclResourceImpl) GetDeprecationMessage() string {
+	if !b.IsDeprecated() || *b.Deprecated == "true" {
+		return ""
+	}
+	return *b.Deprecated
+}
+
 // GetHclResourceImpl implements HclResource
 func (b *HclResourceImpl) GetHclResourceImpl() *HclResourceImpl {
 	return b
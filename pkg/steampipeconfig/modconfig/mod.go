@@ -31,6 +31,8 @@ type Mod struct {
 	Categories []string `cty:"categories" hcl:"categories,optional" column:"categories,jsonb"`
 	Color      *string  `cty:"color" hcl:"color" column:"color,text"`
 	Icon       *string  `cty:"icon" hcl:"icon" column:"icon,text"`
+	// sql (typically common table expressions) prepended to the sql of every control in the mod when it is executed
+	SqlPrelude *string `cty:"sql_prelude" hcl:"sql_prelude" column:"sql_prelude,text"`
 
 	// blocks
 	Require       *Require   `hcl:"require,block"`
@@ -88,6 +90,7 @@ func (m *Mod) Equals(other *Mod) bool {
 		typehelpers.SafeString(m.Description) == typehelpers.SafeString(other.Description) &&
 		typehelpers.SafeString(m.Documentation) == typehelpers.SafeString(other.Documentation) &&
 		typehelpers.SafeString(m.Icon) == typehelpers.SafeString(other.Icon) &&
+		typehelpers.SafeString(m.SqlPrelude) == typehelpers.SafeString(other.SqlPrelude) &&
 		typehelpers.SafeString(m.Title) == typehelpers.SafeString(other.Title)
 	if !res {
 		return res
@@ -235,6 +238,9 @@ func (m *Mod) Save() error {
 	if m.Icon != nil {
 		modBody.SetAttributeValue("icon", cty.StringVal(*m.Icon))
 	}
+	if m.SqlPrelude != nil {
+		modBody.SetAttributeValue("sql_prelude", cty.StringVal(*m.SqlPrelude))
+	}
 	if len(m.Categories) > 0 {
 		categoryValues := make([]cty.Value, len(m.Categories))
 		for i, c := range m.Categories {
@@ -2,6 +2,7 @@ package modconfig
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/turbot/steampipe/pkg/utils"
@@ -44,6 +45,8 @@ func (m *Mod) BuildResourceTree(loadedDependencyMods ModMap) (err error) {
 func (m *Mod) addResourcesIntoTree(sourceMod *Mod) error {
 	var leafNodes []ModTreeItem
 	var err error
+	// the index of the first child added from the source mod
+	firstChild := len(m.children)
 
 	resourceFunc := func(item HclResource) (bool, error) {
 		if treeItem, ok := item.(ModTreeItem); ok {
@@ -70,6 +73,10 @@ func (m *Mod) addResourcesIntoTree(sourceMod *Mod) error {
 	// iterate through all resources in source mod
 	sourceMod.WalkResources(resourceFunc)
 
+	// the resources are walked in map order, so sort the children which were added into declaration order
+	// this ensures the children (and so, for example, the results of 'check all') are in a stable order
+	sortByDeclaration(m.children[firstChild:])
+
 	// now initialise all Paths properties
 	for _, l := range leafNodes {
 		l.SetPaths()
@@ -92,6 +99,20 @@ func (m *Mod) addItemIntoResourceTree(item ModTreeItem) error {
 	return nil
 }
 
+// sortByDeclaration sorts the items by the file and position they are declared at, then by name
+func sortByDeclaration(items []ModTreeItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].GetDeclRange(), items[j].GetDeclRange()
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Start.Byte != b.Start.Byte {
+			return a.Start.Byte < b.Start.Byte
+		}
+		return items[i].Name() < items[j].Name()
+	})
+}
+
 // check whether a resource with the same name has already been added to the mod
 // (it is possible to add the same resource to a mod more than once as the parent resource
 // may have dependency errors and so be decoded again)
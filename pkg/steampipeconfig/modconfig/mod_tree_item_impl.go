@@ -1,8 +1,13 @@
 package modconfig
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/pipe-fittings/hclhelpers"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 )
 
 type ModTreeItemImpl struct {
@@ -12,6 +17,11 @@ type ModTreeItemImpl struct {
 
 	Mod   *Mod       `cty:"mod" json:"-"`
 	Paths []NodePath `column:"path,jsonb" json:"-"`
+	// the condition which determines whether this item is included in its parent dashboard or container
+	// NOTE: this is stored unevaluated as it may depend on inputs
+	If hcl.Expression `hcl:"if,optional" json:"-"`
+	// the parse eval context, used to evaluate a condition which depends on inputs so it may also reference variables and locals
+	conditionEvalCtx *hcl.EvalContext
 
 	parents  []ModTreeItem
 	children []ModTreeItem
@@ -64,3 +74,61 @@ func (b *ModTreeItemImpl) CtyValue() (cty.Value, error) {
 	}
 	return GetCtyValue(b)
 }
+
+// ConditionInputs returns the names of the inputs referenced by the 'if' condition
+// (these are runtime dependencies, so the condition can only be evaluated when the dashboard is executed)
+func (b *ModTreeItemImpl) ConditionInputs() []string {
+	if b.If == nil {
+		return nil
+	}
+	var res []string
+	for _, traversal := range b.If.Variables() {
+		path := strings.TrimPrefix(hclhelpers.TraversalAsString(traversal), "self.")
+		propertyPath, err := ParseResourcePropertyPath(path)
+		if err != nil || propertyPath.ItemType != BlockTypeInput {
+			continue
+		}
+		res = append(res, propertyPath.ToResourceName())
+	}
+	return res
+}
+
+// SetConditionEvalContext sets the parse eval context used to evaluate a condition which depends on inputs
+func (b *ModTreeItemImpl) SetConditionEvalContext(evalCtx *hcl.EvalContext) {
+	b.conditionEvalCtx = evalCtx
+}
+
+// ConditionEvalContext returns a child of the parse eval context, to which the input values may be added
+// to evaluate a condition which depends on inputs
+func (b *ModTreeItemImpl) ConditionEvalContext() *hcl.EvalContext {
+	if b.conditionEvalCtx == nil {
+		return &hcl.EvalContext{}
+	}
+	return b.conditionEvalCtx.NewChild()
+}
+
+// EvaluateCondition evaluates the 'if' condition using the given eval context,
+// returning whether the item should be included in its parent
+// If there is no condition (or it evaluates to null), the item is included
+func (b *ModTreeItemImpl) EvaluateCondition(evalCtx *hcl.EvalContext) (bool, hcl.Diagnostics) {
+	if b.If == nil {
+		return true, nil
+	}
+	val, diags := b.If.Value(evalCtx)
+	if diags.HasErrors() {
+		return false, diags
+	}
+	if val.IsNull() {
+		return true, nil
+	}
+	val, err := convert.Convert(val, cty.Bool)
+	if err != nil || !val.IsKnown() {
+		return false, hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("invalid 'if' condition for %s", b.Name()),
+			Detail:   "the condition must evaluate to a boolean",
+			Subject:  b.If.Range().Ptr(),
+		}}
+	}
+	return val.True(), nil
+}
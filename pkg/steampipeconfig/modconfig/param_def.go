@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	typehelpers "github.com/turbot/go-kit/types"
 	"github.com/turbot/pipe-fittings/hclhelpers"
+	"github.com/zclconf/go-cty/cty"
 )
 
 type ParamDef struct {
@@ -14,6 +15,9 @@ type ParamDef struct {
 	UnqualifiedName string  `cty:"full_name" json:"-"`
 	Description     *string `cty:"description" json:"description"`
 	Default         *string `cty:"default" json:"default"`
+	// optional type constraint - if set, the default must conform to this type
+	Type       cty.Type `json:"-"`
+	TypeString string   `cty:"type" json:"type,omitempty"`
 	// tactical - is the raw value a string
 	IsString bool `cty:"is_string" json:"-"`
 
@@ -37,7 +41,8 @@ func (p *ParamDef) String() string {
 func (p *ParamDef) Equals(other *ParamDef) bool {
 	return p.ShortName == other.ShortName &&
 		typehelpers.SafeString(p.Description) == typehelpers.SafeString(other.Description) &&
-		typehelpers.SafeString(p.Default) == typehelpers.SafeString(other.Default)
+		typehelpers.SafeString(p.Default) == typehelpers.SafeString(other.Default) &&
+		p.TypeString == other.TypeString
 }
 
 // SetDefault sets the default as a atring points, marshalling to json is the underlying value is NOT a string
@@ -37,6 +37,30 @@ func (p *PluginVersion) String() string {
 	return fmt.Sprintf("plugin %s", p.FullName())
 }
 
+// SatisfiedBy returns whether any of the given installed plugins satisfies the requirement
+// plugins are keyed by image ref - nil versions are ignored and locally built plugins always satisfy the requirement
+func (p *PluginVersion) SatisfiedBy(plugins map[string]*PluginVersionString) bool {
+	for installedName, installed := range plugins {
+		if installed == nil {
+			continue
+		}
+		org, name, _ := ociinstaller.NewSteampipeImageRef(installedName).GetOrgNameAndConstraint()
+		if org != p.Org || name != p.Name {
+			// no point checking - different plugin
+			continue
+		}
+		// if org and name matches but the plugin is built locally, the requirement is satisfied
+		if installed.IsLocal() {
+			return true
+		}
+		// if org and name matches, check whether the version constraint is satisfied
+		if p.Constraint.Check(installed.Semver()) {
+			return true
+		}
+	}
+	return false
+}
+
 // Initialise parses the version and name properties
 func (p *PluginVersion) Initialise(block *hcl.Block) hcl.Diagnostics {
 	var diags hcl.Diagnostics
@@ -135,9 +135,15 @@ func (q *QueryArgs) Merge(other *QueryArgs, source QueryProvider) (*QueryArgs, e
 	}
 
 	// create a new query args to store the merged result
+	// NOTE: copy the string arg maps rather than sharing them - the result must not share state with either source
+	// (e.g. if multiple panels reference the same query, each must resolve its args independently)
 	result := NewQueryArgs()
-	result.stringNamedArgs = other.stringNamedArgs
-	result.stringPositionalArgs = other.stringPositionalArgs
+	for k := range other.stringNamedArgs {
+		result.stringNamedArgs[k] = struct{}{}
+	}
+	for i := range other.stringPositionalArgs {
+		result.stringPositionalArgs[i] = struct{}{}
+	}
 
 	// named args
 	// first set values from other
@@ -349,3 +349,28 @@ func TestResolveAsString(t *testing.T) {
 		}
 	}
 }
+
+func TestMergeDoesNotMutateArgs(t *testing.T) {
+	base := NewQueryArgs()
+	if err := base.SetNamedArgVal("base val", "p1"); err != nil {
+		t.Fatal(err)
+	}
+	other := NewQueryArgs()
+	if err := other.SetNamedArgVal("other val", "p2"); err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := base.Merge(other, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := merged.stringNamedArgs["p1"]; !ok {
+		t.Errorf("expected merged args to have string arg p1")
+	}
+	if _, ok := other.stringNamedArgs["p1"]; ok {
+		t.Errorf("expected merge not to add string arg p1 to the other args")
+	}
+	if len(base.ArgMap) != 1 || len(other.ArgMap) != 1 {
+		t.Errorf("expected merge not to modify the source arg maps, got %v and %v", base.ArgMap, other.ArgMap)
+	}
+}
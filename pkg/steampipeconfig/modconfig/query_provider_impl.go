@@ -2,6 +2,7 @@ package modconfig
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/turbot/go-kit/helpers"
@@ -72,7 +73,9 @@ func (q *QueryProviderImpl) ValidateQuery() hcl.Diagnostics {
 		return nil
 	}
 
-	if queryRequired && q.Query == nil && q.SQL == nil {
+	// treat empty sql as not set
+	sqlSet := q.SQL != nil && strings.TrimSpace(*q.SQL) != ""
+	if queryRequired && q.Query == nil && !sqlSet {
 		diags = append(diags, &hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  fmt.Sprintf("%s does not define a query or SQL", q.Name()),
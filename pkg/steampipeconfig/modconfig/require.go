@@ -8,7 +8,6 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/turbot/pipe-fittings/hclhelpers"
 	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
-	"github.com/turbot/steampipe/pkg/ociinstaller"
 	"github.com/turbot/steampipe/pkg/version"
 )
 
@@ -151,21 +150,8 @@ func (r *Require) validatePluginVersions(modName string, plugins map[string]*Plu
 // searchInstalledPluginForRequirement returns plugin validation errors if no plugin is found which satisfies
 // the mod requirement. If plugin is found nil error is returned.
 func (r *Require) searchInstalledPluginForRequirement(modName string, requirement *PluginVersion, plugins map[string]*PluginVersionString) error {
-	for installedName, installed := range plugins {
-		org, name, _ := ociinstaller.NewSteampipeImageRef(installedName).GetOrgNameAndConstraint()
-		if org != requirement.Org || name != requirement.Name {
-			// no point checking - different plugin
-			continue
-		}
-		// if org and name matches but the plugin is built locally, return without any validation error
-		if installed.IsLocal() {
-			return nil
-		}
-		// if org and name matches, check whether the version constraint is satisfied
-		if requirement.Constraint.Check(installed.Semver()) {
-			// constraint is satisfied
-			return nil
-		}
+	if requirement.SatisfiedBy(plugins) {
+		return nil
 	}
 	// validation failed - return error
 	return sperr.New("could not find plugin which satisfies requirement '%s@%s' - required by '%s'", requirement.RawName, requirement.MinVersionString, modName)
@@ -32,32 +32,48 @@ func (d *RuntimeDependency) String() string {
 	return fmt.Sprintf("%s.%s->%s", d.ParentPropertyName, *d.TargetPropertyName, d.PropertyPath.String())
 }
 
-func (d *RuntimeDependency) ValidateSource(dashboard *Dashboard, workspace ResourceMapsProvider) error {
-	// TODO  [node_reuse] re-add parse time validation https://github.com/turbot/steampipe/issues/2925
-	//resourceName := d.PropertyPath.ToResourceName()
-	//var found bool
-	////var sourceResource HclResource
-	//switch d.PropertyPath.ItemType {
-	//// if this is a 'with' resolve from the parent resource
-	//case BlockTypeParam:
-	//	_, found = d.ParentResource.ResolveWithFromTree(resourceName)
-	//case BlockTypeWith:
-	//	_, found = d.ParentResource.ResolveWithFromTree(resourceName)
-	//// if this dependency has a 'self' prefix, resolve from the current dashboard container
-	//case BlockTypeInput:
-	//	_, found = dashboard.GetInput(resourceName)
-	//
-	//	//default:
-	//	//	// otherwise, resolve from the global inputs
-	//	//	_, found = workspace.GetResourceMaps().GlobalDashboardInputs[resourceName]
-	//}
-	//if !found {
-	//	return fmt.Errorf("could not resolve runtime dependency resource %s", d.PropertyPath)
-	//}
-
+// ValidateSource ensures the source resource of the dependency can be resolved
+// inputs are resolved from the dashboard, 'with' blocks from the given owners, then the dashboard,
+// and params from the given owners
+// (the owners are the resource which has the dependency (or whose 'with', node or edge has it) and its base)
+func (d *RuntimeDependency) ValidateSource(dashboard *Dashboard, owners ...HclResource) error {
+	if _, found := d.resolveSource(dashboard, owners...); !found {
+		return fmt.Errorf("could not resolve runtime dependency resource %s", d.PropertyPath)
+	}
 	return nil
 }
 
+// resolveSource returns the resource which provides the value of the dependency
+// for a param dependency, this is the owner which declares the param, as the param value is provided by its args
+func (d *RuntimeDependency) resolveSource(dashboard *Dashboard, owners ...HclResource) (HclResource, bool) {
+	resourceName := d.SourceResourceName()
+	switch d.PropertyPath.ItemType {
+	case BlockTypeInput:
+		input, found := dashboard.GetInput(resourceName)
+		return input, found
+	case BlockTypeWith:
+		for _, owner := range append(owners, dashboard) {
+			if withProvider, ok := owner.(WithProvider); ok {
+				if with, found := withProvider.GetWith(resourceName); found {
+					return with, true
+				}
+			}
+		}
+	case BlockTypeParam:
+		for _, owner := range owners {
+			if queryProvider, ok := owner.(QueryProvider); ok {
+				for _, p := range queryProvider.GetParams() {
+					// check short name not resource name (which is unqualified name)
+					if p.ShortName == d.PropertyPath.Name {
+						return owner, true
+					}
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
 func (d *RuntimeDependency) Equals(other *RuntimeDependency) bool {
 	// TargetPropertyPath
 	if d.PropertyPath.PropertyPath == nil {
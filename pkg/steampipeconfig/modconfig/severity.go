@@ -0,0 +1,20 @@
+package modconfig
+
+import "strings"
+
+// ControlSeverities is the list of supported control severities, in increasing order of severity
+var ControlSeverities = []string{"none", "info", "low", "medium", "high", "critical"}
+
+// SeverityRank returns the position of the severity in ControlSeverities, and whether the severity is supported
+func SeverityRank(severity string) (int, bool) {
+	for i, s := range ControlSeverities {
+		if s == severity {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func severitiesString() string {
+	return strings.Join(ControlSeverities, ", ")
+}
@@ -0,0 +1,98 @@
+package parse
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/turbot/pipe-fittings/hclhelpers"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// unresolvedBaseDiags returns diagnostics for any unresolved blocks whose 'base' property cannot be resolved.
+// A base may be in the current mod or (using a mod qualified name) in a dependency mod.
+// If the base is in a dependency mod which has not been loaded, the block remains unresolved
+// (the dependency is registered by handleDecodeDiags) - this function is called once decoding can make no further progress,
+// to distinguish a base resource which does not exist from a base in a dependency mod which is not loaded
+func (m *ModParseContext) unresolvedBaseDiags() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	// a block may appear more than once in unresolved blocks
+	handledBlocks := make(map[*hcl.Block]bool)
+	for _, unresolved := range m.UnresolvedBlocks {
+		if handledBlocks[unresolved.Block] {
+			continue
+		}
+		handledBlocks[unresolved.Block] = true
+
+		if diag := m.validateBaseReference(unresolved.Block); diag != nil {
+			diags = append(diags, diag)
+		}
+	}
+	sortDiagnostics(diags)
+	return diags
+}
+
+// validateBaseReference checks whether the base of the given block (if any) can be resolved,
+// returning a diagnostic if the base mod is not loaded or the base resource does not exist
+func (m *ModParseContext) validateBaseReference(block *hcl.Block) *hcl.Diagnostic {
+	body, ok := block.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+	attr, ok := body.Attributes["base"]
+	if !ok {
+		return nil
+	}
+	traversal, diags := hcl.AbsTraversalForExpr(attr.Expr)
+	if diags.HasErrors() {
+		// the base is not a simple reference - this will be reported when the block is decoded
+		return nil
+	}
+	baseName := hclhelpers.TraversalAsString(traversal)
+	parsedName, err := modconfig.ParseResourceName(baseName)
+	if err != nil {
+		return &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("invalid base '%s'", baseName),
+			Detail:   err.Error(),
+			Subject:  attr.Expr.Range().Ptr(),
+		}
+	}
+
+	// if the base is in a dependency mod, is the mod loaded?
+	if parsedName.Mod != "" && parsedName.Mod != m.CurrentMod.ShortName && m.getDependencyModByShortName(parsedName.Mod) == nil {
+		return &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("base mod not loaded: '%s'", parsedName.Mod),
+			Detail:   fmt.Sprintf("base '%s' is in mod '%s' which is not a loaded dependency of mod '%s'", baseName, parsedName.Mod, m.CurrentMod.ShortName),
+			Subject:  attr.Expr.Range().Ptr(),
+		}
+	}
+
+	// if the base is itself unresolved, this is a dependency issue which is reported separately
+	if parsedName.Mod == "" || parsedName.Mod == m.CurrentMod.ShortName {
+		if _, unresolved := m.UnresolvedBlocks[parsedName.ToResourceName()]; unresolved {
+			return nil
+		}
+	}
+
+	if _, found := m.GetResource(parsedName); !found {
+		return &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("base not found: '%s'", baseName),
+			Detail:   fmt.Sprintf("could not find %s '%s' to use as base", parsedName.ItemType, baseName),
+			Subject:  attr.Expr.Range().Ptr(),
+		}
+	}
+	return nil
+}
+
+// getDependencyModByShortName returns the loaded top level dependency mod with the given short name, if any
+func (m *ModParseContext) getDependencyModByShortName(modShortName string) *modconfig.Mod {
+	for _, depMod := range m.topLevelDependencyMods {
+		if depMod.ShortName == modShortName {
+			return depMod
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package parse
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/turbot/pipe-fittings/hclhelpers"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// unresolvedChildDiags returns diagnostics for any unresolved benchmarks with a child in a dependency mod
+// which cannot be resolved, either because the dependency mod is not loaded or the child does not exist in it.
+// Like unresolvedBaseDiags, this is called once decoding can make no further progress
+// NOTE: unresolved children in the current mod are reported as dependency errors
+func (m *ModParseContext) unresolvedChildDiags() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	// a block may appear more than once in unresolved blocks
+	handledBlocks := make(map[*hcl.Block]bool)
+	for _, unresolved := range m.UnresolvedBlocks {
+		if handledBlocks[unresolved.Block] || unresolved.Block.Type != modconfig.BlockTypeBenchmark {
+			continue
+		}
+		handledBlocks[unresolved.Block] = true
+
+		diags = append(diags, m.validateDependencyChildReferences(unresolved.Block)...)
+	}
+	sortDiagnostics(diags)
+	return diags
+}
+
+// validateDependencyChildReferences checks whether the children of the given benchmark block which are in a
+// dependency mod can be resolved, returning a diagnostic for each child whose mod is not loaded or which does not exist
+func (m *ModParseContext) validateDependencyChildReferences(block *hcl.Block) hcl.Diagnostics {
+	body, ok := block.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+	attr, ok := body.Attributes["children"]
+	if !ok {
+		return nil
+	}
+	childExprs, diags := hcl.ExprList(attr.Expr)
+	if diags.HasErrors() {
+		// the children are not a list - this will be reported when the block is decoded
+		return nil
+	}
+
+	var res hcl.Diagnostics
+	for _, childExpr := range childExprs {
+		traversal, diags := hcl.AbsTraversalForExpr(childExpr)
+		if diags.HasErrors() {
+			continue
+		}
+		childName := hclhelpers.TraversalAsString(traversal)
+		parsedName, err := modconfig.ParseResourceName(childName)
+		if err != nil || parsedName.Mod == "" || parsedName.Mod == m.CurrentMod.ShortName {
+			continue
+		}
+
+		if m.GetMod(parsedName.Mod) == nil {
+			res = append(res, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("child mod not loaded: '%s'", parsedName.Mod),
+				Detail:   fmt.Sprintf("child '%s' of %s is in mod '%s' which is not a loaded, required dependency of mod '%s'", childName, block.Labels[0], parsedName.Mod, m.CurrentMod.ShortName),
+				Subject:  childExpr.Range().Ptr(),
+			})
+			continue
+		}
+		if _, found := m.GetResource(parsedName); !found {
+			res = append(res, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("child not found: '%s'", childName),
+				Detail:   fmt.Sprintf("could not find %s '%s' in dependency mod '%s'", parsedName.ItemType, parsedName.Name, parsedName.Mod),
+				Subject:  childExpr.Range().Ptr(),
+			})
+		}
+	}
+	return res
+}
@@ -2,7 +2,9 @@ package parse
 
 import (
 	"fmt"
+	"sort"
 
+	"github.com/agext/levenshtein"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -10,11 +12,17 @@ import (
 	"github.com/turbot/pipe-fittings/hclhelpers"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig/var_config"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"golang.org/x/exp/maps"
 )
 
 // A consistent detail message for all "not a valid identifier" diagnostics.
 const badIdentifierDetail = "A name must start with a letter or underscore and may contain only letters, digits, underscores, and dashes."
 
+// the maximum edit distance between an unsupported name and a supported name for the latter to be suggested
+const maxNameSuggestionDistance = 2
+
 var missingVariableErrors = []string{
 	// returned when the context variables does not have top level 'type' node (locals/control/etc)
 	"Unknown variable",
@@ -36,32 +44,100 @@ func decode(parseCtx *ModParseContext) hcl.Diagnostics {
 		return diags
 	}
 
+	// if we are decoding in parallel, group the blocks into batches of independent blocks
+	// NOTE: this must be done before the dependencies are cleared
+	var batches []hcl.Blocks
+	if parseCtx.ParallelDecode() {
+		batches, err = parseCtx.DecodeBatches(blocks)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "failed to determine independent blocks",
+				Detail:   err.Error()})
+			return diags
+		}
+	}
+
 	// now clear dependencies from run context - they will be rebuilt
 	parseCtx.ClearDependencies()
 
-	for _, block := range blocks {
-		if block.Type == modconfig.BlockTypeLocals {
-			resources, res := decodeLocalsBlock(block, parseCtx)
-			if !res.Success() {
-				diags = append(diags, res.Diags...)
-				continue
+	// if we are decoding incrementally, we now own the blocks - release each block as soon as it is decoded
+	if parseCtx.IncrementalDecode() {
+		parseCtx.releaseDecodeContent()
+	}
+
+	if parseCtx.ParallelDecode() {
+		for _, batch := range batches {
+			diags = append(diags, decodeBatch(batch, parseCtx)...)
+		}
+	} else {
+		for i, block := range blocks {
+			resources, blockDiags := decodeBlockResources(block, parseCtx)
+			diags = append(diags, blockDiags...)
+			diags = append(diags, addResourcesToMod(resources, block, parseCtx)...)
+			if parseCtx.IncrementalDecode() && len(resources) > 0 {
+				blocks[i] = nil
+				parseCtx.releaseBlock(block, resources)
 			}
-			for _, resource := range resources {
-				resourceDiags := addResourceToMod(resource, block, parseCtx)
-				diags = append(diags, resourceDiags...)
+		}
+	}
+
+	// references to undeclared variables will never be resolved - report them now
+	diags = append(diags, validateVariableReferences(parseCtx)...)
+
+	// sort the diagnostics so they are reported in a deterministic order
+	sortDiagnostics(diags)
+
+	return diags
+}
+
+// sortDiagnostics sorts diagnostics by file, line, column and severity (errors first)
+// Diagnostics with no subject are ordered after those with a subject.
+// The sort is stable, so diagnostics with identical positions retain their relative order
+func sortDiagnostics(diags hcl.Diagnostics) {
+	sort.SliceStable(diags, func(i, j int) bool {
+		a, b := diags[i], diags[j]
+		if (a.Subject == nil) != (b.Subject == nil) {
+			return a.Subject != nil
+		}
+		if a.Subject != nil {
+			if a.Subject.Filename != b.Subject.Filename {
+				return a.Subject.Filename < b.Subject.Filename
 			}
-		} else {
-			resource, res := decodeBlock(block, parseCtx)
-			diags = append(diags, res.Diags...)
-			if !res.Success() || resource == nil {
-				continue
+			if a.Subject.Start.Line != b.Subject.Start.Line {
+				return a.Subject.Start.Line < b.Subject.Start.Line
+			}
+			if a.Subject.Start.Column != b.Subject.Start.Column {
+				return a.Subject.Start.Column < b.Subject.Start.Column
 			}
+		}
+		// hcl.DiagError is less than hcl.DiagWarning
+		return a.Severity < b.Severity
+	})
+}
 
-			resourceDiags := addResourceToMod(resource, block, parseCtx)
-			diags = append(diags, resourceDiags...)
+// decodeBlockResources decodes the given block, returning the successfully decoded resources
+func decodeBlockResources(block *hcl.Block, parseCtx *ModParseContext) ([]modconfig.HclResource, hcl.Diagnostics) {
+	if block.Type == modconfig.BlockTypeLocals {
+		resources, res := decodeLocalsBlock(block, parseCtx)
+		if !res.Success() {
+			return nil, res.Diags
 		}
+		return resources, nil
 	}
 
+	resource, res := decodeBlock(block, parseCtx)
+	if !res.Success() || resource == nil {
+		return nil, res.Diags
+	}
+	return []modconfig.HclResource{resource}, res.Diags
+}
+
+func addResourcesToMod(resources []modconfig.HclResource, block *hcl.Block, parseCtx *ModParseContext) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, resource := range resources {
+		diags = append(diags, addResourceToMod(resource, block, parseCtx)...)
+	}
 	return diags
 }
 
@@ -79,6 +155,10 @@ func shouldAddToMod(resource modconfig.HclResource, block *hcl.Block, parseCtx *
 	case *modconfig.Mod, *modconfig.DashboardWith:
 		return false
 
+	case *modconfig.Benchmark:
+		// do not add benchmarks whose 'if' condition evaluated false
+		return !parseCtx.isDisabledBenchmark(resource.Name())
+
 	case *modconfig.DashboardCategory, *modconfig.DashboardInput:
 		// if this is a dashboard category or dashboard input, only add top level blocks
 		// this is to allow nested categories/inputs to have the same name as top level categories
@@ -262,6 +342,11 @@ func decodeLocals(block *hcl.Block, parseCtx *ModParseContext) ([]*modconfig.Loc
 			})
 			continue
 		}
+		// check whether this local has already been defined in another locals block
+		if existing, duplicate := parseCtx.addLocalDefinition(name, attr.NameRange); duplicate {
+			res.Diags = append(res.Diags, duplicateLocalDiag(name, existing, attr.NameRange))
+			continue
+		}
 		// try to evaluate expression
 		val, diags := attr.Expr.Value(parseCtx.EvalCtx)
 		// handle any resulting diags, which may specify dependencies
@@ -273,6 +358,21 @@ func decodeLocals(block *hcl.Block, parseCtx *ModParseContext) ([]*modconfig.Loc
 	return locals, res
 }
 
+// duplicateLocalDiag returns the diagnostic for a local which is defined more than once
+// blocks may be decoded in any order, so the diagnostic always refers to the later definition in the source
+func duplicateLocalDiag(name string, a, b hcl.Range) *hcl.Diagnostic {
+	first, second := a, b
+	if b.Filename < a.Filename || (b.Filename == a.Filename && b.Start.Byte < a.Start.Byte) {
+		first, second = b, a
+	}
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  fmt.Sprintf("Duplicate local value '%s'", name),
+		Detail:   fmt.Sprintf("A local value named '%s' was already defined at %s. Local value names must be unique within a mod.", name, first),
+		Subject:  &second,
+	}
+}
+
 func decodeVariable(block *hcl.Block, parseCtx *ModParseContext) (*modconfig.Variable, *DecodeResult) {
 	res := newDecodeResult()
 
@@ -306,6 +406,20 @@ func decodeQueryProvider(block *hcl.Block, parseCtx *ModParseContext) (modconfig
 		return nil, res
 	}
 
+	// a control severity may reference row data - if so, remove it from the body so it is not evaluated now
+	if control, ok := resource.(*modconfig.Control); ok {
+		remain = decodeRowSeverity(remain.(*hclsyntax.Body), control, parseCtx)
+	}
+	// an input default may reference another input - decode it separately and remove it from the body
+	if input, ok := resource.(*modconfig.DashboardInput); ok {
+		var moreDiags hcl.Diagnostics
+		remain, moreDiags = decodeInputDefault(remain.(*hclsyntax.Body), input, parseCtx)
+		res.handleDecodeDiags(moreDiags)
+		if !res.Success() {
+			return nil, res
+		}
+	}
+
 	// decode the body into 'resource' to populate all properties that can be automatically decoded
 	diags = decodeHclBody(remain, parseCtx.EvalCtx, parseCtx, resource)
 	res.handleDecodeDiags(diags)
@@ -316,6 +430,105 @@ func decodeQueryProvider(block *hcl.Block, parseCtx *ModParseContext) (modconfig
 	return resource.(modconfig.QueryProvider), res
 }
 
+// decodeRowSeverity checks whether the control severity is an expression which references row data
+// if so, the expression is stored on the control to be evaluated for each row at execution time,
+// and a copy of the body without the severity attribute is returned
+func decodeRowSeverity(body *hclsyntax.Body, control *modconfig.Control, parseCtx *ModParseContext) *hclsyntax.Body {
+	attr, ok := body.Attributes["severity"]
+	if !ok {
+		return body
+	}
+	referencesRow := false
+	for _, traversal := range attr.Expr.Variables() {
+		if traversal.RootName() == "row" {
+			referencesRow = true
+			break
+		}
+	}
+	if !referencesRow {
+		return body
+	}
+
+	control.SetSeverityExpression(attr.Expr, parseCtx.EvalCtx)
+	bodyCopy := *body
+	bodyCopy.Attributes = maps.Clone(body.Attributes)
+	delete(bodyCopy.Attributes, "severity")
+	return &bodyCopy
+}
+
+// decodeInputDefault decodes the input default, which is either a string or a reference to the value of another input,
+// e.g. self.input.region.value, and returns a copy of the body without the default attribute
+func decodeInputDefault(body *hclsyntax.Body, input *modconfig.DashboardInput, parseCtx *ModParseContext) (*hclsyntax.Body, hcl.Diagnostics) {
+	attr, ok := body.Attributes["default"]
+	if !ok {
+		return body, nil
+	}
+	bodyCopy := *body
+	bodyCopy.Attributes = maps.Clone(body.Attributes)
+	delete(bodyCopy.Attributes, "default")
+
+	// unless the default references another input (via 'self'), it is a literal value
+	referencesSelf := false
+	for _, traversal := range attr.Expr.Variables() {
+		if traversal.RootName() == modconfig.RuntimeDependencyDashboardScope {
+			referencesSelf = true
+			break
+		}
+	}
+	if !referencesSelf {
+		defaultValue, diags := decodeInputDefaultValue(attr, input, parseCtx)
+		input.Default = defaultValue
+		return &bodyCopy, diags
+	}
+
+	runtimeDependency, err := getRuntimeDepFromExpression(attr.Expr, "default", "default")
+	if err == nil && runtimeDependency.PropertyPath.ItemType != modconfig.BlockTypeInput {
+		err = fmt.Errorf("the default may only reference the value of another input, e.g. self.input.<name>.value")
+	}
+	if err != nil {
+		return &bodyCopy, hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("%s has invalid default", input.Name()),
+			Detail:   err.Error(),
+			Subject:  attr.Range().Ptr(),
+		}}
+	}
+	input.DefaultDependency = runtimeDependency
+	return &bodyCopy, nil
+}
+
+// decodeInputDefaultValue decodes a literal input default into either a string or a list of strings
+// whether a list is valid for the input type is validated when the input is decoded
+func decodeInputDefaultValue(attr *hclsyntax.Attribute, input *modconfig.DashboardInput, parseCtx *ModParseContext) (any, hcl.Diagnostics) {
+	val, diags := attr.Expr.Value(parseCtx.EvalCtx)
+	if diags.HasErrors() || val.IsNull() {
+		return nil, diags
+	}
+	ty := cty.String
+	if val.Type().IsTupleType() || val.Type().IsListType() || val.Type().IsSetType() {
+		ty = cty.List(cty.String)
+	}
+	val, err := convert.Convert(val, ty)
+	if err != nil {
+		return nil, hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("%s has invalid default", input.Name()),
+			Detail:   fmt.Sprintf("default must be a string or a list of strings: %s", err.Error()),
+			Subject:  attr.Range().Ptr(),
+		}}
+	}
+	defaultValue, err := hclhelpers.CtyToGo(val)
+	if err != nil {
+		return nil, hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("%s has invalid default", input.Name()),
+			Detail:   err.Error(),
+			Subject:  attr.Range().Ptr(),
+		}}
+	}
+	return defaultValue, nil
+}
+
 func decodeQueryProviderBlocks(block *hcl.Block, content *hclsyntax.Body, resource modconfig.HclResource, parseCtx *ModParseContext) *DecodeResult {
 	var diags hcl.Diagnostics
 	res := newDecodeResult()
@@ -492,6 +705,10 @@ func decodeDashboardBlocks(content *hclsyntax.Body, dashboard *modconfig.Dashboa
 
 	for _, b := range content.Blocks {
 		block := b.AsHCLBlock()
+		// labels blocks are decoded into the dashboard by decodeHclBody
+		if block.Type == modconfig.BlockTypeLabels {
+			continue
+		}
 
 		// decode block
 		resource, blockRes := decodeBlock(block, parseCtx)
@@ -499,6 +716,12 @@ func decodeDashboardBlocks(content *hclsyntax.Body, dashboard *modconfig.Dashboa
 		if !blockRes.Success() {
 			continue
 		}
+		// if the block has a condition which evaluates false, exclude it
+		if include, moreDiags := evaluateChildCondition(resource, parseCtx); !include {
+			// (the condition may depend on resources which are not yet decoded)
+			res.handleDecodeDiags(moreDiags)
+			continue
+		}
 
 		// we expect either inputs or child report nodes
 		// add the resource to the mod
@@ -517,6 +740,22 @@ func decodeDashboardBlocks(content *hclsyntax.Body, dashboard *modconfig.Dashboa
 	return res
 }
 
+// evaluateChildCondition evaluates the 'if' condition of a dashboard child, returning whether it should be included
+// conditions which reference inputs cannot be evaluated until the dashboard is executed, so these children are included
+func evaluateChildCondition(resource modconfig.HclResource, parseCtx *ModParseContext) (bool, hcl.Diagnostics) {
+	treeItem, ok := resource.(modconfig.ModTreeItem)
+	if !ok {
+		return true, nil
+	}
+	impl := treeItem.GetModTreeItemImpl()
+	if len(impl.ConditionInputs()) > 0 {
+		// store the eval context so the condition may reference variables and locals when it is evaluated
+		impl.SetConditionEvalContext(parseCtx.EvalCtx)
+		return true, nil
+	}
+	return impl.EvaluateCondition(parseCtx.EvalCtx)
+}
+
 func decodeDashboardContainer(block *hcl.Block, parseCtx *ModParseContext) (*modconfig.DashboardContainer, *DecodeResult) {
 	res := newDecodeResult()
 	container := modconfig.NewDashboardContainer(block, parseCtx.CurrentMod, parseCtx.DetermineBlockName(block)).(*modconfig.DashboardContainer)
@@ -559,6 +798,12 @@ func decodeDashboardContainerBlocks(content *hclsyntax.Body, dashboardContainer
 		if !blockRes.Success() {
 			continue
 		}
+		// if the block has a condition which evaluates false, exclude it
+		if include, moreDiags := evaluateChildCondition(resource, parseCtx); !include {
+			// (the condition may depend on resources which are not yet decoded)
+			res.handleDecodeDiags(moreDiags)
+			continue
+		}
 
 		// special handling for inputs
 		if b.Type == modconfig.BlockTypeInput {
@@ -588,6 +833,12 @@ func decodeBenchmark(block *hcl.Block, parseCtx *ModParseContext) (*modconfig.Be
 	diags = decodeProperty(content, "children", &benchmark.ChildNames, parseCtx.EvalCtx)
 	res.handleDecodeDiags(diags)
 
+	diags = decodeProperty(content, "child_order", &benchmark.ChildOrder, parseCtx.EvalCtx)
+	res.handleDecodeDiags(diags)
+
+	diags = decodeProperty(content, "deprecated", &benchmark.Deprecated, parseCtx.EvalCtx)
+	res.handleDecodeDiags(diags)
+
 	diags = decodeProperty(content, "description", &benchmark.Description, parseCtx.EvalCtx)
 	res.handleDecodeDiags(diags)
 
@@ -597,8 +848,9 @@ func decodeBenchmark(block *hcl.Block, parseCtx *ModParseContext) (*modconfig.Be
 	diags = decodeProperty(content, "tags", &benchmark.Tags, parseCtx.EvalCtx)
 	res.handleDecodeDiags(diags)
 
-	diags = decodeProperty(content, "title", &benchmark.Title, parseCtx.EvalCtx)
-	res.handleDecodeDiags(diags)
+	// the title may reference the benchmark tags, e.g. title = "${self.tags.section} - Checks"
+	// NOTE: this relies on the tags having been decoded above
+	decodeSelfTagsProperty(content, "title", &benchmark.Title, benchmark.Tags, res, parseCtx)
 
 	diags = decodeProperty(content, "type", &benchmark.Type, parseCtx.EvalCtx)
 	res.handleDecodeDiags(diags)
@@ -606,15 +858,52 @@ func decodeBenchmark(block *hcl.Block, parseCtx *ModParseContext) (*modconfig.Be
 	diags = decodeProperty(content, "display", &benchmark.Display, parseCtx.EvalCtx)
 	res.handleDecodeDiags(diags)
 
+	diags = decodeProperty(content, "weight", &benchmark.Weight, parseCtx.EvalCtx)
+	res.handleDecodeDiags(diags)
+
+	diags = decodeProperty(content, "include", &benchmark.Include, parseCtx.EvalCtx)
+	res.handleDecodeDiags(diags)
+
+	diags = decodeProperty(content, "exclude", &benchmark.Exclude, parseCtx.EvalCtx)
+	res.handleDecodeDiags(diags)
+
+	diags = decodeProperty(content, "min_severity", &benchmark.MinSeverity, parseCtx.EvalCtx)
+	res.handleDecodeDiags(diags)
+
+	diags = decodeProperty(content, "required_severity", &benchmark.RequiredSeverity, parseCtx.EvalCtx)
+	res.handleDecodeDiags(diags)
+
+	// if the benchmark has a condition which evaluates false, the benchmark (and so its subtree) is excluded
+	// NOTE: the benchmark is still added to the run context so parents which reference it can be decoded
+	enabled := true
+	if attr, ok := content.Attributes["if"]; ok {
+		benchmark.If = attr.Expr
+		enabled, diags = benchmark.EvaluateCondition(parseCtx.EvalCtx)
+		res.handleDecodeDiags(diags)
+		if res.Success() && !enabled {
+			parseCtx.addDisabledBenchmark(benchmark)
+		}
+	}
+
 	// now add children
-	if res.Success() {
+	if res.Success() && enabled {
+		childNames := benchmark.ChildNames.StringList()
+		// add any controls selected by the include/exclude patterns
+		if len(benchmark.Include) > 0 || len(benchmark.Exclude) > 0 {
+			selectedNames, selectionRes := resolveChildNamesFromPatterns(benchmark, childNames, block, parseCtx)
+			res.Merge(selectionRes)
+			if !res.Success() {
+				return benchmark, res
+			}
+			childNames = append(childNames, selectedNames...)
+		}
+
 		supportedChildren := []string{modconfig.BlockTypeBenchmark, modconfig.BlockTypeControl}
-		children, diags := resolveChildrenFromNames(benchmark.ChildNames.StringList(), block, supportedChildren, parseCtx)
+		children, diags := resolveChildrenFromNames(childNames, block, supportedChildren, parseCtx)
 		res.handleDecodeDiags(diags)
 
-		// now set children and child name strings
+		// now set children (this also sets the child name strings)
 		benchmark.SetChildren(children)
-		benchmark.ChildNameStrings = getChildNameStringsFromModTreeItem(children)
 	}
 
 	diags = decodeProperty(content, "base", &benchmark.Base, parseCtx.EvalCtx)
@@ -639,6 +928,48 @@ func decodeProperty(content *hcl.BodyContent, property string, dest interface{},
 	return diags
 }
 
+// decodeSelfTagsProperty decodes a property which may reference the tags of the resource being decoded, using 'self.tags'
+// a reference to a tag which does not exist is an error (rather than an unresolved dependency)
+func decodeSelfTagsProperty(content *hcl.BodyContent, property string, dest interface{}, tags map[string]string, res *DecodeResult, parseCtx *ModParseContext) {
+	tagValues := make(map[string]cty.Value, len(tags))
+	for k, v := range tags {
+		tagValues[k] = cty.StringVal(v)
+	}
+	evalCtx := parseCtx.EvalCtx.NewChild()
+	evalCtx.Variables = map[string]cty.Value{
+		"self": cty.ObjectVal(map[string]cty.Value{"tags": cty.ObjectVal(tagValues)}),
+	}
+
+	diags := decodeProperty(content, property, dest, evalCtx)
+	var otherDiags hcl.Diagnostics
+	for _, diag := range diags {
+		if diag.Expression != nil && referencesSelf(diag.Expression) {
+			// if the tags could not be decoded, this block will be decoded again once its dependencies are resolved
+			// - otherwise, a failed self reference means the tag does not exist
+			if len(res.Depends) == 0 {
+				res.addDiags(hcl.Diagnostics{&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("%s references a tag which is not defined", property),
+					Detail:   diag.Detail,
+					Subject:  diag.Subject,
+				}})
+			}
+			continue
+		}
+		otherDiags = append(otherDiags, diag)
+	}
+	res.handleDecodeDiags(otherDiags)
+}
+
+func referencesSelf(expr hcl.Expression) bool {
+	for _, v := range expr.Variables() {
+		if v.RootName() == "self" {
+			return true
+		}
+	}
+	return false
+}
+
 // handleModDecodeResult
 // if decode was successful:
 // - generate and set resource metadata
@@ -654,6 +985,10 @@ func handleModDecodeResult(resource modconfig.HclResource, res *DecodeResult, bl
 	// set whether this is a top level resource
 	resource.SetTopLevel(parseCtx.IsTopLevelBlock(block))
 
+	// if a title resolver is set, use it to resolve the title
+	// NOTE: this must be done BEFORE calling OnDecoded, so a resolved title takes precedence over a base title
+	resolveTitle(resource, parseCtx)
+
 	// call post decode hook
 	// NOTE: must do this BEFORE adding resource to run context to ensure we respect the base property
 	moreDiags := resource.OnDecoded(block, parseCtx)
@@ -685,6 +1020,20 @@ func handleModDecodeResult(resource modconfig.HclResource, res *DecodeResult, bl
 	}
 }
 
+// resolveTitle sets the title of benchmarks and controls using the title resolver of the parse context (if any)
+// if the resolver does not provide a title, the HCL title is retained
+func resolveTitle(resource modconfig.HclResource, parseCtx *ModParseContext) {
+	if parseCtx.TitleResolver == nil {
+		return
+	}
+	switch resource.(type) {
+	case *modconfig.Benchmark, *modconfig.Control:
+		if title, ok := parseCtx.TitleResolver(resource.Name()); ok {
+			resource.GetHclResourceImpl().Title = &title
+		}
+	}
+}
+
 func resourceIsAnonymous(resource modconfig.HclResource) bool {
 	// (if a resource anonymous it must support ResourceWithMetadata)
 	resourceWithMetadata, ok := resource.(modconfig.ResourceWithMetadata)
@@ -745,6 +1094,7 @@ func validateHcl(blockType string, body *hclsyntax.Body, schema *hcl.BodySchema)
 			diags = append(diags, &hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  fmt.Sprintf(`Unsupported block type: Blocks of type '%s' are not expected here.`, block.Type),
+				Detail:   nameSuggestionDetail(block.Type, maps.Keys(supportedBlocks)),
 				Subject:  &block.TypeRange,
 			})
 		}
@@ -763,6 +1113,7 @@ func validateHcl(blockType string, body *hclsyntax.Body, schema *hcl.BodySchema)
 				diags = append(diags, &hcl.Diagnostic{
 					Severity: hcl.DiagError,
 					Summary:  fmt.Sprintf(`Unsupported attribute: '%s' not expected here.`, attribute.Name),
+					Detail:   nameSuggestionDetail(attribute.Name, maps.Keys(supportedAttributes)),
 					Subject:  &subject,
 				})
 			}
@@ -772,6 +1123,25 @@ func validateHcl(blockType string, body *hclsyntax.Body, schema *hcl.BodySchema)
 	return diags
 }
 
+// nameSuggestionDetail returns a diagnostic detail suggesting the supported name closest to the given name,
+// or an empty string if no supported name is within maxNameSuggestionDistance edits
+func nameSuggestionDetail(name string, supportedNames []string) string {
+	// sort the names so the suggestion is stable if more than one name is equally close
+	sort.Strings(supportedNames)
+	suggestion := ""
+	bestDistance := maxNameSuggestionDistance + 1
+	for _, supportedName := range supportedNames {
+		if distance := levenshtein.Distance(name, supportedName, nil); distance < bestDistance {
+			suggestion = supportedName
+			bestDistance = distance
+		}
+	}
+	if suggestion == "" {
+		return ""
+	}
+	return fmt.Sprintf("Did you mean %q?", suggestion)
+}
+
 func isDeprecated(attribute *hclsyntax.Attribute, blockType string) bool {
 	switch attribute.Name {
 	case "search_path", "search_path_prefix":
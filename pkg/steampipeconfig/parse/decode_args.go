@@ -8,8 +8,10 @@ import (
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/turbot/go-kit/helpers"
 	"github.com/turbot/pipe-fittings/hclhelpers"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/inputvars/typeexpr"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 	"github.com/zclconf/go-cty/cty/gocty"
 )
 
@@ -292,8 +294,17 @@ func decodeParam(block *hcl.Block, parseCtx *ModParseContext) (*modconfig.ParamD
 		moreDiags := gohcl.DecodeExpression(attr.Expr, parseCtx.EvalCtx, &def.Description)
 		diags = append(diags, moreDiags...)
 	}
+	// decode the type before the default, so the default can be validated against the type
+	if attr, exists := content.Attributes["type"]; exists {
+		ty, moreDiags := typeexpr.TypeConstraint(attr.Expr)
+		diags = append(diags, moreDiags...)
+		if !moreDiags.HasErrors() {
+			def.Type = ty
+			def.TypeString = hclhelpers.CtyTypeToHclType(ty)
+		}
+	}
 	if attr, exists := content.Attributes["default"]; exists {
-		defaultValue, deps, moreDiags := decodeParamDefault(attr, parseCtx, def.UnqualifiedName)
+		defaultValue, deps, moreDiags := decodeParamDefault(attr, parseCtx, def.UnqualifiedName, def.Type)
 		diags = append(diags, moreDiags...)
 		if !helpers.IsNil(defaultValue) {
 			def.SetDefault(defaultValue)
@@ -303,10 +314,26 @@ func decodeParam(block *hcl.Block, parseCtx *ModParseContext) (*modconfig.ParamD
 	return def, runtimeDependencies, diags
 }
 
-func decodeParamDefault(attr *hcl.Attribute, parseCtx *ModParseContext, paramName string) (any, []*modconfig.RuntimeDependency, hcl.Diagnostics) {
+// decodeParamDefault evaluates the param default, which may be any cty value, including lists, maps and objects
+// if the param has a type constraint, the default is converted to that type
+func decodeParamDefault(attr *hcl.Attribute, parseCtx *ModParseContext, paramName string, ty cty.Type) (any, []*modconfig.RuntimeDependency, hcl.Diagnostics) {
 	v, diags := attr.Expr.Value(parseCtx.EvalCtx)
 
-	if v.IsKnown() {
+	// NOTE: check the value is wholly known, as a list or map may contain unknown elements
+	if v.IsWhollyKnown() {
+		if ty != cty.NilType {
+			var err error
+			v, err = convert.Convert(v, ty)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("%s default does not match type %s", paramName, hclhelpers.CtyTypeToHclType(ty)),
+					Detail:   err.Error(),
+					Subject:  &attr.Range,
+				})
+				return nil, nil, diags
+			}
+		}
 		// convert the raw default into a string representation
 		val, err := hclhelpers.CtyToGo(v)
 		if err != nil {
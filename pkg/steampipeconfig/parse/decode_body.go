@@ -156,13 +156,16 @@ func getSchemaForStruct(t reflect.Type) *hcl.BodySchema {
 		if tag == "" {
 			continue
 		}
-		if idx := strings.LastIndex(tag, ",block"); idx != -1 {
-			blockName := tag[:idx]
-			schema.Blocks = append(schema.Blocks, hcl.BlockHeaderSchema{Type: blockName})
-		} else {
-			attributeName := strings.Split(tag, ",")[0]
-			if attributeName != "" {
-				schema.Attributes = append(schema.Attributes, hcl.AttributeSchema{Name: attributeName})
+		// the tag is the name followed by an optional kind, e.g. "width", "base,optional", "name,label", ",remain"
+		name, kind, _ := strings.Cut(tag, ",")
+		switch kind {
+		case "block":
+			schema.Blocks = append(schema.Blocks, hcl.BlockHeaderSchema{Type: name})
+		case "label", "remain":
+			// labels and the remaining body are not attributes
+		default:
+			if name != "" {
+				schema.Attributes = append(schema.Attributes, hcl.AttributeSchema{Name: name})
 			}
 		}
 	}
@@ -298,3 +301,38 @@ func getNestedStructVals(val any) (_ []any, diags hcl.Diagnostics) {
 	}
 	return res, nil
 }
+
+// releaseRemain clears the 'remain' bodies used to allow partial decoding of the resource
+// these are not used once the resource is decoded, and retain the parsed hcl of the resource block
+// NOTE: the resource body is decoded into each of its embedded structs, so their remain bodies are also cleared
+func releaseRemain(resource modconfig.HclResource) {
+	v := reflect.ValueOf(resource)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	releaseRemainFields(v.Elem())
+}
+
+func releaseRemainFields(v reflect.Value) {
+	ty := v.Type()
+	for i := 0; i < ty.NumField(); i++ {
+		field := ty.Field(i)
+		fieldVal := v.Field(i)
+		if field.Tag.Get("hcl") == ",remain" {
+			if fieldVal.CanSet() {
+				fieldVal.Set(reflect.Zero(fieldVal.Type()))
+			}
+			continue
+		}
+		// only walk embedded structs - other fields may reference other resources
+		if !field.Anonymous {
+			continue
+		}
+		if fieldVal.Kind() == reflect.Ptr && !fieldVal.IsNil() {
+			fieldVal = fieldVal.Elem()
+		}
+		if fieldVal.Kind() == reflect.Struct {
+			releaseRemainFields(fieldVal)
+		}
+	}
+}
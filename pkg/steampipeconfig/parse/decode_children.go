@@ -2,6 +2,8 @@ package parse
 
 import (
 	"fmt"
+	"path"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/turbot/go-kit/helpers"
@@ -17,9 +19,9 @@ func resolveChildrenFromNames(childNames []string, block *hcl.Block, supportedCh
 	}
 
 	// find the children in the eval context and populate control children
-	children := make([]modconfig.ModTreeItem, len(childNames))
+	children := make([]modconfig.ModTreeItem, 0, len(childNames))
 
-	for i, childName := range childNames {
+	for _, childName := range childNames {
 		parsedName, err := modconfig.ParseResourceName(childName)
 		if err != nil || !helpers.StringSliceContains(supportedChildren, parsedName.ItemType) {
 			diags = append(diags, childErrorDiagnostic(childName, block))
@@ -38,6 +40,11 @@ func resolveChildrenFromNames(childNames []string, block *hcl.Block, supportedCh
 			break
 		}
 
+		// exclude any benchmarks whose 'if' condition evaluated false
+		if parseCtx.isDisabledBenchmark(fmt.Sprintf("%s.%s", mod.ShortName, parsedName.ToResourceName())) {
+			continue
+		}
+
 		resource, found := mod.GetResource(parsedName)
 		// ensure this item is a mod tree item
 		child, ok := resource.(modconfig.ModTreeItem)
@@ -50,7 +57,7 @@ func resolveChildrenFromNames(childNames []string, block *hcl.Block, supportedCh
 			continue
 		}
 
-		children[i] = child
+		children = append(children, child)
 	}
 	if diags.HasErrors() {
 		return nil, diags
@@ -86,10 +93,173 @@ func childErrorDiagnostic(childName string, block *hcl.Block) *hcl.Diagnostic {
 	}
 }
 
-func getChildNameStringsFromModTreeItem(children []modconfig.ModTreeItem) []string {
-	res := make([]string, len(children))
-	for i, n := range children {
-		res[i] = n.Name()
+// resolveChildNamesFromPatterns returns the full names of the controls in the current mod selected by the
+// include/exclude glob patterns of the benchmark (patterns are matched against the control short name)
+// controls which are already explicit children are not returned
+// if any selected control has not yet been decoded, the result will contain a dependency on it
+func resolveChildNamesFromPatterns(benchmark *modconfig.Benchmark, explicitChildNames []string, block *hcl.Block, parseCtx *ModParseContext) ([]string, *DecodeResult) {
+	res := newDecodeResult()
+	mod := parseCtx.CurrentMod
+
+	// validate the patterns
+	for _, pattern := range append(append([]string{}, benchmark.Include...), benchmark.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			res.addDiags(hcl.Diagnostics{&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("%s has invalid child pattern '%s'", benchmark.Name(), pattern),
+				Detail:   err.Error(),
+				Subject:  &block.DefRange,
+			}})
+		}
+	}
+	if res.Diags.HasErrors() {
+		return nil, res
+	}
+
+	// build map of explicit children, keyed by full name
+	explicitChildren := make(map[string]bool, len(explicitChildNames))
+	for _, childName := range explicitChildNames {
+		if parsedName, err := modconfig.ParseResourceName(childName); err == nil {
+			if fullName, err := parsedName.ToFullNameWithMod(mod.ShortName); err == nil {
+				explicitChildren[fullName] = true
+			}
+		}
+	}
+
+	var warnings hcl.Diagnostics
+	var selectedNames []string
+	// the number of controls matching the patterns, including explicit children
+	matchCount := 0
+	for _, controlName := range parseCtx.controlNames {
+		fullName := fmt.Sprintf("%s.%s.%s", mod.ShortName, modconfig.BlockTypeControl, controlName)
+		excludePattern, excluded := matchChildPattern(controlName, benchmark.Exclude)
+		_, included := matchChildPattern(controlName, benchmark.Include)
+		if included && !excluded {
+			matchCount++
+		}
+		if explicitChildren[fullName] {
+			// an explicit child is always included
+			if excluded {
+				warnings = append(warnings, &hcl.Diagnostic{
+					Severity: hcl.DiagWarning,
+					Summary:  fmt.Sprintf("%s child '%s' matches exclude pattern '%s' - explicit children are always included", benchmark.Name(), fullName, excludePattern),
+					Subject:  &block.DefRange,
+				})
+			}
+			continue
+		}
+		if !included || excluded {
+			continue
+		}
+
+		selectedNames = append(selectedNames, fullName)
+		// if the control has not been decoded yet, add a dependency on it
+		if _, ok := mod.ResourceMaps.Controls[fullName]; !ok {
+			dependency := &modconfig.ResourceDependency{
+				Range: block.DefRange,
+				Traversals: []hcl.Traversal{{
+					hcl.TraverseRoot{Name: modconfig.BlockTypeControl},
+					hcl.TraverseAttr{Name: controlName},
+				}},
+			}
+			res.Depends[dependency.String()] = dependency
+		}
+	}
+
+	// only report warnings once the selection is resolved, to avoid duplicates for each decode pass
+	if len(res.Depends) > 0 {
+		return selectedNames, res
+	}
+	if len(benchmark.Include) == 0 {
+		warnings = append(warnings, &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  fmt.Sprintf("%s has exclude patterns but no include patterns - exclude has no effect", benchmark.Name()),
+			Subject:  &block.DefRange,
+		})
+	} else if matchCount == 0 {
+		warnings = append(warnings, &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  fmt.Sprintf("%s include patterns select no controls", benchmark.Name()),
+			Detail:   fmt.Sprintf("include: %s, exclude: %s", strings.Join(benchmark.Include, ", "), strings.Join(benchmark.Exclude, ", ")),
+			Subject:  &block.DefRange,
+		})
+	}
+	res.addDiags(warnings)
+
+	return selectedNames, res
+}
+
+// matchChildPattern returns the first of the given glob patterns which matches the name, if any
+func matchChildPattern(name string, patterns []string) (string, bool) {
+	for _, pattern := range patterns {
+		// the patterns have already been validated
+		if match, _ := path.Match(pattern, name); match {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// removeDisabledBenchmarkDescendants removes the benchmarks and controls in the subtrees of disabled benchmarks from the mod
+// (otherwise, having no parent, they would be added to the mod as top level children)
+// a descendant is not removed if it is also the child of a resource which is not itself being removed
+func removeDisabledBenchmarkDescendants(mod *modconfig.Mod, parseCtx *ModParseContext) {
+	if len(parseCtx.disabledBenchmarks) == 0 {
+		return
+	}
+
+	// build the set of descendants of the disabled benchmarks
+	// NOTE: the children of disabled benchmarks are not resolved, so use their child names
+	removed := make(map[string]modconfig.ModTreeItem)
+	var addDescendant func(child modconfig.ModTreeItem)
+	addDescendant = func(child modconfig.ModTreeItem) {
+		if _, ok := removed[child.Name()]; ok {
+			return
+		}
+		removed[child.Name()] = child
+		for _, grandchild := range child.GetChildren() {
+			addDescendant(grandchild)
+		}
+	}
+	for _, benchmark := range parseCtx.disabledBenchmarks {
+		for _, childName := range benchmark.ChildNames.StringList() {
+			parsedName, err := modconfig.ParseResourceName(childName)
+			if err != nil {
+				continue
+			}
+			if child, ok := mod.GetResource(parsedName); ok {
+				if treeItem, ok := child.(modconfig.ModTreeItem); ok {
+					addDescendant(treeItem)
+				}
+			}
+		}
+	}
+
+	// now keep any descendants which are also children of a resource which is kept (and so their descendants)
+	for keptChild := true; keptChild; {
+		keptChild = false
+		resourceFunc := func(resource modconfig.HclResource) (bool, error) {
+			treeItem, ok := resource.(modconfig.ModTreeItem)
+			if !ok {
+				return true, nil
+			}
+			if _, isRemoved := removed[treeItem.Name()]; isRemoved {
+				return true, nil
+			}
+			for _, child := range treeItem.GetChildren() {
+				if _, isRemoved := removed[child.Name()]; isRemoved {
+					delete(removed, child.Name())
+					keptChild = true
+				}
+			}
+			return true, nil
+		}
+		// NOTE: resourceFunc never returns an error
+		_ = mod.ResourceMaps.WalkResources(resourceFunc)
+	}
+
+	for name := range removed {
+		delete(mod.ResourceMaps.Benchmarks, name)
+		delete(mod.ResourceMaps.Controls, name)
 	}
-	return res
 }
@@ -0,0 +1,79 @@
+package parse
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// the result of decoding a single block of a batch
+type blockDecodeResult struct {
+	resources []modconfig.HclResource
+	diags     hcl.Diagnostics
+}
+
+// decodeBatch decodes a batch of blocks which have no dependencies on each other
+// blocks are decoded concurrently, except for those which must be decoded sequentially (see decodeSequentially)
+// once all blocks are decoded, the resources are added to the mod in block order, so the result is the same as
+// decoding the blocks sequentially
+func decodeBatch(batch hcl.Blocks, parseCtx *ModParseContext) hcl.Diagnostics {
+	results := make([]blockDecodeResult, len(batch))
+
+	// first decode the blocks which must be decoded sequentially
+	var concurrentIdx []int
+	for i, block := range batch {
+		if decodeSequentially(block) {
+			results[i].resources, results[i].diags = decodeBlockResources(block, parseCtx)
+		} else {
+			concurrentIdx = append(concurrentIdx, i)
+		}
+	}
+
+	// now decode the remaining blocks concurrently
+	// the eval context must not change while the blocks are being decoded
+	// - as the blocks are independent, they do not need the resources decoded by other blocks in the batch
+	parseCtx.beginConcurrentDecode()
+	idxChan := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range idxChan {
+				results[i].resources, results[i].diags = decodeBlockResources(batch[i], parseCtx)
+			}
+		}()
+	}
+	for _, i := range concurrentIdx {
+		idxChan <- i
+	}
+	close(idxChan)
+	wg.Wait()
+	parseCtx.endConcurrentDecode()
+
+	// merge the diagnostics and add the resources to the mod, in block order
+	var diags hcl.Diagnostics
+	for i, block := range batch {
+		diags = append(diags, results[i].diags...)
+		diags = append(diags, addResourcesToMod(results[i].resources, block, parseCtx)...)
+		if parseCtx.IncrementalDecode() && len(results[i].resources) > 0 {
+			batch[i] = nil
+			parseCtx.releaseBlock(block, results[i].resources)
+		}
+	}
+	return diags
+}
+
+// decodeSequentially returns whether the block must not be decoded concurrently with other blocks
+// - the mod block updates the current mod
+// - dashboards and containers push themselves onto the parent stack, which is used to name their child blocks,
+// and add their inputs to the mod
+func decodeSequentially(block *hcl.Block) bool {
+	switch block.Type {
+	case modconfig.BlockTypeMod, modconfig.BlockTypeDashboard, modconfig.BlockTypeContainer:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,4589 @@
+package parse
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	typehelpers "github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/utils"
+	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/exp/maps"
+)
+
+type chartSeriesTest struct {
+	source        string
+	expectedColor string
+	expectedLabel string
+	expectedError string
+}
+
+var testCasesChartSeries = map[string]chartSeriesTest{
+	"hex color": {
+		source: `
+chart "c1" {
+  sql = "select 1"
+  series "s1" {
+    color = "#ff0000"
+    label = "Series 1"
+  }
+}`,
+		expectedColor: "#ff0000",
+		expectedLabel: "Series 1",
+	},
+	"short hex color": {
+		source: `
+chart "c1" {
+  sql = "select 1"
+  series "s1" {
+    color = "#f00"
+  }
+}`,
+		expectedColor: "#f00",
+	},
+	"named color": {
+		source: `
+chart "c1" {
+  sql = "select 1"
+  series "s1" {
+    color = "alert"
+  }
+}`,
+		expectedColor: "alert",
+	},
+	"invalid hex color": {
+		source: `
+chart "c1" {
+  sql = "select 1"
+  series "s1" {
+    color = "#ff00zz"
+  }
+}`,
+		expectedError: "series 's1' has invalid color '#ff00zz'",
+	},
+	"unknown series property": {
+		source: `
+chart "c1" {
+  sql = "select 1"
+  series "s1" {
+    colour = "#ff0000"
+  }
+}`,
+		expectedError: `An argument named "colour" is not expected here`,
+	},
+}
+
+func TestDecodeChartSeries(t *testing.T) {
+	for name, test := range testCasesChartSeries {
+		mod, res := parseTestMod(t, test.source)
+		if !checkDecodeError(t, name, res.Error, test.expectedError) {
+			continue
+		}
+		chart := mod.ResourceMaps.DashboardCharts["local.chart.c1"]
+		if chart == nil {
+			t.Errorf("Test %s FAILED. Chart not found", name)
+			continue
+		}
+		series := chart.Series["s1"]
+		if series == nil {
+			t.Errorf("Test %s FAILED. Series not found", name)
+			continue
+		}
+		if color := typehelpers.SafeString(series.Color); color != test.expectedColor {
+			t.Errorf("Test %s FAILED. Expected color %s, got %s", name, test.expectedColor, color)
+		}
+		if label := typehelpers.SafeString(series.Label); label != test.expectedLabel {
+			t.Errorf("Test %s FAILED. Expected label %s, got %s", name, test.expectedLabel, label)
+		}
+	}
+}
+
+type variableReferenceTest struct {
+	source        string
+	expectedError string
+}
+
+var testCasesVariableReferences = map[string]variableReferenceTest{
+	"declared variable": {
+		source: `
+variable "env" {
+  default = "prod"
+}
+control "c1" {
+  title = var.env
+  sql   = "select 1"
+}`,
+	},
+	"undeclared variable": {
+		source: `
+variable "env" {
+  default = "prod"
+}
+control "c1" {
+  title = var.foo
+  sql   = "select 1"
+}`,
+		expectedError: "variable 'foo' is not declared",
+	},
+}
+
+func TestValidateVariableReferences(t *testing.T) {
+	for name, test := range testCasesVariableReferences {
+		mod, res := parseTestMod(t, test.source)
+		if !checkDecodeError(t, name, res.Error, test.expectedError) {
+			continue
+		}
+		if c := mod.ResourceMaps.Controls["local.control.c1"]; c == nil || typehelpers.SafeString(c.Title) != "prod" {
+			t.Errorf("Test %s FAILED. Expected control title to be resolved from variable", name)
+		}
+	}
+}
+
+type dashboardWithInputTest struct {
+	source        string
+	expectedDeps  []string
+	expectedError string
+}
+
+var testCasesDashboardWithInputs = map[string]dashboardWithInputTest{
+	"input depends on dashboard with": {
+		source: `
+dashboard "d1" {
+  with "w1" {
+    sql = "select 'a' as name"
+  }
+  input "i1" {
+    sql  = "select $1 as label, $1 as value"
+    args = [with.w1.rows[0].name]
+  }
+}`,
+		expectedDeps: []string{"with.w1"},
+	},
+	"cycle between with and input": {
+		source: `
+dashboard "d1" {
+  with "w1" {
+    sql  = "select $1 as name"
+    args = [self.input.i1.value]
+  }
+  input "i1" {
+    sql  = "select $1 as label, $1 as value"
+    args = [with.w1.rows[0].name]
+  }
+}`,
+		expectedError: "Failed to resolve input dependency order for dashboard 'local.dashboard.d1'",
+	},
+}
+
+func TestDashboardWithInputDependencies(t *testing.T) {
+	for name, test := range testCasesDashboardWithInputs {
+		mod, res := parseTestMod(t, test.source)
+		if !checkDecodeError(t, name, res.Error, test.expectedError) {
+			continue
+		}
+		dashboard := mod.ResourceMaps.Dashboards["local.dashboard.d1"]
+		if dashboard == nil {
+			t.Errorf("Test %s FAILED. Dashboard not found", name)
+			continue
+		}
+		input, ok := dashboard.GetInput("input.i1")
+		if !ok {
+			t.Errorf("Test %s FAILED. Input not found", name)
+			continue
+		}
+		var deps []string
+		for _, dep := range input.GetRuntimeDependencies() {
+			deps = append(deps, dep.SourceResourceName())
+		}
+		if strings.Join(deps, ",") != strings.Join(test.expectedDeps, ",") {
+			t.Errorf("Test %s FAILED. Expected dependencies %v, got %v", name, test.expectedDeps, deps)
+		}
+	}
+}
+
+type inputDependencyOrderTest struct {
+	source               string
+	expectedOrder        []string
+	expectedDependencies map[string][]string
+}
+
+var testCasesInputDependencyOrder = map[string]inputDependencyOrderTest{
+	"cascading inputs": {
+		source: `
+dashboard "d1" {
+  input "account" {
+    sql = "select 'a' as label, 'a' as value"
+  }
+  input "region" {
+    sql  = "select $1 as label, $1 as value"
+    args = [self.input.account.value]
+  }
+  input "vpc" {
+    sql  = "select $1 as label, $1 as value"
+    args = [self.input.region.value]
+  }
+  input "other" {
+    sql = "select 'b' as label, 'b' as value"
+  }
+}`,
+		expectedOrder: []string{"input.account", "input.region", "input.vpc", "input.other"},
+		expectedDependencies: map[string][]string{
+			"input.region": {"input.account"},
+			"input.vpc":    {"input.region"},
+		},
+	},
+	"inputs declared before their dependencies": {
+		source: `
+dashboard "d1" {
+  input "vpc" {
+    sql  = "select $1 as label, $1 as value"
+    args = [self.input.region.value]
+  }
+  input "other" {
+    sql = "select 'b' as label, 'b' as value"
+  }
+  input "region" {
+    sql  = "select $1 as label, $1 as value"
+    args = [self.input.account.value]
+  }
+  input "account" {
+    sql = "select 'a' as label, 'a' as value"
+  }
+}`,
+		expectedOrder: []string{"input.other", "input.account", "input.region", "input.vpc"},
+		expectedDependencies: map[string][]string{
+			"input.region": {"input.account"},
+			"input.vpc":    {"input.region"},
+		},
+	},
+	"input depends on input via with": {
+		source: `
+dashboard "d1" {
+  with "w1" {
+    sql  = "select $1 as name"
+    args = [self.input.account.value]
+  }
+  input "account" {
+    sql = "select 'a' as label, 'a' as value"
+  }
+  input "region" {
+    sql  = "select $1 as label, $1 as value"
+    args = [with.w1.rows[0].name]
+  }
+}`,
+		expectedOrder: []string{"input.account", "input.region"},
+		expectedDependencies: map[string][]string{
+			"input.region": {"input.account"},
+		},
+	},
+}
+
+func TestDashboardInputDependencyOrder(t *testing.T) {
+	for name, test := range testCasesInputDependencyOrder {
+		mod, res := parseTestMod(t, test.source)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			continue
+		}
+		dashboard := mod.ResourceMaps.Dashboards["local.dashboard.d1"]
+		if dashboard == nil {
+			t.Errorf("Test %s FAILED. Dashboard not found", name)
+			continue
+		}
+		if order := dashboard.InputDependencyOrder(); !reflect.DeepEqual(order, test.expectedOrder) {
+			t.Errorf("Test %s FAILED. Expected order %v, got %v", name, test.expectedOrder, order)
+		}
+		for _, inputName := range test.expectedOrder {
+			if deps := dashboard.InputDependencies(inputName); !reflect.DeepEqual(deps, test.expectedDependencies[inputName]) {
+				t.Errorf("Test %s FAILED. Expected dependencies of %s to be %v, got %v", name, inputName, test.expectedDependencies[inputName], deps)
+			}
+		}
+	}
+}
+
+type inputsDependingOnTest struct {
+	source             string
+	expectedDependents map[string][]string
+	expectedError      string
+}
+
+var testCasesInputsDependingOn = map[string]inputsDependingOnTest{
+	"cascading option queries": {
+		source: `
+dashboard "d1" {
+  input "account" {
+    sql = "select 'a' as label, 'a' as value"
+  }
+  input "region" {
+    sql  = "select $1 as label, $1 as value"
+    args = [self.input.account.value]
+  }
+}`,
+		expectedDependents: map[string][]string{
+			"input.account": {"input.region"},
+			"input.region":  nil,
+		},
+	},
+	"cascading option queries via with": {
+		source: `
+dashboard "d1" {
+  with "regions" {
+    sql  = "select $1 as name"
+    args = [self.input.account.value]
+  }
+  input "account" {
+    sql = "select 'a' as label, 'a' as value"
+  }
+  input "region" {
+    sql  = "select $1 as label, $1 as value"
+    args = [with.regions.rows[0].name]
+  }
+}`,
+		expectedDependents: map[string][]string{
+			"input.account": {"input.region"},
+			"input.region":  nil,
+		},
+	},
+	"cycle between option queries": {
+		source: `
+dashboard "d1" {
+  input "account" {
+    sql  = "select $1 as label, $1 as value"
+    args = [self.input.region.value]
+  }
+  input "region" {
+    sql  = "select $1 as label, $1 as value"
+    args = [self.input.account.value]
+  }
+}`,
+		expectedError: "Failed to resolve input dependency order for dashboard 'local.dashboard.d1'",
+	},
+}
+
+func TestDashboardInputsDependingOn(t *testing.T) {
+	for name, test := range testCasesInputsDependingOn {
+		mod, res := parseTestMod(t, test.source)
+		if !checkDecodeError(t, name, res.Error, test.expectedError) {
+			continue
+		}
+		dashboard := mod.ResourceMaps.Dashboards["local.dashboard.d1"]
+		if dashboard == nil {
+			t.Errorf("Test %s FAILED. Dashboard not found", name)
+			continue
+		}
+		for inputName, expected := range test.expectedDependents {
+			if dependents := dashboard.GetInputsDependingOn(inputName); !reflect.DeepEqual(dependents, expected) {
+				t.Errorf("Test %s FAILED. Expected inputs depending on %s to be %v, got %v", name, inputName, expected, dependents)
+			}
+		}
+	}
+}
+
+type runtimeDependencyProvidersTest struct {
+	source   string
+	expected []string
+}
+
+var testCasesRuntimeDependencyProviders = map[string]runtimeDependencyProvidersTest{
+	"nested providers": {
+		source: `
+dashboard "d1" {
+  input "i1" {
+    sql = "select 'a' as label, 'a' as value"
+  }
+  text {
+    value = "some text"
+  }
+  container {
+    chart "c1" {
+      sql  = "select $1"
+      args = [self.input.i1.value]
+    }
+  }
+}`,
+		expected: []string{"local.chart.c1", "local.input.i1"},
+	},
+	"no providers": {
+		source: `
+dashboard "d1" {
+  text {
+    value = "some text"
+  }
+}`,
+		expected: []string{},
+	},
+}
+
+func TestDashboardRuntimeDependencyProviders(t *testing.T) {
+	for name, test := range testCasesRuntimeDependencyProviders {
+		mod, res := parseTestMod(t, test.source)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			continue
+		}
+		dashboard := mod.ResourceMaps.Dashboards["local.dashboard.d1"]
+		if dashboard == nil {
+			t.Errorf("Test %s FAILED. Dashboard not found", name)
+			continue
+		}
+		providers := dashboard.RuntimeDependencyProviders()
+		names := []string{}
+		for _, p := range providers {
+			names = append(names, p.Name())
+		}
+		sort.Strings(names)
+		if !reflect.DeepEqual(names, test.expected) {
+			t.Errorf("Test %s FAILED. Expected providers %v, got %v", name, test.expected, names)
+		}
+	}
+}
+
+type sharedQueryArgsTest struct {
+	source   string
+	expected map[string][]any
+}
+
+var testCasesSharedQueryArgs = map[string]sharedQueryArgsTest{
+	"named args": {
+		source: `
+query "q1" {
+  sql = "select $1"
+  param "region" {
+    default = "us-east-1"
+  }
+}
+dashboard "d1" {
+  chart "c1" {
+    query = query.q1
+    args  = {
+      region = "eu-west-1"
+    }
+  }
+  chart "c2" {
+    query = query.q1
+    args  = {
+      region = "ap-south-1"
+    }
+  }
+  chart "c3" {
+    query = query.q1
+  }
+}`,
+		expected: map[string][]any{
+			"local.chart.c1": {"eu-west-1"},
+			"local.chart.c2": {"ap-south-1"},
+			"local.chart.c3": {"us-east-1"},
+		},
+	},
+	"positional args": {
+		source: `
+query "q1" {
+  sql = "select $1, $2"
+}
+dashboard "d1" {
+  chart "c1" {
+    query = query.q1
+    args  = ["a", 1]
+  }
+  chart "c2" {
+    query = query.q1
+    args  = ["b", 2]
+  }
+}`,
+		expected: map[string][]any{
+			"local.chart.c1": {"a", float64(1)},
+			"local.chart.c2": {"b", float64(2)},
+		},
+	},
+}
+
+func TestSharedQueryArgs(t *testing.T) {
+	for name, test := range testCasesSharedQueryArgs {
+		mod, res := parseTestMod(t, test.source)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			continue
+		}
+		query := mod.ResourceMaps.Queries["local.query.q1"]
+		if query == nil {
+			t.Errorf("Test %s FAILED. Query not found", name)
+			continue
+		}
+
+		// resolve the args of each chart twice, in a different order, to verify resolving one does not affect another
+		chartNames := maps.Keys(test.expected)
+		sort.Strings(chartNames)
+		for _, chartNames := range [][]string{chartNames, reverseStrings(chartNames)} {
+			for _, chartName := range chartNames {
+				chart := mod.ResourceMaps.DashboardCharts[chartName]
+				if chart == nil {
+					t.Errorf("Test %s FAILED. Chart %s not found", name, chartName)
+					continue
+				}
+				if chart.GetQuery() != query {
+					t.Errorf("Test %s FAILED. Expected chart %s to reference the shared query", name, chartName)
+				}
+				// resolve the args in the same way as Workspace.ResolveQueryFromQueryProvider
+				chartArgs, err := modconfig.MergeArgs(chart, nil)
+				if err != nil {
+					t.Errorf("Test %s FAILED with unexpected error merging args for %s: %v", name, chartName, err)
+					continue
+				}
+				argVals, err := modconfig.ResolveArgs(chart.GetQuery(), chartArgs)
+				if err != nil {
+					t.Errorf("Test %s FAILED with unexpected error resolving args for %s: %v", name, chartName, err)
+					continue
+				}
+				if !reflect.DeepEqual(argVals, test.expected[chartName]) {
+					t.Errorf("Test %s FAILED. Expected args for %s to be %v, got %v", name, chartName, test.expected[chartName], argVals)
+				}
+			}
+		}
+		if query.Args != nil && !query.Args.Empty() {
+			t.Errorf("Test %s FAILED. Expected the shared query args to be unchanged, got %s", name, query.Args)
+		}
+	}
+}
+
+func reverseStrings(s []string) []string {
+	res := make([]string, len(s))
+	for i, v := range s {
+		res[len(s)-1-i] = v
+	}
+	return res
+}
+
+type inputUrlParamTest struct {
+	source        string
+	inputValues   map[string]any
+	urlParams     map[string]string
+	expected      map[string]any
+	expectedError string
+}
+
+var testCasesInputUrlParams = map[string]inputUrlParamTest{
+	"url param overrides default": {
+		source: `
+dashboard "d1" {
+  input "region" {
+    placeholder = "select a region"
+    url_param   = "region"
+  }
+  input "account" {
+    placeholder = "select an account"
+  }
+}`,
+		urlParams: map[string]string{"region": "us-east-1", "account": "123"},
+		expected:  map[string]any{"input.region": "us-east-1"},
+	},
+	"explicit input value takes precedence": {
+		source: `
+dashboard "d1" {
+  input "region" {
+    placeholder = "select a region"
+    url_param   = "region"
+  }
+}`,
+		inputValues: map[string]any{"input.region": "eu-west-1"},
+		urlParams:   map[string]string{"region": "us-east-1"},
+		expected:    map[string]any{"input.region": "eu-west-1"},
+	},
+	"empty url param": {
+		source: `
+dashboard "d1" {
+  input "region" {
+    placeholder = "select a region"
+    url_param   = " "
+  }
+}`,
+		expectedError: "local.input.region has an empty url_param",
+	},
+}
+
+func TestInputUrlParams(t *testing.T) {
+	for name, test := range testCasesInputUrlParams {
+		mod, res := parseTestMod(t, test.source)
+		if !checkDecodeError(t, name, res.Error, test.expectedError) {
+			continue
+		}
+		dashboard := mod.ResourceMaps.Dashboards["local.dashboard.d1"]
+		if dashboard == nil {
+			t.Errorf("Test %s FAILED. Dashboard not found", name)
+			continue
+		}
+		inputValues := dashboard.ResolveUrlParamInputValues(test.inputValues, test.urlParams)
+		if !reflect.DeepEqual(inputValues, test.expected) {
+			t.Errorf("Test %s FAILED. Expected %v, got %v", name, test.expected, inputValues)
+		}
+	}
+}
+
+type controlSqlStatementsTest struct {
+	source        string
+	expectedError string
+}
+
+var testCasesControlSqlStatements = map[string]controlSqlStatementsTest{
+	"multiple statements": {
+		source: `
+control "c1" {
+  sql = <<-EOQ
+    create temp table t as select 'ok' as status;
+    select status, 'reason' as reason, 'r' as resource from t
+  EOQ
+}`,
+	},
+	"no sql or query": {
+		source: `
+control "c1" {
+  title = "c1"
+}`,
+		expectedError: "local.control.c1 does not define a query or SQL",
+	},
+	"empty sql": {
+		source: `
+control "c1" {
+  sql = " "
+}`,
+		expectedError: "local.control.c1 does not define a query or SQL",
+	},
+	"malformed statements": {
+		source: `
+control "c1" {
+  sql = "select 'ok; select 1"
+}`,
+		expectedError: "local.control.c1 has invalid sql",
+	},
+}
+
+func TestDecodeControlSqlStatements(t *testing.T) {
+	for name, test := range testCasesControlSqlStatements {
+		_, res := parseTestMod(t, test.source)
+		if test.expectedError != "" {
+			if res.Error == nil || !strings.Contains(res.Error.Error(), test.expectedError) {
+				t.Errorf("Test %s FAILED. Expected error containing '%s', got %v", name, test.expectedError, res.Error)
+			}
+			continue
+		}
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+		}
+	}
+}
+
+type dashboardTagInheritanceTest struct {
+	source       string
+	expectedTags map[string]map[string]string
+	// map of dashboard name to the expected tags of its panels
+	expectedDashboardTags map[string]map[string]map[string]string
+	expectedError         string
+}
+
+var testCasesDashboardTagInheritance = map[string]dashboardTagInheritanceTest{
+	"inherit tags": {
+		source: `
+dashboard "d1" {
+  inherit_tags = true
+  tags = {
+    service  = "aws"
+    category = "dashboard"
+  }
+  chart "c1" {
+    sql = "select 1"
+    tags = {
+      category = "chart"
+    }
+  }
+  container {
+    table "t1" {
+      sql = "select 1"
+    }
+  }
+}`,
+		expectedTags: map[string]map[string]string{
+			"local.chart.c1": {"service": "aws", "category": "chart"},
+			"local.table.t1": {"service": "aws", "category": "dashboard"},
+		},
+	},
+	"inherit tags from base": {
+		source: `
+dashboard "base_dash" {
+  chart "c1" {
+    sql = "select 1"
+    tags = {
+      category = "chart"
+    }
+  }
+  container {
+    table "t1" {
+      sql = "select 1"
+    }
+  }
+}
+dashboard "d2" {
+  base         = dashboard.base_dash
+  inherit_tags = true
+  tags = {
+    service = "aws"
+  }
+}`,
+		expectedTags: map[string]map[string]string{
+			"local.chart.c1": {"category": "chart"},
+			"local.table.t1": nil,
+		},
+		expectedDashboardTags: map[string]map[string]map[string]string{
+			"local.dashboard.base_dash": {
+				"local.chart.c1": {"category": "chart"},
+				"local.table.t1": nil,
+			},
+			"local.dashboard.d2": {
+				"local.chart.c1": {"service": "aws", "category": "chart"},
+				"local.table.t1": {"service": "aws"},
+			},
+		},
+	},
+	"inherit tags not set": {
+		source: `
+dashboard "d1" {
+  tags = {
+    service = "aws"
+  }
+  chart "c1" {
+    sql = "select 1"
+  }
+  table "t1" {
+    sql = "select 1"
+  }
+}`,
+		expectedTags: map[string]map[string]string{
+			"local.chart.c1": nil,
+			"local.table.t1": nil,
+		},
+	},
+}
+
+func TestDashboardTagInheritance(t *testing.T) {
+	for name, test := range testCasesDashboardTagInheritance {
+		mod, res := parseTestMod(t, test.source)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			continue
+		}
+		for panelName, expectedTags := range test.expectedTags {
+			parsedName, err := modconfig.ParseResourceName(panelName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resource, ok := mod.GetResource(parsedName)
+			if !ok {
+				t.Errorf("Test %s FAILED. Panel %s not found", name, panelName)
+				continue
+			}
+			if tags := resource.GetHclResourceImpl().Tags; !reflect.DeepEqual(tags, expectedTags) {
+				t.Errorf("Test %s FAILED. Expected tags %v for %s, got %v", name, expectedTags, panelName, tags)
+			}
+		}
+		for dashboardName, expectedPanelTags := range test.expectedDashboardTags {
+			dashboard := mod.ResourceMaps.Dashboards[dashboardName]
+			if dashboard == nil {
+				t.Errorf("Test %s FAILED. Dashboard %s not found", name, dashboardName)
+				continue
+			}
+			panelTags := make(map[string]map[string]string)
+			_ = dashboard.WalkResources(func(resource modconfig.HclResource) (bool, error) {
+				if _, ok := resource.(*modconfig.DashboardContainer); !ok {
+					panelTags[resource.Name()] = resource.GetHclResourceImpl().Tags
+				}
+				return true, nil
+			})
+			for panelName, expectedTags := range expectedPanelTags {
+				if tags := panelTags[panelName]; !reflect.DeepEqual(tags, expectedTags) {
+					t.Errorf("Test %s FAILED. Expected tags %v for %s in %s, got %v", name, expectedTags, panelName, dashboardName, tags)
+				}
+			}
+		}
+	}
+}
+
+type benchmarkChildOrderTest struct {
+	source          string
+	expectedOrder   []string
+	expectedWarning string
+}
+
+var testCasesBenchmarkChildOrder = map[string]benchmarkChildOrderTest{
+	"partial ordering": {
+		source: `
+benchmark "b1" {
+  children    = [control.c1, control.c2, control.c3, control.c4]
+  child_order = ["control.c3", "local.control.c2"]
+}
+control "c1" {
+  sql = "select 1"
+}
+control "c2" {
+  sql = "select 1"
+}
+control "c3" {
+  sql = "select 1"
+}
+control "c4" {
+  sql = "select 1"
+}`,
+		expectedOrder: []string{"local.control.c3", "local.control.c2", "local.control.c1", "local.control.c4"},
+	},
+	"unknown child name": {
+		source: `
+benchmark "b1" {
+  children    = [control.c1, control.c2]
+  child_order = ["control.c2", "control.c5"]
+}
+control "c1" {
+  sql = "select 1"
+}
+control "c2" {
+  sql = "select 1"
+}`,
+		expectedOrder:   []string{"local.control.c2", "local.control.c1"},
+		expectedWarning: "child_order contains 'control.c5' which is not a child of the benchmark",
+	},
+	"child order inherited from base": {
+		source: `
+benchmark "b0" {
+  children    = [control.c1, control.c2]
+  child_order = ["control.c3"]
+}
+benchmark "b1" {
+  base     = benchmark.b0
+  children = [control.c1, control.c2, control.c3]
+}
+control "c1" {
+  sql = "select 1"
+}
+control "c2" {
+  sql = "select 1"
+}
+control "c3" {
+  sql = "select 1"
+}`,
+		expectedOrder:   []string{"local.control.c3", "local.control.c1", "local.control.c2"},
+		expectedWarning: "local.benchmark.b0 child_order contains 'control.c3' which is not a child of the benchmark",
+	},
+	"child order with children inherited from base": {
+		source: `
+benchmark "b0" {
+  children = [control.c1, control.c2]
+}
+benchmark "b1" {
+  base        = benchmark.b0
+  child_order = ["control.c2", "control.c5"]
+}
+control "c1" {
+  sql = "select 1"
+}
+control "c2" {
+  sql = "select 1"
+}`,
+		expectedOrder:   []string{"local.control.c2", "local.control.c1"},
+		expectedWarning: "local.benchmark.b1 child_order contains 'control.c5' which is not a child of the benchmark",
+	},
+}
+
+func TestBenchmarkChildOrder(t *testing.T) {
+	for name, test := range testCasesBenchmarkChildOrder {
+		mod, res := parseTestMod(t, test.source)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			continue
+		}
+		if test.expectedWarning != "" && !strings.Contains(strings.Join(res.Warnings, "\n"), test.expectedWarning) {
+			t.Errorf("Test %s FAILED. Expected warning containing '%s', got %v", name, test.expectedWarning, res.Warnings)
+		}
+		benchmark := mod.ResourceMaps.Benchmarks["local.benchmark.b1"]
+		if benchmark == nil {
+			t.Errorf("Test %s FAILED. Benchmark not found", name)
+			continue
+		}
+		var order []string
+		for _, child := range benchmark.GetChildren() {
+			order = append(order, child.Name())
+		}
+		if !reflect.DeepEqual(order, test.expectedOrder) {
+			t.Errorf("Test %s FAILED. Expected order %v, got %v", name, test.expectedOrder, order)
+		}
+		if !reflect.DeepEqual(benchmark.ChildNameStrings, test.expectedOrder) {
+			t.Errorf("Test %s FAILED. Expected child name strings %v, got %v", name, test.expectedOrder, benchmark.ChildNameStrings)
+		}
+		if childNames := benchmark.ChildNames.StringList(); !reflect.DeepEqual(childNames, test.expectedOrder) {
+			t.Errorf("Test %s FAILED. Expected child names %v, got %v", name, test.expectedOrder, childNames)
+		}
+	}
+}
+
+type deprecatedResourceTest struct {
+	source          string
+	expectedMessage string
+	expectedWarning string
+}
+
+var testCasesDeprecatedResources = map[string]deprecatedResourceTest{
+	"deprecated control referenced by benchmark": {
+		source: `
+benchmark "b1" {
+  children = [control.c1]
+}
+control "c1" {
+  sql        = "select 1"
+  deprecated = "use control.c2"
+}`,
+		expectedMessage: "use control.c2",
+		expectedWarning: "local.benchmark.b1 references deprecated resource local.control.c1: use control.c2",
+	},
+	"deprecated control referenced by deprecated benchmark": {
+		source: `
+benchmark "b1" {
+  children   = [control.c1]
+  deprecated = true
+}
+control "c1" {
+  sql        = "select 1"
+  deprecated = true
+}`,
+	},
+}
+
+func TestDeprecatedResources(t *testing.T) {
+	for name, test := range testCasesDeprecatedResources {
+		mod, res := parseTestMod(t, test.source)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			continue
+		}
+		warnings := strings.Join(res.Warnings, "\n")
+		if test.expectedWarning == "" && warnings != "" {
+			t.Errorf("Test %s FAILED. Expected no warnings, got %s", name, warnings)
+		}
+		if test.expectedWarning != "" && !strings.Contains(warnings, test.expectedWarning) {
+			t.Errorf("Test %s FAILED. Expected warning containing '%s', got %s", name, test.expectedWarning, warnings)
+		}
+		control := mod.ResourceMaps.Controls["local.control.c1"]
+		if control == nil || !control.IsDeprecated() {
+			t.Errorf("Test %s FAILED. Expected control to be deprecated", name)
+			continue
+		}
+		if message := control.GetDeprecationMessage(); message != test.expectedMessage {
+			t.Errorf("Test %s FAILED. Expected deprecation message '%s', got '%s'", name, test.expectedMessage, message)
+		}
+	}
+}
+
+type inputHelpTest struct {
+	source              string
+	expectedPlaceholder string
+	expectedHelp        string
+}
+
+var testCasesInputHelp = map[string]inputHelpTest{
+	"placeholder and help": {
+		source: `
+dashboard "d1" {
+  input "i1" {
+    placeholder = "select a region"
+    help        = "the region to report on"
+  }
+}`,
+		expectedPlaceholder: "select a region",
+		expectedHelp:        "the region to report on",
+	},
+	"empty help": {
+		source: `
+dashboard "d1" {
+  input "i1" {
+    placeholder = "select a region"
+    help        = ""
+  }
+}`,
+		expectedPlaceholder: "select a region",
+	},
+	"inherited from base": {
+		source: `
+input "base_input" {
+  placeholder = "select a region"
+  help        = "the region to report on"
+}
+dashboard "d1" {
+  input "i1" {
+    base = input.base_input
+  }
+}`,
+		expectedPlaceholder: "select a region",
+		expectedHelp:        "the region to report on",
+	},
+}
+
+func TestDecodeInputHelp(t *testing.T) {
+	for name, test := range testCasesInputHelp {
+		mod, res := parseTestMod(t, test.source)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			continue
+		}
+		dashboard := mod.ResourceMaps.Dashboards["local.dashboard.d1"]
+		if dashboard == nil {
+			t.Errorf("Test %s FAILED. Dashboard not found", name)
+			continue
+		}
+		input, ok := dashboard.GetInput("input.i1")
+		if !ok {
+			t.Errorf("Test %s FAILED. Input not found", name)
+			continue
+		}
+		if placeholder := typehelpers.SafeString(input.Placeholder); placeholder != test.expectedPlaceholder {
+			t.Errorf("Test %s FAILED. Expected placeholder '%s', got '%s'", name, test.expectedPlaceholder, placeholder)
+		}
+		if help := typehelpers.SafeString(input.Help); help != test.expectedHelp {
+			t.Errorf("Test %s FAILED. Expected help '%s', got '%s'", name, test.expectedHelp, help)
+		}
+	}
+}
+
+type parallelDecodeTest struct {
+	source string
+}
+
+var testCasesParallelDecode = map[string]parallelDecodeTest{
+	"dependent and independent blocks": {
+		source: `
+variable "region" {
+  default = "us-east-1"
+}
+locals {
+  prefix = "select"
+}
+control "c2" {
+  title = query.q1.title
+  query = query.q1
+}
+query "q1" {
+  title = "q1 in ${var.region}"
+  sql   = "${local.prefix} 1"
+}
+query "q2" {
+  sql = "${local.prefix} 2"
+}
+control "c1" {
+  sql = query.q2.sql
+}
+benchmark "b1" {
+  title    = control.c2.title
+  children = [control.c1, control.c2]
+}
+dashboard "d1" {
+  title = query.q1.title
+  input "i1" {
+    sql = query.q2.sql
+  }
+  container {
+    chart {
+      query = query.q1
+      args  = {
+        "region" = self.input.i1.value
+      }
+    }
+    table {
+      sql = "select 3"
+    }
+  }
+  benchmark {
+    base = benchmark.b1
+  }
+}`,
+	},
+}
+
+func TestParallelDecode(t *testing.T) {
+	for name, test := range testCasesParallelDecode {
+		sequentialMod, res := parseTestMod(t, test.source)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error decoding sequentially: %v", name, res.Error)
+			continue
+		}
+		parallelMod, res := parseTestModWithFlags(t, test.source, CreateDefaultMod|ParallelDecode)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error decoding in parallel: %v", name, res.Error)
+			continue
+		}
+
+		expected := summariseModResources(sequentialMod)
+		actual := summariseModResources(parallelMod)
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("Test %s FAILED. Expected %v, got %v", name, expected, actual)
+		}
+	}
+}
+
+// generateLargeModSource generates the source of a mod with the given number of benchmarks, each with 10 controls
+// controls reference queries declared after them, so decoding requires multiple passes
+func generateLargeModSource(benchmarkCount int) string {
+	var sb strings.Builder
+	sb.WriteString(`
+locals {
+  prefix = "select"
+}
+`)
+	for b := 0; b < benchmarkCount; b++ {
+		var children []string
+		for c := 0; c < 10; c++ {
+			name := fmt.Sprintf("c_%d_%d", b, c)
+			children = append(children, "control."+name)
+			fmt.Fprintf(&sb, `
+control "%s" {
+  title = "Control %d.%d"
+  query = query.q_%d
+  tags  = { benchmark = "b_%d" }
+}
+`, name, b, c, b, b)
+		}
+		fmt.Fprintf(&sb, `
+query "q_%d" {
+  title = "Query %d"
+  sql   = "${local.prefix} %d"
+}
+
+benchmark "b_%d" {
+  title    = "Benchmark %d"
+  children = [%s]
+}
+`, b, b, b, b, b, strings.Join(children, ", "))
+	}
+	return sb.String()
+}
+
+func TestIncrementalDecode(t *testing.T) {
+	source := generateLargeModSource(50)
+	mod, res := parseTestMod(t, source)
+	if res.Error != nil {
+		t.Fatalf("unexpected error decoding: %v", res.Error)
+	}
+	expected := summariseModResources(mod)
+	if len(expected) != 50*12+1 {
+		t.Fatalf("Expected %d resources, got %d", 50*12+1, len(expected))
+	}
+
+	for name, flags := range map[string]ParseModFlag{
+		"incremental":          CreateDefaultMod | IncrementalDecode,
+		"incremental parallel": CreateDefaultMod | IncrementalDecode | ParallelDecode,
+	} {
+		incrementalMod, res := parseTestModWithFlags(t, source, flags)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			continue
+		}
+
+		actual := summariseModResources(incrementalMod)
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("Test %s FAILED. Incrementally decoded mod does not match mod decoded from the full file", name)
+		}
+		// the block bodies should have been released
+		for controlName, control := range incrementalMod.ResourceMaps.Controls {
+			if control.Remain != nil ||
+				control.HclResourceRemain != nil ||
+				control.QueryProviderRemain != nil ||
+				control.ModTreeItemRemain != nil ||
+				control.RuntimeDependencyProviderRemain != nil ||
+				control.ResourceWithMetadataBaseRemain != nil {
+				t.Errorf("Test %s FAILED. Expected the body of %s to be released", name, controlName)
+				break
+			}
+		}
+		for benchmarkName, benchmark := range incrementalMod.ResourceMaps.Benchmarks {
+			if benchmark.Remain != nil ||
+				benchmark.HclResourceRemain != nil ||
+				benchmark.ModTreeItemRemain != nil ||
+				benchmark.ResourceWithMetadataBaseRemain != nil {
+				t.Errorf("Test %s FAILED. Expected the body of %s to be released", name, benchmarkName)
+				break
+			}
+		}
+	}
+
+	// resources with 'with' blocks also retain the body in their with provider
+	dashboardMod, res := parseTestModWithFlags(t, `
+graph "g1" {
+  with "w1" {
+    sql = "select 1 as id"
+  }
+  node "n1" {
+    sql  = "select $1 as id"
+    args = [with.w1.rows[0].id]
+  }
+}
+dashboard "d1" {
+  with "w2" {
+    sql = "select 1 as id"
+  }
+  graph {
+    base = graph.g1
+  }
+}`, CreateDefaultMod|IncrementalDecode)
+	if res.Error != nil {
+		t.Fatalf("unexpected error decoding: %v", res.Error)
+	}
+	dashboard := dashboardMod.ResourceMaps.Dashboards["local.dashboard.d1"]
+	if dashboard.Remain != nil ||
+		dashboard.HclResourceRemain != nil ||
+		dashboard.ModTreeItemRemain != nil ||
+		dashboard.WithProviderRemain != nil ||
+		dashboard.ResourceWithMetadataBaseRemain != nil {
+		t.Errorf("Test dashboard FAILED. Expected the body of %s to be released", dashboard.Name())
+	}
+	graph := dashboardMod.ResourceMaps.DashboardGraphs["local.graph.g1"]
+	if graph.Remain != nil ||
+		graph.HclResourceRemain != nil ||
+		graph.QueryProviderRemain != nil ||
+		graph.ModTreeItemRemain != nil ||
+		graph.RuntimeDependencyProviderRemain != nil ||
+		graph.WithProviderRemain != nil ||
+		graph.ResourceWithMetadataBaseRemain != nil {
+		t.Errorf("Test graph FAILED. Expected the body of %s to be released", graph.Name())
+	}
+}
+
+// summariseModResources returns a map of the title, sql and children of every resource in the mod, keyed by name
+func summariseModResources(mod *modconfig.Mod) map[string]string {
+	res := make(map[string]string)
+	mod.ResourceMaps.WalkResources(func(item modconfig.HclResource) (bool, error) {
+		summary := "title: " + typehelpers.SafeString(item.GetTitle())
+		if qp, ok := item.(modconfig.QueryProvider); ok {
+			summary += " sql: " + typehelpers.SafeString(qp.GetSQL())
+		}
+		if treeItem, ok := item.(modconfig.ModTreeItem); ok {
+			for _, child := range treeItem.GetChildren() {
+				summary += " child: " + child.Name()
+			}
+		}
+		res[item.Name()] = summary
+		return true, nil
+	})
+	return res
+}
+
+type conditionalPanelTest struct {
+	source           string
+	expectedChildren []string
+	// the inputs referenced by the condition of chart c1 (if any)
+	expectedConditionInputs []string
+}
+
+var testCasesConditionalPanel = map[string]conditionalPanelTest{
+	"variable conditions": {
+		source: `
+variable "show_chart" {
+  default = false
+}
+dashboard "d1" {
+  chart "c1" {
+    if  = var.show_chart
+    sql = "select 1"
+  }
+  table "t1" {
+    if  = !var.show_chart
+    sql = "select 2"
+  }
+  text "x1" {
+    value = "always shown"
+  }
+}`,
+		expectedChildren: []string{"local.table.t1", "local.text.x1"},
+	},
+	"nested container condition": {
+		source: `
+dashboard "d1" {
+  container {
+    if = false
+    chart "c1" {
+      sql = "select 1"
+    }
+  }
+  container "c2" {
+    table "t1" {
+      if  = 1 > 2
+      sql = "select 2"
+    }
+  }
+}`,
+		expectedChildren: []string{"local.container.c2"},
+	},
+	"input condition evaluated at runtime": {
+		source: `
+dashboard "d1" {
+  input "i1" {
+    sql = "select 1"
+  }
+  chart "c1" {
+    if  = self.input.i1.value == "show"
+    sql = "select 1"
+  }
+}`,
+		expectedChildren:        []string{"local.input.i1", "local.chart.c1"},
+		expectedConditionInputs: []string{"input.i1"},
+	},
+	"variable and input condition evaluated at runtime": {
+		source: `
+variable "show_chart" {
+  default = true
+}
+dashboard "d1" {
+  input "i1" {
+    sql = "select 1"
+  }
+  chart "c1" {
+    if  = var.show_chart && self.input.i1.value == "show"
+    sql = "select 1"
+  }
+}`,
+		expectedChildren:        []string{"local.input.i1", "local.chart.c1"},
+		expectedConditionInputs: []string{"input.i1"},
+	},
+}
+
+func TestConditionalPanels(t *testing.T) {
+	for name, test := range testCasesConditionalPanel {
+		mod, res := parseTestMod(t, test.source)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			continue
+		}
+		dashboard := mod.ResourceMaps.Dashboards["local.dashboard.d1"]
+		if dashboard == nil {
+			t.Errorf("Test %s FAILED. Dashboard not found", name)
+			continue
+		}
+		var children []string
+		for _, child := range dashboard.GetChildren() {
+			children = append(children, child.Name())
+		}
+		if !reflect.DeepEqual(children, test.expectedChildren) {
+			t.Errorf("Test %s FAILED. Expected children %v, got %v", name, test.expectedChildren, children)
+		}
+		if test.expectedConditionInputs == nil {
+			continue
+		}
+		chart := mod.ResourceMaps.DashboardCharts["local.chart.c1"]
+		if chart == nil {
+			t.Errorf("Test %s FAILED. Chart not found", name)
+			continue
+		}
+		if conditionInputs := chart.ConditionInputs(); !reflect.DeepEqual(conditionInputs, test.expectedConditionInputs) {
+			t.Errorf("Test %s FAILED. Expected condition inputs %v, got %v", name, test.expectedConditionInputs, conditionInputs)
+		}
+		// the condition is evaluated at runtime in a child of the parse eval context, so any variables must resolve
+		evalCtx := chart.ConditionEvalContext()
+		inputs := cty.ObjectVal(map[string]cty.Value{"i1": cty.ObjectVal(map[string]cty.Value{"value": cty.StringVal("show")})})
+		evalCtx.Variables = map[string]cty.Value{"self": cty.ObjectVal(map[string]cty.Value{"input": inputs})}
+		if include, diags := chart.EvaluateCondition(evalCtx); diags.HasErrors() || !include {
+			t.Errorf("Test %s FAILED. Expected condition to evaluate true at runtime, got %v: %v", name, include, diags)
+		}
+	}
+}
+
+type controlSeverityTest struct {
+	source             string
+	expectedSeverity   string
+	expectedExpression bool
+	// map of the value of the 'public' column to the expected row severity
+	expectedRowSeverities map[bool]string
+}
+
+var testCasesControlSeverity = map[string]controlSeverityTest{
+	"static severity": {
+		source: `
+control "c1" {
+  sql      = "select 1"
+  severity = "high"
+}`,
+		expectedSeverity: "high",
+	},
+	"row dependent severity": {
+		source: `
+control "c1" {
+  sql      = "select 1"
+  severity = row.public ? "critical" : "high"
+}`,
+		expectedExpression: true,
+		expectedRowSeverities: map[bool]string{
+			true:  "critical",
+			false: "high",
+		},
+	},
+	"row dependent severity referencing variable and function": {
+		source: `
+variable "public_severity" {
+  default = "critical"
+}
+control "c1" {
+  sql      = "select 1"
+  severity = row.public ? var.public_severity : lower("HIGH")
+}`,
+		expectedExpression: true,
+		expectedRowSeverities: map[bool]string{
+			true:  "critical",
+			false: "high",
+		},
+	},
+}
+
+func TestDecodeControlSeverity(t *testing.T) {
+	for name, test := range testCasesControlSeverity {
+		control, err := decodeSingleControl(t, test.source)
+		if !checkDecodeError(t, name, err, "") {
+			continue
+		}
+		if severity := typehelpers.SafeString(control.Severity); severity != test.expectedSeverity {
+			t.Errorf("Test %s FAILED. Expected severity '%s', got '%s'", name, test.expectedSeverity, severity)
+		}
+		if control.HasRowSeverity() != test.expectedExpression {
+			t.Errorf("Test %s FAILED. Expected row severity %v, got %v", name, test.expectedExpression, control.HasRowSeverity())
+		}
+		for public, expectedSeverity := range test.expectedRowSeverities {
+			severity, err := control.EvaluateRowSeverity(map[string]any{"public": public})
+			if err != nil {
+				t.Errorf("Test %s FAILED with unexpected error evaluating row severity: %v", name, err)
+				continue
+			}
+			if severity != expectedSeverity {
+				t.Errorf("Test %s FAILED. Expected row severity '%s' for public=%v, got '%s'", name, expectedSeverity, public, severity)
+			}
+		}
+	}
+}
+
+type controlDependsOnTest struct {
+	source            string
+	expectedDependsOn []string
+	expectedError     string
+}
+
+var testCasesControlDependsOn = map[string]controlDependsOnTest{
+	"depends on control declared later": {
+		source: `
+control "c1" {
+  sql        = "select 1"
+  depends_on = [control.c2]
+}
+control "c2" {
+  sql = "select 1"
+}`,
+		expectedDependsOn: []string{"local.control.c2"},
+	},
+	"depends on query": {
+		source: `
+control "c1" {
+  sql        = "select 1"
+  depends_on = [query.q1]
+}
+query "q1" {
+  sql = "select 1"
+}`,
+		expectedError: "local.control.c1 has invalid depends_on 'local.query.q1'",
+	},
+	"dependency cycle": {
+		source: `
+control "c1" {
+  sql        = "select 1"
+  depends_on = [control.c2]
+}
+control "c2" {
+  sql        = "select 1"
+  depends_on = [control.c1]
+}`,
+		expectedError: "Cycle error",
+	},
+}
+
+func TestDecodeControlDependsOn(t *testing.T) {
+	for name, test := range testCasesControlDependsOn {
+		control, err := decodeSingleControl(t, test.source)
+		if !checkDecodeError(t, name, err, test.expectedError) {
+			continue
+		}
+		if dependsOn := control.DependsOn.StringList(); !reflect.DeepEqual(dependsOn, test.expectedDependsOn) {
+			t.Errorf("Test %s FAILED. Expected depends_on %v, got %v", name, test.expectedDependsOn, dependsOn)
+		}
+	}
+}
+
+type controlOnErrorTest struct {
+	source          string
+	expectedOnError string
+	expectedError   string
+}
+
+var testCasesControlOnError = map[string]controlOnErrorTest{
+	"default": {
+		source: `
+control "c1" {
+  sql = "select 1"
+}`,
+		expectedOnError: "error",
+	},
+	"skip": {
+		source: `
+control "c1" {
+  sql      = "select 1"
+  on_error = "skip"
+}`,
+		expectedOnError: "skip",
+	},
+	"alarm": {
+		source: `
+control "c1" {
+  sql      = "select 1"
+  on_error = "alarm"
+}`,
+		expectedOnError: "alarm",
+	},
+	"invalid": {
+		source: `
+control "c1" {
+  sql      = "select 1"
+  on_error = "ignore"
+}`,
+		expectedError: "invalid on_error value",
+	},
+}
+
+func TestDecodeControlOnError(t *testing.T) {
+	for name, test := range testCasesControlOnError {
+		control, err := decodeSingleControl(t, test.source)
+		if !checkDecodeError(t, name, err, test.expectedError) {
+			continue
+		}
+		if onError := control.GetOnError(); onError != test.expectedOnError {
+			t.Errorf("Test %s FAILED. Expected on_error '%s', got '%s'", name, test.expectedOnError, onError)
+		}
+	}
+}
+
+type paramDefaultTest struct {
+	source          string
+	expectedDefault any
+	expectedError   string
+}
+
+var testCasesParamDefault = map[string]paramDefaultTest{
+	"list default": {
+		source: `
+control "c1" {
+  sql = "select 1"
+  param "p1" {
+    default = ["us-east-1", "eu-west-1"]
+  }
+}`,
+		expectedDefault: []any{"us-east-1", "eu-west-1"},
+	},
+	"map default": {
+		source: `
+control "c1" {
+  sql = "select 1"
+  param "p1" {
+    default = {
+      env   = "prod"
+      owner = "ops"
+    }
+  }
+}`,
+		expectedDefault: map[string]any{"env": "prod", "owner": "ops"},
+	},
+	"nested object default": {
+		source: `
+locals {
+  regions = ["us-east-1"]
+}
+control "c1" {
+  sql = "select 1"
+  param "p1" {
+    default = {
+      regions = local.regions
+      limit   = 10
+    }
+  }
+}`,
+		expectedDefault: map[string]any{"regions": []any{"us-east-1"}, "limit": float64(10)},
+	},
+	"typed list default": {
+		source: `
+control "c1" {
+  sql = "select 1"
+  param "p1" {
+    type    = list(string)
+    default = ["a", 1]
+  }
+}`,
+		expectedDefault: []any{"a", "1"},
+	},
+	"default does not match type": {
+		source: `
+control "c1" {
+  sql = "select 1"
+  param "p1" {
+    type    = map(number)
+    default = {
+      env = "prod"
+    }
+  }
+}`,
+		expectedError: "param.p1 default does not match type map(number)",
+	},
+}
+
+func TestDecodeParamDefault(t *testing.T) {
+	for name, test := range testCasesParamDefault {
+		control, err := decodeSingleControl(t, test.source)
+		if !checkDecodeError(t, name, err, test.expectedError) {
+			continue
+		}
+		if len(control.Params) != 1 {
+			t.Errorf("Test %s FAILED. Expected 1 param, got %d", name, len(control.Params))
+			continue
+		}
+		defaultValue, err := control.Params[0].GetDefault()
+		if err != nil {
+			t.Errorf("Test %s FAILED with unexpected error getting default: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(defaultValue, test.expectedDefault) {
+			t.Errorf("Test %s FAILED. Expected default %v, got %v", name, test.expectedDefault, defaultValue)
+		}
+	}
+}
+
+type benchmarkTagTitleTest struct {
+	source        string
+	expectedTitle string
+	expectedError string
+}
+
+var testCasesBenchmarkTagTitle = map[string]benchmarkTagTitleTest{
+	"title references tag": {
+		source: `
+benchmark "b1" {
+  title    = "CIS ${self.tags.section} - Checks"
+  tags     = {
+    section = "1.1"
+  }
+  children = []
+}`,
+		expectedTitle: "CIS 1.1 - Checks",
+	},
+	"tags reference local": {
+		source: `
+locals {
+  section = "2.3"
+}
+benchmark "b1" {
+  title    = "CIS ${self.tags.section} - Checks"
+  tags     = {
+    section = local.section
+  }
+  children = []
+}`,
+		expectedTitle: "CIS 2.3 - Checks",
+	},
+	"title references missing tag": {
+		source: `
+benchmark "b1" {
+  title    = "CIS ${self.tags.section} - Checks"
+  tags     = {
+    service = "s3"
+  }
+  children = []
+}`,
+		expectedError: "title references a tag which is not defined",
+	},
+}
+
+func TestBenchmarkTagTitle(t *testing.T) {
+	for name, test := range testCasesBenchmarkTagTitle {
+		mod, res := parseTestMod(t, test.source)
+		if !checkDecodeError(t, name, res.Error, test.expectedError) {
+			continue
+		}
+		benchmark := mod.ResourceMaps.Benchmarks["local.benchmark.b1"]
+		if benchmark == nil {
+			t.Errorf("Test %s FAILED. Benchmark not found", name)
+			continue
+		}
+		if title := typehelpers.SafeString(benchmark.Title); title != test.expectedTitle {
+			t.Errorf("Test %s FAILED. Expected title '%s', got '%s'", name, test.expectedTitle, title)
+		}
+	}
+}
+
+func TestSortDiagnostics(t *testing.T) {
+	subject := func(filename string, line, column int) *hcl.Range {
+		return &hcl.Range{Filename: filename, Start: hcl.Pos{Line: line, Column: column}}
+	}
+	expected := hcl.Diagnostics{
+		{Severity: hcl.DiagError, Summary: "a.sp 1:1 error", Subject: subject("a.sp", 1, 1)},
+		{Severity: hcl.DiagWarning, Summary: "a.sp 1:1 warning 1", Subject: subject("a.sp", 1, 1)},
+		{Severity: hcl.DiagWarning, Summary: "a.sp 1:1 warning 2", Subject: subject("a.sp", 1, 1)},
+		{Severity: hcl.DiagWarning, Summary: "a.sp 1:5 warning", Subject: subject("a.sp", 1, 5)},
+		{Severity: hcl.DiagError, Summary: "a.sp 3:1 error", Subject: subject("a.sp", 3, 1)},
+		{Severity: hcl.DiagWarning, Summary: "b.sp 2:1 warning", Subject: subject("b.sp", 2, 1)},
+		{Severity: hcl.DiagError, Summary: "no subject error"},
+		{Severity: hcl.DiagWarning, Summary: "no subject warning"},
+	}
+
+	// sort several permutations of the diagnostics - the result must always be the same
+	// (the 2 warnings at a.sp 1:1 are always in the same relative order so the stable sort preserves their order)
+	permutations := []hcl.Diagnostics{
+		{expected[7], expected[6], expected[5], expected[4], expected[3], expected[1], expected[2], expected[0]},
+		{expected[1], expected[5], expected[7], expected[2], expected[0], expected[4], expected[6], expected[3]},
+		{expected[6], expected[1], expected[3], expected[0], expected[7], expected[2], expected[5], expected[4]},
+	}
+	for i, diags := range permutations {
+		sortDiagnostics(diags)
+		if !reflect.DeepEqual(diags, expected) {
+			var summaries []string
+			for _, d := range diags {
+				summaries = append(summaries, d.Summary)
+			}
+			t.Errorf("Permutation %d FAILED. Got order %v", i, summaries)
+		}
+	}
+}
+
+type titleResolverTest struct {
+	source         string
+	titles         map[string]string
+	expectedTitles map[string]string
+}
+
+var testCasesTitleResolver = map[string]titleResolverTest{
+	"resolved and fallback titles": {
+		source: `
+control "c1" {
+  title = "hcl c1"
+  sql   = "select 1"
+}
+control "c2" {
+  title = "hcl c2"
+  sql   = "select 2"
+}
+benchmark "b1" {
+  children = [control.c1, control.c2]
+}
+query "q1" {
+  title = "hcl q1"
+  sql   = "select 3"
+}`,
+		titles: map[string]string{
+			"local.control.c1":   "catalog c1",
+			"local.benchmark.b1": "catalog b1",
+			// queries do not support title resolution
+			"local.query.q1": "catalog q1",
+		},
+		expectedTitles: map[string]string{
+			"local.control.c1":   "catalog c1",
+			"local.control.c2":   "hcl c2",
+			"local.benchmark.b1": "catalog b1",
+			"local.query.q1":     "hcl q1",
+		},
+	},
+}
+
+func TestTitleResolver(t *testing.T) {
+	for name, test := range testCasesTitleResolver {
+		fileData := map[string][]byte{testModPath + "/test.sp": []byte(test.source)}
+		parseCtx := newTestModParseContext(t)
+		parseCtx.TitleResolver = func(resourceName string) (string, bool) {
+			title, ok := test.titles[resourceName]
+			return title, ok
+		}
+		mod, res := ParseMod(context.Background(), fileData, nil, parseCtx)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			continue
+		}
+		for resourceName, expectedTitle := range test.expectedTitles {
+			parsedName, err := modconfig.ParseResourceName(resourceName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resource, ok := mod.GetResource(parsedName)
+			if !ok {
+				t.Errorf("Test %s FAILED. Resource %s not found", name, resourceName)
+				continue
+			}
+			if title := typehelpers.SafeString(resource.GetTitle()); title != expectedTitle {
+				t.Errorf("Test %s FAILED. Expected title of %s to be '%s', got '%s'", name, resourceName, expectedTitle, title)
+			}
+		}
+	}
+}
+
+type crossModBaseTest struct {
+	source           string
+	expectedSeverity string
+	expectedError    string
+}
+
+var testCasesCrossModBase = map[string]crossModBaseTest{
+	"base in dependency mod": {
+		source: `
+control "c1" {
+  base = dep.control.dep_c1
+}`,
+		expectedSeverity: "high",
+	},
+	"base not found": {
+		source: `
+control "c1" {
+  base = dep.control.missing
+}`,
+		expectedError: "base not found: 'dep.control.missing'",
+	},
+	"base mod not loaded": {
+		source: `
+control "c1" {
+  base = other.control.dep_c1
+}`,
+		expectedError: "base mod not loaded: 'other'",
+	},
+}
+
+func TestCrossModBase(t *testing.T) {
+	depMod := parseTestDependencyMod(t, "dep", `
+control "dep_c1" {
+  sql      = "select 1"
+  severity = "high"
+}`)
+
+	for name, test := range testCasesCrossModBase {
+		parseCtx := newTestModParseContext(t)
+		parseCtx.AddLoadedDependencyMod(depMod)
+		if diags := parseCtx.AddModResources(depMod); diags.HasErrors() {
+			t.Fatalf("failed to add dependency mod resources: %s", diags.Error())
+		}
+		fileData := map[string][]byte{testModPath + "/test.sp": []byte(test.source)}
+		mod, res := ParseMod(context.Background(), fileData, nil, parseCtx)
+
+		if !checkDecodeError(t, name, res.Error, test.expectedError) {
+			continue
+		}
+		control := mod.ResourceMaps.Controls["local.control.c1"]
+		if control == nil {
+			t.Errorf("Test %s FAILED. Control not found", name)
+			continue
+		}
+		if severity := typehelpers.SafeString(control.Severity); severity != test.expectedSeverity {
+			t.Errorf("Test %s FAILED. Expected severity '%s' inherited from base, got '%s'", name, test.expectedSeverity, severity)
+		}
+	}
+}
+
+type crossModBenchmarkChildTest struct {
+	source string
+	// if set, the dependency mod is loaded but is not declared in the require block of the current mod
+	notRequired      bool
+	expectedChildren []string
+	expectedError    string
+}
+
+var testCasesCrossModBenchmarkChild = map[string]crossModBenchmarkChildTest{
+	"children in dependency mod": {
+		source: `
+benchmark "b1" {
+  children = [control.c1, dep.control.dep_c1, dep.benchmark.dep_b1]
+}
+control "c1" {
+  sql = "select 1"
+}`,
+		expectedChildren: []string{"local.control.c1", "dep.control.dep_c1", "dep.benchmark.dep_b1"},
+	},
+	"child not found in dependency mod": {
+		source: `
+benchmark "b1" {
+  children = [dep.control.missing]
+}`,
+		expectedError: "child not found: 'dep.control.missing'",
+	},
+	"child mod not loaded": {
+		source: `
+benchmark "b1" {
+  children = [other.control.dep_c1]
+}`,
+		expectedError: "child mod not loaded: 'other'",
+	},
+	"child mod loaded but not required": {
+		source: `
+benchmark "b1" {
+  children = [dep.control.dep_c1]
+}`,
+		notRequired:   true,
+		expectedError: "Could not resolve mod for child dep.control.dep_c1",
+	},
+}
+
+func TestCrossModBenchmarkChild(t *testing.T) {
+	depMod := parseTestDependencyMod(t, "dep", `
+benchmark "dep_b1" {
+  children = [control.dep_c2]
+}
+control "dep_c1" {
+  sql = "select 1"
+}
+control "dep_c2" {
+  sql = "select 2"
+}`)
+
+	for name, test := range testCasesCrossModBenchmarkChild {
+		parseCtx := newTestModParseContext(t)
+		parseCtx.AddLoadedDependencyMod(depMod)
+		if !test.notRequired {
+			requiredMod, err := modconfig.NewModVersionConstraint(depMod.DependencyName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			parseCtx.CurrentMod.Require.AddModDependencies(map[string]*modconfig.ModVersionConstraint{requiredMod.Name: requiredMod})
+		}
+		if diags := parseCtx.AddModResources(depMod); diags.HasErrors() {
+			t.Fatalf("failed to add dependency mod resources: %s", diags.Error())
+		}
+		fileData := map[string][]byte{testModPath + "/test.sp": []byte(test.source)}
+		mod, res := ParseMod(context.Background(), fileData, nil, parseCtx)
+
+		if !checkDecodeError(t, name, res.Error, test.expectedError) {
+			continue
+		}
+		benchmark := mod.ResourceMaps.Benchmarks["local.benchmark.b1"]
+		if benchmark == nil {
+			t.Errorf("Test %s FAILED. Benchmark not found", name)
+			continue
+		}
+		if !reflect.DeepEqual(benchmark.ChildNameStrings, test.expectedChildren) {
+			t.Errorf("Test %s FAILED. Expected children %v, got %v", name, test.expectedChildren, benchmark.ChildNameStrings)
+			continue
+		}
+		// the children in the dependency mod must be the dependency mod resources
+		for _, child := range benchmark.GetChildren() {
+			if strings.HasPrefix(child.Name(), "dep.") && child.GetMod() != depMod {
+				t.Errorf("Test %s FAILED. Expected %s to belong to the dependency mod", name, child.Name())
+			}
+		}
+	}
+}
+
+type controlRemediationTest struct {
+	source        string
+	expected      *modconfig.ControlRemediation
+	expectedError string
+}
+
+var testCasesControlRemediation = map[string]controlRemediationTest{
+	"remediation block": {
+		source: `
+control "c1" {
+  sql = "select 1"
+  remediation {
+    description = "Enable versioning on the bucket"
+    commands    = ["aws s3api put-bucket-versioning --bucket foo --versioning-configuration Status=Enabled"]
+  }
+}`,
+		expected: &modconfig.ControlRemediation{
+			Description: utils.ToStringPointer("Enable versioning on the bucket"),
+			Commands:    []string{"aws s3api put-bucket-versioning --bucket foo --versioning-configuration Status=Enabled"},
+		},
+	},
+	"no remediation": {
+		source: `
+control "c1" {
+  sql = "select 1"
+}`,
+	},
+	"inherited from base": {
+		source: `
+control "base" {
+  sql = "select 1"
+  remediation {
+    description = "Rotate the key"
+    commands    = ["rotate"]
+  }
+}
+control "c1" {
+  base = control.base
+  remediation {
+    description = "Rotate the access key"
+  }
+}`,
+		expected: &modconfig.ControlRemediation{
+			Description: utils.ToStringPointer("Rotate the access key"),
+			Commands:    []string{"rotate"},
+		},
+	},
+	"commands not a string list": {
+		source: `
+control "c1" {
+  sql = "select 1"
+  remediation {
+    description = "Enable versioning"
+    commands    = [{ cmd = "foo" }]
+  }
+}`,
+		expectedError: "string required",
+	},
+}
+
+func TestDecodeControlRemediation(t *testing.T) {
+	for name, test := range testCasesControlRemediation {
+		control, err := decodeSingleControl(t, test.source)
+		if !checkDecodeError(t, name, err, test.expectedError) {
+			continue
+		}
+		if !reflect.DeepEqual(control.Remediation, test.expected) {
+			t.Errorf("Test %s FAILED. Expected remediation %v, got %v", name, test.expected, control.Remediation)
+		}
+	}
+}
+
+type emptyBenchmarkTest struct {
+	source           string
+	expectedWarnings []string
+}
+
+var testCasesEmptyBenchmark = map[string]emptyBenchmarkTest{
+	"empty leaf benchmark": {
+		source: `
+benchmark "b1" {
+  children = [control.c1, benchmark.b2]
+}
+benchmark "b2" {
+  children = []
+}
+control "c1" {
+  sql = "select 1"
+}`,
+		expectedWarnings: []string{"local.benchmark.b2 contains no controls"},
+	},
+	"nested benchmarks with no controls": {
+		source: `
+benchmark "b1" {
+  children = [benchmark.b2]
+}
+benchmark "b2" {
+  children = []
+}`,
+		expectedWarnings: []string{"local.benchmark.b1 contains no controls", "local.benchmark.b2 contains no controls"},
+	},
+	"all benchmarks contain controls": {
+		source: `
+benchmark "b1" {
+  children = [benchmark.b2]
+}
+benchmark "b2" {
+  children = [control.c1]
+}
+control "c1" {
+  sql = "select 1"
+}`,
+	},
+}
+
+func TestEmptyBenchmarkWarnings(t *testing.T) {
+	for name, test := range testCasesEmptyBenchmark {
+		_, res := parseTestMod(t, test.source)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			continue
+		}
+		var warnings []string
+		for _, w := range res.Warnings {
+			if strings.Contains(w, "contains no controls") {
+				warnings = append(warnings, w)
+			}
+		}
+		if len(warnings) != len(test.expectedWarnings) {
+			t.Errorf("Test %s FAILED. Expected %d warnings, got %d: %v", name, len(test.expectedWarnings), len(warnings), warnings)
+			continue
+		}
+		for i, expected := range test.expectedWarnings {
+			if !strings.Contains(warnings[i], expected) {
+				t.Errorf("Test %s FAILED. Expected warning containing '%s', got '%s'", name, expected, warnings[i])
+			}
+		}
+	}
+}
+
+type duplicateControlSqlTest struct {
+	source           string
+	expectedWarnings []string
+}
+
+var testCasesDuplicateControlSql = map[string]duplicateControlSqlTest{
+	"distinct sql": {
+		source: `
+control "c1" {
+  sql = "select 1"
+}
+control "c2" {
+  sql = "select 2"
+}`,
+	},
+	"identical sql": {
+		source: `
+control "c1" {
+  sql = "select 'ok' as status, 'r' as resource, 'reason' as reason"
+}
+control "c3" {
+  sql = "select 'ok' as status, 'r' as resource, 'reason' as reason"
+}
+control "c2" {
+  sql = <<-EOQ
+    select 'ok' as status,
+      'r' as resource,
+      'reason' as reason;
+  EOQ
+}`,
+		expectedWarnings: []string{
+			"local.control.c3 has the same sql as local.control.c1 (possible duplication)",
+			"local.control.c2 has the same sql as local.control.c1 (possible duplication)",
+		},
+	},
+	"whitespace in string literal": {
+		source: `
+control "c1" {
+  sql = "select 'a b' as reason"
+}
+control "c2" {
+  sql = "select 'a  b' as reason"
+}`,
+	},
+	"same sql with different args": {
+		source: `
+control "c1" {
+  sql  = "select $1"
+  args = ["a"]
+}
+control "c2" {
+  sql  = "select $1"
+  args = ["b"]
+}`,
+	},
+	"sql inherited from base": {
+		source: `
+control "c1" {
+  sql = "select 1"
+}
+control "c2" {
+  base  = control.c1
+  title = "C2"
+}`,
+	},
+	"shared named query": {
+		source: `
+query "q1" {
+  sql = "select 1"
+}
+control "c1" {
+  query = query.q1
+}
+control "c2" {
+  query = query.q1
+}`,
+	},
+}
+
+func TestDuplicateControlSqlWarnings(t *testing.T) {
+	for name, test := range testCasesDuplicateControlSql {
+		_, res := parseTestMod(t, test.source)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			continue
+		}
+		var warnings []string
+		for _, w := range res.Warnings {
+			if strings.Contains(w, "possible duplication") {
+				warnings = append(warnings, w)
+			}
+		}
+		if len(warnings) != len(test.expectedWarnings) {
+			t.Errorf("Test %s FAILED. Expected %d warnings, got %d: %v", name, len(test.expectedWarnings), len(warnings), warnings)
+			continue
+		}
+		for i, expected := range test.expectedWarnings {
+			if !strings.Contains(warnings[i], expected) {
+				t.Errorf("Test %s FAILED. Expected warning containing '%s', got '%s'", name, expected, warnings[i])
+			}
+		}
+	}
+}
+
+type dashboardLabelsTest struct {
+	source              string
+	locale              string
+	expectedTitle       string
+	expectedDescription string
+	expectedError       string
+}
+
+var testCasesDashboardLabels = map[string]dashboardLabelsTest{
+	"non-default locale": {
+		source: `
+dashboard "d1" {
+  title       = "Cost Report"
+  description = "Monthly cost"
+  labels "fr" {
+    title       = "Rapport de coûts"
+    description = "Coût mensuel"
+  }
+  labels "de" {
+    title = "Kostenbericht"
+  }
+}`,
+		locale:              "fr",
+		expectedTitle:       "Rapport de coûts",
+		expectedDescription: "Coût mensuel",
+	},
+	"locale with title only falls back to default description": {
+		source: `
+dashboard "d1" {
+  title       = "Cost Report"
+  description = "Monthly cost"
+  labels "de" {
+    title = "Kostenbericht"
+  }
+}`,
+		locale:              "de",
+		expectedTitle:       "Kostenbericht",
+		expectedDescription: "Monthly cost",
+	},
+	"default locale": {
+		source: `
+dashboard "d1" {
+  title       = "Cost Report"
+  description = "Monthly cost"
+  labels "fr" {
+    title = "Rapport de coûts"
+  }
+}`,
+		expectedTitle:       "Cost Report",
+		expectedDescription: "Monthly cost",
+	},
+	"duplicate locale": {
+		source: `
+dashboard "d1" {
+  title = "Cost Report"
+  labels "fr" {
+    title = "Rapport de coûts"
+  }
+  labels "fr" {
+    title = "Rapport"
+  }
+}`,
+		expectedError: "duplicate labels for locale 'fr'",
+	},
+}
+
+func TestDashboardLabels(t *testing.T) {
+	for name, test := range testCasesDashboardLabels {
+		mod, res := parseTestMod(t, test.source)
+		if !checkDecodeError(t, name, res.Error, test.expectedError) {
+			continue
+		}
+		dashboard := mod.ResourceMaps.Dashboards["local.dashboard.d1"]
+		if dashboard == nil {
+			t.Errorf("Test %s FAILED. Dashboard not found", name)
+			continue
+		}
+		if title := dashboard.GetLocalisedTitle(test.locale); title != test.expectedTitle {
+			t.Errorf("Test %s FAILED. Expected title '%s', got '%s'", name, test.expectedTitle, title)
+		}
+		if description := dashboard.GetLocalisedDescription(test.locale); description != test.expectedDescription {
+			t.Errorf("Test %s FAILED. Expected description '%s', got '%s'", name, test.expectedDescription, description)
+		}
+	}
+}
+
+type inputTypeTest struct {
+	source           string
+	expectedOptions  []string
+	expectedFreeText bool
+	expectedError    string
+}
+
+var testCasesInputType = map[string]inputTypeTest{
+	"combo with options": {
+		source: `
+dashboard "d1" {
+  input "i1" {
+    type = "combo"
+    option "us-east-1" {}
+    option "eu-west-1" {}
+  }
+}`,
+		expectedOptions:  []string{"us-east-1", "eu-west-1"},
+		expectedFreeText: true,
+	},
+	"combo without options or query": {
+		source: `
+dashboard "d1" {
+  input "i1" {
+    type = "combo"
+  }
+}`,
+		expectedFreeText: true,
+	},
+	"select": {
+		source: `
+dashboard "d1" {
+  input "i1" {
+    type = "select"
+    option "us-east-1" {}
+  }
+}`,
+		expectedOptions: []string{"us-east-1"},
+	},
+	"invalid type": {
+		source: `
+dashboard "d1" {
+  input "i1" {
+    type = "dropdown"
+    option "us-east-1" {}
+  }
+}`,
+		expectedError: "invalid type 'dropdown'",
+	},
+}
+
+func TestDecodeInputType(t *testing.T) {
+	for name, test := range testCasesInputType {
+		mod, res := parseTestMod(t, test.source)
+		if !checkDecodeError(t, name, res.Error, test.expectedError) {
+			continue
+		}
+		dashboard := mod.ResourceMaps.Dashboards["local.dashboard.d1"]
+		if dashboard == nil {
+			t.Errorf("Test %s FAILED. Dashboard not found", name)
+			continue
+		}
+		input, ok := dashboard.GetInput("input.i1")
+		if !ok {
+			t.Errorf("Test %s FAILED. Input not found", name)
+			continue
+		}
+		var options []string
+		for _, o := range input.Options {
+			options = append(options, o.Name)
+		}
+		if !reflect.DeepEqual(options, test.expectedOptions) {
+			t.Errorf("Test %s FAILED. Expected options %v, got %v", name, test.expectedOptions, options)
+		}
+		if freeText := input.AllowsFreeText(); freeText != test.expectedFreeText {
+			t.Errorf("Test %s FAILED. Expected free text %v, got %v", name, test.expectedFreeText, freeText)
+		}
+	}
+}
+
+type controlTagsTest struct {
+	source        string
+	expectedTags  map[string]string
+	expectedError string
+}
+
+var testCasesControlTags = map[string]controlTagsTest{
+	"variable reference": {
+		source: `
+variable "env" {
+  default = "prod"
+}
+control "c1" {
+  sql  = "select 1"
+  tags = {
+    environment = var.env
+    owner       = "ops"
+  }
+}`,
+		expectedTags: map[string]string{"environment": "prod", "owner": "ops"},
+	},
+	"local reference": {
+		source: `
+locals {
+  env = "dev"
+}
+control "c1" {
+  sql  = "select 1"
+  tags = { environment = local.env }
+}`,
+		expectedTags: map[string]string{"environment": "dev"},
+	},
+	"reference to resource decoded later": {
+		source: `
+control "c1" {
+  sql  = "select 1"
+  tags = { environment = control.c2.title }
+}
+control "c2" {
+  title = "staging"
+  sql   = "select 1"
+}`,
+		expectedTags: map[string]string{"environment": "staging"},
+	},
+	"non-string variable": {
+		source: `
+variable "env" {
+  type    = object({ name = string })
+  default = { name = "prod" }
+}
+control "c1" {
+  sql  = "select 1"
+  tags = { environment = var.env }
+}`,
+		expectedError: `element "environment": string required`,
+	},
+}
+
+func TestDecodeControlTags(t *testing.T) {
+	for name, test := range testCasesControlTags {
+		control, err := decodeSingleControl(t, test.source)
+		if !checkDecodeError(t, name, err, test.expectedError) {
+			continue
+		}
+		if !reflect.DeepEqual(control.Tags, test.expectedTags) {
+			t.Errorf("Test %s FAILED. Expected tags %v, got %v", name, test.expectedTags, control.Tags)
+		}
+	}
+}
+
+type dependencyGraphTest struct {
+	source   string
+	expected map[string][]string
+}
+
+var testCasesDependencyGraph = map[string]dependencyGraphTest{
+	"dependencies declared after dependents": {
+		source: `
+benchmark "b1" {
+  children = [control.c1, control.c2]
+}
+control "c1" {
+  query = query.q1
+}
+control "c2" {
+  title = control.c1.title
+  query = query.q1
+}
+query "q1" {
+  title = "q1"
+  sql   = "select 1"
+}`,
+		expected: map[string][]string{
+			"benchmark.b1": {"control.c1", "control.c2"},
+			"control.c1":   {"query.q1"},
+			"control.c2":   {"control.c1", "query.q1"},
+		},
+	},
+	"no dependencies": {
+		source: `
+query "q1" {
+  sql = "select 1"
+}
+control "c1" {
+  query = query.q1
+}`,
+		expected: map[string][]string{},
+	},
+}
+
+func TestDependencyGraph(t *testing.T) {
+	for name, test := range testCasesDependencyGraph {
+		parseCtx := newTestModParseContext(t)
+		fileData := map[string][]byte{testModPath + "/test.sp": []byte(test.source)}
+		_, res := ParseMod(context.Background(), fileData, nil, parseCtx)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			continue
+		}
+		if graph := parseCtx.DependencyGraph(); !reflect.DeepEqual(graph, test.expected) {
+			t.Errorf("Test %s FAILED. Expected %v, got %v", name, test.expected, graph)
+		}
+	}
+}
+
+type benchmarkWeightTest struct {
+	source         string
+	expectedWeight float64
+	expectedError  string
+}
+
+var testCasesBenchmarkWeight = map[string]benchmarkWeightTest{
+	"weight": {
+		source: `
+benchmark "b1" {
+  weight   = 2.5
+  children = [control.c1]
+}
+control "c1" {
+  sql = "select 1"
+}`,
+		expectedWeight: 2.5,
+	},
+	"default weight": {
+		source: `
+benchmark "b1" {
+  children = [control.c1]
+}
+control "c1" {
+  sql = "select 1"
+}`,
+		expectedWeight: 1,
+	},
+	"negative weight": {
+		source: `
+benchmark "b1" {
+  weight   = -1
+  children = [control.c1]
+}
+control "c1" {
+  sql = "select 1"
+}`,
+		expectedError: "weight must not be negative",
+	},
+}
+
+func TestDecodeBenchmarkWeight(t *testing.T) {
+	for name, test := range testCasesBenchmarkWeight {
+		mod, res := parseTestMod(t, test.source)
+		if !checkDecodeError(t, name, res.Error, test.expectedError) {
+			continue
+		}
+		benchmark := mod.ResourceMaps.Benchmarks["local.benchmark.b1"]
+		if benchmark == nil {
+			t.Errorf("Test %s FAILED. Benchmark not found", name)
+			continue
+		}
+		if weight := benchmark.GetWeight(); weight != test.expectedWeight {
+			t.Errorf("Test %s FAILED. Expected weight %v, got %v", name, test.expectedWeight, weight)
+		}
+	}
+}
+
+type dashboardAutoRefreshTest struct {
+	source              string
+	expectedAutoRefresh time.Duration
+	expectedError       string
+}
+
+var testCasesDashboardAutoRefresh = map[string]dashboardAutoRefreshTest{
+	"valid interval": {
+		source: `
+dashboard "d1" {
+  auto_refresh = "5m"
+}`,
+		expectedAutoRefresh: 5 * time.Minute,
+	},
+	"no auto refresh": {
+		source: `
+dashboard "d1" {
+}`,
+	},
+	"invalid interval": {
+		source: `
+dashboard "d1" {
+  auto_refresh = "often"
+}`,
+		expectedError: "invalid auto_refresh 'often'",
+	},
+	"negative interval": {
+		source: `
+dashboard "d1" {
+  auto_refresh = "-30s"
+}`,
+		expectedError: "invalid auto_refresh '-30s'",
+	},
+}
+
+func TestDashboardAutoRefresh(t *testing.T) {
+	for name, test := range testCasesDashboardAutoRefresh {
+		mod, res := parseTestMod(t, test.source)
+		if !checkDecodeError(t, name, res.Error, test.expectedError) {
+			continue
+		}
+		dashboard := mod.ResourceMaps.Dashboards["local.dashboard.d1"]
+		if dashboard == nil {
+			t.Errorf("Test %s FAILED. Dashboard not found", name)
+			continue
+		}
+		if autoRefresh := dashboard.GetAutoRefresh(); autoRefresh != test.expectedAutoRefresh {
+			t.Errorf("Test %s FAILED. Expected auto refresh %s, got %s", name, test.expectedAutoRefresh, autoRefresh)
+		}
+	}
+}
+
+type controlTitleFromQueryTest struct {
+	source        string
+	expectedTitle string
+}
+
+var testCasesControlTitleFromQuery = map[string]controlTitleFromQueryTest{
+	"untitled control": {
+		source: `
+query "q1" {
+  title = "Buckets without versioning"
+  sql   = "select 1"
+}
+control "c1" {
+  query = query.q1
+}`,
+		expectedTitle: "Buckets without versioning",
+	},
+	"untitled control referencing query declared later": {
+		source: `
+control "c1" {
+  query = query.q1
+}
+query "q1" {
+  title = "Buckets without versioning"
+  sql   = "select 1"
+}`,
+		expectedTitle: "Buckets without versioning",
+	},
+	"explicit control title": {
+		source: `
+query "q1" {
+  title = "Buckets without versioning"
+  sql   = "select 1"
+}
+control "c1" {
+  title = "S3 versioning"
+  query = query.q1
+}`,
+		expectedTitle: "S3 versioning",
+	},
+	"base control title": {
+		source: `
+query "q1" {
+  title = "Buckets without versioning"
+  sql   = "select 1"
+}
+control "base" {
+  title = "S3 versioning"
+  query = query.q1
+}
+control "c1" {
+  base = control.base
+}`,
+		expectedTitle: "S3 versioning",
+	},
+	"untitled query": {
+		source: `
+query "q1" {
+  sql = "select 1"
+}
+control "c1" {
+  query = query.q1
+}`,
+	},
+}
+
+func TestControlTitleFromQuery(t *testing.T) {
+	for name, test := range testCasesControlTitleFromQuery {
+		control, err := decodeSingleControl(t, test.source)
+		if !checkDecodeError(t, name, err, "") {
+			continue
+		}
+		if title := typehelpers.SafeString(control.Title); title != test.expectedTitle {
+			t.Errorf("Test %s FAILED. Expected title '%s', got '%s'", name, test.expectedTitle, title)
+		}
+	}
+}
+
+type benchmarkChildPatternTest struct {
+	source           string
+	expectedChildren []string
+	expectedWarning  string
+	expectedError    string
+}
+
+var testCasesBenchmarkChildPattern = map[string]benchmarkChildPatternTest{
+	"include pattern": {
+		source: `
+benchmark "b1" {
+  include = ["aws_cis_*"]
+}
+control "aws_cis_1_1" {
+  sql = "select 1"
+}
+control "aws_cis_1_2" {
+  sql = "select 1"
+}
+control "gcp_cis_1_1" {
+  sql = "select 1"
+}`,
+		expectedChildren: []string{"local.control.aws_cis_1_1", "local.control.aws_cis_1_2"},
+	},
+	"include and exclude patterns": {
+		source: `
+benchmark "b1" {
+  include = ["aws_*", "gcp_cis_1_?"]
+  exclude = ["aws_cis_1_1"]
+}
+control "aws_cis_1_1" {
+  sql = "select 1"
+}
+control "aws_cis_1_2" {
+  sql = "select 1"
+}
+control "gcp_cis_1_1" {
+  sql = "select 1"
+}`,
+		expectedChildren: []string{"local.control.aws_cis_1_2", "local.control.gcp_cis_1_1"},
+	},
+	"explicit children and include pattern": {
+		source: `
+control "gcp_cis_1_1" {
+  sql = "select 1"
+}
+control "aws_cis_1_1" {
+  sql = "select 1"
+}
+benchmark "b1" {
+  children = [control.gcp_cis_1_1, control.aws_cis_1_1]
+  include  = ["aws_*"]
+}`,
+		expectedChildren: []string{"local.control.gcp_cis_1_1", "local.control.aws_cis_1_1"},
+	},
+	"explicit child excluded": {
+		source: `
+control "aws_cis_1_1" {
+  sql = "select 1"
+}
+benchmark "b1" {
+  children = [control.aws_cis_1_1]
+  include  = ["aws_*"]
+  exclude  = ["aws_cis_*"]
+}`,
+		expectedChildren: []string{"local.control.aws_cis_1_1"},
+		expectedWarning:  "matches exclude pattern 'aws_cis_*'",
+	},
+	"no matching controls": {
+		source: `
+benchmark "b1" {
+  include = ["azure_*"]
+}
+control "aws_cis_1_1" {
+  sql = "select 1"
+}`,
+		expectedChildren: []string{},
+		expectedWarning:  "include patterns select no controls",
+	},
+	"exclude without include": {
+		source: `
+benchmark "b1" {
+  children = [control.aws_cis_1_1]
+  exclude  = ["gcp_*"]
+}
+control "aws_cis_1_1" {
+  sql = "select 1"
+}`,
+		expectedChildren: []string{"local.control.aws_cis_1_1"},
+		expectedWarning:  "exclude has no effect",
+	},
+	"invalid pattern": {
+		source: `
+benchmark "b1" {
+  include = ["aws_[cis"]
+}`,
+		expectedError: "invalid child pattern 'aws_[cis'",
+	},
+}
+
+func TestDecodeBenchmarkChildPatterns(t *testing.T) {
+	for name, test := range testCasesBenchmarkChildPattern {
+		mod, res := parseTestMod(t, test.source)
+		if !checkDecodeError(t, name, res.Error, test.expectedError) {
+			continue
+		}
+		benchmark := mod.ResourceMaps.Benchmarks["local.benchmark.b1"]
+		if benchmark == nil {
+			t.Errorf("Test %s FAILED. Benchmark not found", name)
+			continue
+		}
+		if !reflect.DeepEqual(benchmark.ChildNameStrings, test.expectedChildren) {
+			t.Errorf("Test %s FAILED. Expected children %v, got %v", name, test.expectedChildren, benchmark.ChildNameStrings)
+		}
+		// the fixture controls share their sql, so ignore duplicate sql warnings
+		var patternWarnings []string
+		for _, w := range res.Warnings {
+			if !strings.Contains(w, "possible duplication") {
+				patternWarnings = append(patternWarnings, w)
+			}
+		}
+		warnings := strings.Join(patternWarnings, "\n")
+		if test.expectedWarning == "" && len(patternWarnings) > 0 {
+			t.Errorf("Test %s FAILED. Expected no warnings, got %s", name, warnings)
+		}
+		if test.expectedWarning != "" && strings.Count(warnings, test.expectedWarning) != 1 {
+			t.Errorf("Test %s FAILED. Expected a single warning containing '%s', got %s", name, test.expectedWarning, warnings)
+		}
+	}
+}
+
+const testInputValuesDashboardSource = `
+dashboard "d1" {
+  input "region" {
+    type     = "select"
+    required = true
+    option "us-east-1" {}
+    option "eu-west-1" {}
+  }
+  input "accounts" {
+    type = "multiselect"
+    option "prod" {}
+    option "dev" {}
+  }
+  input "search" {
+    type = "text"
+  }
+}
+
+dashboard "d2" {
+  base = dashboard.d1
+}`
+
+type inputValuesTest struct {
+	values         map[string]string
+	expectedErrors []string
+}
+
+var testCasesInputValues = map[string]inputValuesTest{
+	"valid values": {
+		values: map[string]string{"input.region": "us-east-1", "input.accounts": "prod, dev", "input.search": "anything"},
+	},
+	"only required value": {
+		values: map[string]string{"input.region": "eu-west-1"},
+	},
+	"invalid option": {
+		values:         map[string]string{"input.region": "ap-south-1"},
+		expectedErrors: []string{"invalid value 'ap-south-1' for input 'input.region'"},
+	},
+	"invalid multiselect option": {
+		values:         map[string]string{"input.region": "us-east-1", "input.accounts": "prod,staging"},
+		expectedErrors: []string{"invalid value 'staging' for input 'input.accounts'"},
+	},
+	"unknown input and missing required input": {
+		values: map[string]string{"input.zone": "a"},
+		expectedErrors: []string{
+			"local.dashboard.d1 has no input 'input.zone'",
+			"missing value for required input 'input.region'",
+		},
+	},
+}
+
+func TestDashboardValidateInputValues(t *testing.T) {
+	mod, res := parseTestMod(t, testInputValuesDashboardSource)
+	if res.Error != nil {
+		t.Fatalf("failed to parse mod: %v", res.Error)
+	}
+	// d2 inherits its inputs from d1, so must validate them in the same way
+	for _, dashboardName := range []string{"local.dashboard.d1", "local.dashboard.d2"} {
+		dashboard := mod.ResourceMaps.Dashboards[dashboardName]
+		if dashboard == nil {
+			t.Fatalf("dashboard %s not found", dashboardName)
+		}
+
+		for name, test := range testCasesInputValues {
+			var errors, expectedErrors []string
+			for _, diag := range dashboard.ValidateInputValues(test.values) {
+				errors = append(errors, diag.Summary)
+			}
+			for _, expected := range test.expectedErrors {
+				expectedErrors = append(expectedErrors, strings.ReplaceAll(expected, "local.dashboard.d1", dashboardName))
+			}
+			if !reflect.DeepEqual(errors, expectedErrors) {
+				t.Errorf("Test %s (%s) FAILED. Expected errors %v, got %v", name, dashboardName, expectedErrors, errors)
+			}
+		}
+	}
+}
+
+type controlCredentialHintTest struct {
+	source          string
+	expectedProfile *string
+	expectedRoleArn *string
+	expectedError   string
+}
+
+var testCasesControlCredentialHint = map[string]controlCredentialHintTest{
+	"profile": {
+		source: `
+control "c1" {
+  sql     = "select 1"
+  profile = "audit"
+}`,
+		expectedProfile: utils.ToStringPointer("audit"),
+	},
+	"role arn from base": {
+		source: `
+control "base" {
+  sql      = "select 1"
+  role_arn = "arn:aws:iam::123456789012:role/audit"
+}
+control "c1" {
+  base = control.base
+}`,
+		expectedRoleArn: utils.ToStringPointer("arn:aws:iam::123456789012:role/audit"),
+	},
+	"no credential hint": {
+		source: `
+control "c1" {
+  sql = "select 1"
+}`,
+	},
+	"empty profile": {
+		source: `
+control "c1" {
+  sql     = "select 1"
+  profile = " "
+}`,
+		expectedError: "local.control.c1 has an empty profile",
+	},
+}
+
+func TestDecodeControlCredentialHint(t *testing.T) {
+	for name, test := range testCasesControlCredentialHint {
+		control, err := decodeSingleControl(t, test.source)
+		if !checkDecodeError(t, name, err, test.expectedError) {
+			continue
+		}
+		if !utils.SafeStringsEqual(control.Profile, test.expectedProfile) {
+			t.Errorf("Test %s FAILED. Expected profile %v, got %v", name, typehelpers.SafeString(test.expectedProfile), typehelpers.SafeString(control.Profile))
+		}
+		if !utils.SafeStringsEqual(control.RoleArn, test.expectedRoleArn) {
+			t.Errorf("Test %s FAILED. Expected role_arn %v, got %v", name, typehelpers.SafeString(test.expectedRoleArn), typehelpers.SafeString(control.RoleArn))
+		}
+	}
+}
+
+type dashboardRowTest struct {
+	source        string
+	expectedRow   bool
+	expectedError string
+}
+
+var testCasesDashboardRow = map[string]dashboardRowTest{
+	"row widths sum to grid width": {
+		source: `
+dashboard "d1" {
+  container {
+    row = true
+    card {
+      width = 4
+      sql   = "select 1"
+    }
+    card {
+      width = 8
+      sql   = "select 1"
+    }
+  }
+}`,
+		expectedRow: true,
+	},
+	"row widths overflow": {
+		source: `
+dashboard "d1" {
+  container {
+    row = true
+    card {
+      width = 6
+      sql   = "select 1"
+    }
+    card {
+      width = 8
+      sql   = "select 1"
+    }
+  }
+}`,
+		expectedError: "combined width of its children is 14, which exceeds the grid width of 12",
+	},
+	"row child with no width": {
+		source: `
+dashboard "d1" {
+  container {
+    row = true
+    card {
+      width = 6
+      sql   = "select 1"
+    }
+    card {
+      sql = "select 1"
+    }
+  }
+}`,
+		expectedError: "combined width of its children is 18",
+	},
+	"not a row": {
+		source: `
+dashboard "d1" {
+  container {
+    card {
+      width = 6
+      sql   = "select 1"
+    }
+    card {
+      width = 8
+      sql   = "select 1"
+    }
+  }
+}`,
+	},
+}
+
+func TestDashboardRow(t *testing.T) {
+	for name, test := range testCasesDashboardRow {
+		mod, res := parseTestMod(t, test.source)
+		if !checkDecodeError(t, name, res.Error, test.expectedError) {
+			continue
+		}
+		if len(mod.ResourceMaps.DashboardContainers) != 1 {
+			t.Errorf("Test %s FAILED. Expected 1 container, got %d", name, len(mod.ResourceMaps.DashboardContainers))
+			continue
+		}
+		for _, container := range mod.ResourceMaps.DashboardContainers {
+			if container.IsRow() != test.expectedRow {
+				t.Errorf("Test %s FAILED. Expected row %v, got %v", name, test.expectedRow, container.IsRow())
+			}
+		}
+	}
+}
+
+type documentationLinkTest struct {
+	documentation   string
+	expectedWarning string
+}
+
+var testCasesDocumentationLink = map[string]documentationLinkTest{
+	"valid link": {
+		documentation: "https://hub.steampipe.io/mods/turbot/aws_compliance",
+	},
+	"markdown documentation": {
+		documentation: "# CIS\\n\\nSee https://www.cisecurity.org for details",
+	},
+	"missing scheme separator": {
+		documentation:   "https//hub.steampipe.io/mods",
+		expectedWarning: "local.benchmark.b1 has a malformed documentation link 'https//hub.steampipe.io/mods'",
+	},
+	"missing host": {
+		documentation:   "http:/hub.steampipe.io",
+		expectedWarning: "local.benchmark.b1 has a malformed documentation link 'http:/hub.steampipe.io'",
+	},
+}
+
+func TestDocumentationLinks(t *testing.T) {
+	for name, test := range testCasesDocumentationLink {
+		source := fmt.Sprintf(`
+benchmark "b1" {
+  documentation = "%s"
+  children      = [control.c1]
+}
+control "c1" {
+  sql = "select 1"
+}`, test.documentation)
+		_, res := parseTestMod(t, source)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			continue
+		}
+		warnings := strings.Join(res.Warnings, "\n")
+		if test.expectedWarning == "" {
+			if len(res.Warnings) > 0 {
+				t.Errorf("Test %s FAILED. Expected no warnings, got %s", name, warnings)
+			}
+			continue
+		}
+		if !strings.Contains(warnings, test.expectedWarning) {
+			t.Errorf("Test %s FAILED. Expected warning containing '%s', got %s", name, test.expectedWarning, warnings)
+		}
+	}
+}
+
+const testInputValidationDashboardSource = `
+dashboard "d1" {
+  input "name" {
+    type = "text"
+    validation {
+      condition     = can(regex("^[a-z_]+$", self.value))
+      error_message = "The name must contain only lowercase letters and underscores."
+    }
+    validation {
+      condition     = length(self.value) <= 10
+      error_message = "The name must be at most 10 characters."
+    }
+  }
+  input "accounts" {
+    type = "multicombo"
+    validation {
+      condition     = length(self.value) <= 2
+      error_message = "At most 2 accounts may be selected."
+    }
+  }
+}
+
+dashboard "d2" {
+  base = dashboard.d1
+}`
+
+type inputValidationTest struct {
+	values           map[string]string
+	expectedMessages []string
+}
+
+var testCasesInputValidation = map[string]inputValidationTest{
+	"valid values": {
+		values: map[string]string{"input.name": "aws_prod", "input.accounts": "prod,dev"},
+	},
+	"invalid characters": {
+		values:           map[string]string{"input.name": "AWS-prod"},
+		expectedMessages: []string{"The name must contain only lowercase letters and underscores."},
+	},
+	"multiple failures": {
+		values: map[string]string{"input.name": "AWS-production", "input.accounts": "prod,dev,test"},
+		expectedMessages: []string{
+			"At most 2 accounts may be selected.",
+			"The name must contain only lowercase letters and underscores.",
+			"The name must be at most 10 characters.",
+		},
+	},
+}
+
+func TestDashboardInputValidation(t *testing.T) {
+	mod, res := parseTestMod(t, testInputValidationDashboardSource)
+	if res.Error != nil {
+		t.Fatalf("failed to parse mod: %v", res.Error)
+	}
+	// d2 inherits its inputs from d1, so must apply the same validation rules
+	for _, dashboardName := range []string{"local.dashboard.d1", "local.dashboard.d2"} {
+		dashboard := mod.ResourceMaps.Dashboards[dashboardName]
+		if dashboard == nil {
+			t.Fatalf("dashboard %s not found", dashboardName)
+		}
+
+		for name, test := range testCasesInputValidation {
+			var messages []string
+			for _, diag := range dashboard.ValidateInputValues(test.values) {
+				messages = append(messages, diag.Detail)
+			}
+			if !reflect.DeepEqual(messages, test.expectedMessages) {
+				t.Errorf("Test %s (%s) FAILED. Expected messages %v, got %v", name, dashboardName, test.expectedMessages, messages)
+			}
+		}
+	}
+}
+
+type controlTestBlockTest struct {
+	source         string
+	expectedCounts []map[string]int
+	expectedError  string
+}
+
+var testCasesControlTestBlock = map[string]controlTestBlockTest{
+	"no tests": {
+		source: `
+control "c1" {
+  sql = "select 'ok' as status, 'r' as resource, 'reason' as reason"
+}`,
+	},
+	"single test": {
+		source: `
+control "c1" {
+  sql = "select 'ok' as status, 'r' as resource, 'reason' as reason"
+  test "fixture_1" {
+    description = "one bucket ok, two alarming"
+    ok    = 1
+    alarm = 2
+  }
+}`,
+		expectedCounts: []map[string]int{{"ok": 1, "alarm": 2}},
+	},
+	"multiple tests": {
+		source: `
+control "c1" {
+  sql = "select 'ok' as status, 'r' as resource, 'reason' as reason"
+  test "fixture_1" {
+    ok = 1
+  }
+  test "fixture_2" {
+    error = 0
+    skip  = 3
+  }
+}`,
+		expectedCounts: []map[string]int{{"ok": 1}, {"error": 0, "skip": 3}},
+	},
+	"negative count": {
+		source: `
+control "c1" {
+  sql = "select 'ok' as status, 'r' as resource, 'reason' as reason"
+  test "fixture_1" {
+    alarm = -1
+  }
+}`,
+		expectedError: "has invalid alarm count -1",
+	},
+	"no counts": {
+		source: `
+control "c1" {
+  sql = "select 'ok' as status, 'r' as resource, 'reason' as reason"
+  test "fixture_1" {
+    description = "nothing asserted"
+  }
+}`,
+		expectedError: "does not specify any expected status counts",
+	},
+	"duplicate test name": {
+		source: `
+control "c1" {
+  sql = "select 'ok' as status, 'r' as resource, 'reason' as reason"
+  test "fixture_1" {
+    ok = 1
+  }
+  test "fixture_1" {
+    ok = 2
+  }
+}`,
+		expectedError: "has duplicate test 'fixture_1'",
+	},
+}
+
+func TestDecodeControlTestBlock(t *testing.T) {
+	for name, test := range testCasesControlTestBlock {
+		control, err := decodeSingleControl(t, test.source)
+		if !checkDecodeError(t, name, err, test.expectedError) {
+			continue
+		}
+		if len(control.Tests) != len(test.expectedCounts) {
+			t.Errorf("Test %s FAILED. Expected %d tests, got %d", name, len(test.expectedCounts), len(control.Tests))
+			continue
+		}
+		for i, controlTest := range control.Tests {
+			if counts := controlTest.ExpectedCounts(); !reflect.DeepEqual(counts, test.expectedCounts[i]) {
+				t.Errorf("Test %s FAILED. Expected counts %v for test '%s', got %v", name, test.expectedCounts[i], controlTest.Name, counts)
+			}
+		}
+	}
+}
+
+type benchmarkSeverityTest struct {
+	source           string
+	expectedWarnings []string
+	expectedError    string
+}
+
+var testCasesBenchmarkSeverity = map[string]benchmarkSeverityTest{
+	"all controls meet min_severity": {
+		source: `
+benchmark "b1" {
+  min_severity = "high"
+  children     = [control.c1, control.c2]
+}
+control "c1" {
+  severity = "high"
+  sql      = "select 1"
+}
+control "c2" {
+  severity = "critical"
+  sql      = "select 1"
+}`,
+	},
+	"control below min_severity": {
+		source: `
+benchmark "b1" {
+  min_severity = "high"
+  children     = [control.c1, benchmark.b2]
+}
+benchmark "b2" {
+  children = [control.c2]
+}
+control "c1" {
+  severity = "high"
+  sql      = "select 1"
+}
+control "c2" {
+  severity = "low"
+  sql      = "select 1"
+}`,
+		expectedWarnings: []string{"local.control.c2 has severity 'low' which is below the minimum severity 'high' of local.benchmark.b1"},
+	},
+	"control with no severity": {
+		source: `
+benchmark "b1" {
+  min_severity = "low"
+  children     = [control.c1]
+}
+control "c1" {
+  sql = "select 1"
+}`,
+		expectedWarnings: []string{"local.control.c1 has no severity which is below the minimum severity 'low' of local.benchmark.b1"},
+	},
+	"control does not match required_severity": {
+		source: `
+benchmark "b1" {
+  required_severity = "medium"
+  children          = [control.c1, control.c2]
+}
+control "c1" {
+  severity = "medium"
+  sql      = "select 1"
+}
+control "c2" {
+  severity = "critical"
+  sql      = "select 1"
+}`,
+		expectedWarnings: []string{"local.control.c2 has severity 'critical' but local.benchmark.b1 requires severity 'medium'"},
+	},
+	"invalid min_severity": {
+		source: `
+benchmark "b1" {
+  min_severity = "urgent"
+  children     = [control.c1]
+}
+control "c1" {
+  severity = "high"
+  sql      = "select 1"
+}`,
+		expectedError: "local.benchmark.b1 has invalid min_severity 'urgent'",
+	},
+}
+
+func TestBenchmarkSeverityValidation(t *testing.T) {
+	for name, test := range testCasesBenchmarkSeverity {
+		_, res := parseTestMod(t, test.source)
+		if !checkDecodeError(t, name, res.Error, test.expectedError) {
+			continue
+		}
+		var severityWarnings []string
+		for _, warning := range res.Warnings {
+			if strings.Contains(warning, "severity") {
+				severityWarnings = append(severityWarnings, warning)
+			}
+		}
+		if len(severityWarnings) != len(test.expectedWarnings) {
+			t.Errorf("Test %s FAILED. Expected %d severity warnings, got %d: %v", name, len(test.expectedWarnings), len(severityWarnings), severityWarnings)
+			continue
+		}
+		for i, expected := range test.expectedWarnings {
+			if !strings.Contains(severityWarnings[i], expected) {
+				t.Errorf("Test %s FAILED. Expected warning containing '%s', got '%s'", name, expected, severityWarnings[i])
+			}
+		}
+	}
+}
+
+type benchmarkConditionTest struct {
+	source string
+	// the child names of benchmark b1 (nil if b1 is not expected to be in the mod)
+	expectedChildren []string
+	// the benchmarks and controls expected to have been excluded from the mod
+	expectedExcluded []string
+	// the benchmarks and controls expected to be in the mod
+	expectedIncluded []string
+	expectedError    string
+}
+
+var testCasesBenchmarkCondition = map[string]benchmarkConditionTest{
+	"no condition": {
+		source: `
+benchmark "b1" {
+  children = [benchmark.b2]
+}
+benchmark "b2" {
+  children = [control.c1]
+}
+control "c1" {
+  sql = "select 1"
+}`,
+		expectedChildren: []string{"local.benchmark.b2"},
+	},
+	"enabled by variable": {
+		source: `
+variable "pci" {
+  type    = bool
+  default = true
+}
+benchmark "b1" {
+  children = [benchmark.b2]
+}
+benchmark "b2" {
+  if       = var.pci
+  children = [control.c1]
+}
+control "c1" {
+  sql = "select 1"
+}`,
+		expectedChildren: []string{"local.benchmark.b2"},
+	},
+	"disabled by variable": {
+		source: `
+variable "pci" {
+  type    = bool
+  default = false
+}
+benchmark "b1" {
+  children = [benchmark.b2, control.c2]
+}
+benchmark "b2" {
+  if       = var.pci
+  children = [benchmark.b3]
+}
+benchmark "b3" {
+  children = [control.c1]
+}
+control "c1" {
+  sql = "select 1"
+}
+control "c2" {
+  sql = "select 1"
+}`,
+		expectedChildren: []string{"local.control.c2"},
+		expectedExcluded: []string{"benchmark.b2", "benchmark.b3", "control.c1"},
+	},
+	"disabled by variable with shared descendant": {
+		source: `
+variable "pci" {
+  type    = bool
+  default = false
+}
+benchmark "b1" {
+  children = [benchmark.b2, benchmark.b3]
+}
+benchmark "b2" {
+  if       = var.pci
+  children = [benchmark.b3, benchmark.b4]
+}
+benchmark "b3" {
+  children = [control.c1]
+}
+benchmark "b4" {
+  children = [control.c2]
+}
+control "c1" {
+  sql = "select 1"
+}
+control "c2" {
+  sql = "select 1"
+}`,
+		expectedChildren: []string{"local.benchmark.b3"},
+		expectedIncluded: []string{"benchmark.b3", "control.c1"},
+		expectedExcluded: []string{"benchmark.b2", "benchmark.b4", "control.c2"},
+	},
+	"disabled top level benchmark": {
+		source: `
+variable "pci" {
+  type    = bool
+  default = false
+}
+benchmark "b1" {
+  if       = var.pci
+  children = [control.c1]
+}
+control "c1" {
+  sql = "select 1"
+}`,
+		expectedExcluded: []string{"benchmark.b1", "control.c1"},
+	},
+	"non boolean condition": {
+		source: `
+benchmark "b1" {
+  if       = "yes please"
+  children = [control.c1]
+}
+control "c1" {
+  sql = "select 1"
+}`,
+		expectedError: "invalid 'if' condition for local.benchmark.b1",
+	},
+}
+
+func TestDecodeBenchmarkCondition(t *testing.T) {
+	for name, test := range testCasesBenchmarkCondition {
+		mod, res := parseTestMod(t, test.source)
+		if !checkDecodeError(t, name, res.Error, test.expectedError) {
+			continue
+		}
+		for _, excluded := range test.expectedExcluded {
+			if benchmarkOrControlExists(mod, "local."+excluded) {
+				t.Errorf("Test %s FAILED. Expected %s to be excluded from the mod", name, excluded)
+			}
+		}
+		for _, included := range test.expectedIncluded {
+			if !benchmarkOrControlExists(mod, "local."+included) {
+				t.Errorf("Test %s FAILED. Expected %s to be in the mod", name, included)
+			}
+		}
+		if test.expectedChildren == nil {
+			continue
+		}
+		b1, ok := mod.ResourceMaps.Benchmarks["local.benchmark.b1"]
+		if !ok {
+			t.Errorf("Test %s FAILED. Expected benchmark b1 to be in the mod", name)
+			continue
+		}
+		if !reflect.DeepEqual(b1.ChildNameStrings, test.expectedChildren) {
+			t.Errorf("Test %s FAILED. Expected children %v, got %v", name, test.expectedChildren, b1.ChildNameStrings)
+		}
+	}
+}
+
+func benchmarkOrControlExists(mod *modconfig.Mod, name string) bool {
+	_, isBenchmark := mod.ResourceMaps.Benchmarks[name]
+	_, isControl := mod.ResourceMaps.Controls[name]
+	return isBenchmark || isControl
+}
+
+type controlColumnTypesTest struct {
+	source              string
+	expectedColumnTypes map[string]string
+	expectedError       string
+}
+
+var testCasesControlColumnTypes = map[string]controlColumnTypesTest{
+	"no column types": {
+		source: `
+control "c1" {
+  sql = "select 'ok' as status, 'r' as resource, 'reason' as reason"
+}`,
+	},
+	"column types": {
+		source: `
+control "c1" {
+  sql = "select 'ok' as status, 'r' as resource, 'reason' as reason, now()::text as created"
+  column_types = {
+    created = "timestamp"
+    size    = "number"
+  }
+}`,
+		expectedColumnTypes: map[string]string{"created": "timestamp", "size": "number"},
+	},
+	"column types inherited from base": {
+		source: `
+control "base" {
+  sql = "select 'ok' as status, 'r' as resource, 'reason' as reason"
+  column_types = {
+    created = "date"
+  }
+}
+control "c1" {
+  base = control.base
+}`,
+		expectedColumnTypes: map[string]string{"created": "date"},
+	},
+	"invalid column type": {
+		source: `
+control "c1" {
+  sql = "select 'ok' as status, 'r' as resource, 'reason' as reason"
+  column_types = {
+    created = "datetime"
+  }
+}`,
+		expectedError: "local.control.c1 has invalid type 'datetime' for column 'created'",
+	},
+}
+
+func TestDecodeControlColumnTypes(t *testing.T) {
+	for name, test := range testCasesControlColumnTypes {
+		control, err := decodeSingleControl(t, test.source)
+		if !checkDecodeError(t, name, err, test.expectedError) {
+			continue
+		}
+		if !reflect.DeepEqual(control.ColumnTypes, test.expectedColumnTypes) {
+			t.Errorf("Test %s FAILED. Expected column types %v, got %v", name, test.expectedColumnTypes, control.ColumnTypes)
+		}
+	}
+}
+
+type controlBatchSizeTest struct {
+	source            string
+	expectedBatchSize *int
+	expectedError     string
+}
+
+var testCasesControlBatchSize = map[string]controlBatchSizeTest{
+	"no batch size": {
+		source: `
+control "c1" {
+  sql = "select 1"
+}`,
+	},
+	"batch size": {
+		source: `
+control "c1" {
+  sql        = "select 1"
+  batch_size = 500
+}`,
+		expectedBatchSize: utils.ToIntegerPointer(500),
+	},
+	"batch size inherited from base": {
+		source: `
+control "base" {
+  sql        = "select 1"
+  batch_size = 100
+}
+control "c1" {
+  base = control.base
+}`,
+		expectedBatchSize: utils.ToIntegerPointer(100),
+	},
+	"zero batch size": {
+		source: `
+control "c1" {
+  sql        = "select 1"
+  batch_size = 0
+}`,
+		expectedError: "local.control.c1 has invalid batch_size 0",
+	},
+	"negative batch size": {
+		source: `
+control "c1" {
+  sql        = "select 1"
+  batch_size = -10
+}`,
+		expectedError: "local.control.c1 has invalid batch_size -10",
+	},
+}
+
+func TestDecodeControlBatchSize(t *testing.T) {
+	for name, test := range testCasesControlBatchSize {
+		control, err := decodeSingleControl(t, test.source)
+		if !checkDecodeError(t, name, err, test.expectedError) {
+			continue
+		}
+		if !utils.SafeIntEqual(control.BatchSize, test.expectedBatchSize) {
+			t.Errorf("Test %s FAILED. Expected batch size %v, got %v", name, typehelpers.ToString(test.expectedBatchSize), typehelpers.ToString(control.BatchSize))
+		}
+	}
+}
+
+type panelBaseTest struct {
+	source string
+	// the expected properties of the panel named 'p1' in dashboard d1
+	panelType     string
+	expectedTitle string
+	expectedSql   string
+	expectedWidth int
+	// for charts, the expected chart type
+	expectedChartType string
+}
+
+var testCasesPanelBase = map[string]panelBaseTest{
+	"chart overrides query": {
+		source: `
+chart "template" {
+  title = "Template chart"
+  type  = "bar"
+  width = 6
+  sql   = "select 1 as a"
+}
+dashboard "d1" {
+  chart "p1" {
+    base = chart.template
+    sql  = "select 2 as a"
+  }
+}`,
+		panelType:         modconfig.BlockTypeChart,
+		expectedTitle:     "Template chart",
+		expectedSql:       "select 2 as a",
+		expectedWidth:     6,
+		expectedChartType: "bar",
+	},
+	"chart overrides title and type": {
+		source: `
+chart "template" {
+  title = "Template chart"
+  type  = "bar"
+  width = 6
+  sql   = "select 1 as a"
+}
+dashboard "d1" {
+  chart "p1" {
+    base  = chart.template
+    title = "Overridden chart"
+    type  = "line"
+  }
+}`,
+		panelType:         modconfig.BlockTypeChart,
+		expectedTitle:     "Overridden chart",
+		expectedSql:       "select 1 as a",
+		expectedWidth:     6,
+		expectedChartType: "line",
+	},
+	"chart based on a chart with a base": {
+		source: `
+chart "template" {
+  title = "Template chart"
+  type  = "bar"
+  width = 6
+  sql   = "select 1 as a"
+}
+chart "wide_template" {
+  base  = chart.template
+  width = 12
+}
+dashboard "d1" {
+  chart "p1" {
+    base = chart.wide_template
+    sql  = "select 2 as a"
+  }
+}`,
+		panelType:         modconfig.BlockTypeChart,
+		expectedTitle:     "Template chart",
+		expectedSql:       "select 2 as a",
+		expectedWidth:     12,
+		expectedChartType: "bar",
+	},
+	"table overrides query": {
+		source: `
+table "template" {
+  title = "Template table"
+  width = 4
+  sql   = "select 1 as a"
+}
+dashboard "d1" {
+  table "p1" {
+    base = table.template
+    sql  = "select 2 as a"
+  }
+}`,
+		panelType:     modconfig.BlockTypeTable,
+		expectedTitle: "Template table",
+		expectedSql:   "select 2 as a",
+		expectedWidth: 4,
+	},
+	"card overrides width": {
+		source: `
+card "template" {
+  title = "Template card"
+  width = 2
+  sql   = "select 1 as value"
+}
+dashboard "d1" {
+  card "p1" {
+    base  = card.template
+    width = 3
+  }
+}`,
+		panelType:     modconfig.BlockTypeCard,
+		expectedTitle: "Template card",
+		expectedSql:   "select 1 as value",
+		expectedWidth: 3,
+	},
+}
+
+func TestDecodePanelBase(t *testing.T) {
+	for name, test := range testCasesPanelBase {
+		mod, res := parseTestMod(t, test.source)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			continue
+		}
+		dashboard := mod.ResourceMaps.Dashboards["local.dashboard.d1"]
+		if dashboard == nil {
+			t.Errorf("Test %s FAILED. Dashboard not found", name)
+			continue
+		}
+		var panel modconfig.QueryProvider
+		for _, child := range dashboard.GetChildren() {
+			if child.BlockType() == test.panelType && child.GetUnqualifiedName() == test.panelType+".p1" {
+				panel, _ = child.(modconfig.QueryProvider)
+			}
+		}
+		if panel == nil {
+			t.Errorf("Test %s FAILED. Panel %s.p1 not found", name, test.panelType)
+			continue
+		}
+		if title := typehelpers.SafeString(panel.GetTitle()); title != test.expectedTitle {
+			t.Errorf("Test %s FAILED. Expected title '%s', got '%s'", name, test.expectedTitle, title)
+		}
+		if sql := typehelpers.SafeString(panel.GetSQL()); sql != test.expectedSql {
+			t.Errorf("Test %s FAILED. Expected sql '%s', got '%s'", name, test.expectedSql, sql)
+		}
+		if width := panel.(modconfig.DashboardLeafNode).GetWidth(); width != test.expectedWidth {
+			t.Errorf("Test %s FAILED. Expected width %d, got %d", name, test.expectedWidth, width)
+		}
+		if chart, ok := panel.(*modconfig.DashboardChart); ok {
+			if chartType := typehelpers.SafeString(chart.Type); chartType != test.expectedChartType {
+				t.Errorf("Test %s FAILED. Expected chart type '%s', got '%s'", name, test.expectedChartType, chartType)
+			}
+		}
+	}
+}
+
+type extractPanelTest struct {
+	source           string
+	panel            string
+	expectedChildren []string
+	expectedInputs   []string
+	expectedError    string
+}
+
+var testCasesExtractPanel = map[string]extractPanelTest{
+	"panel using an input": {
+		source: `
+dashboard "d1" {
+  input "account" {
+    sql = "select 'a' as label, 'a' as value"
+  }
+  input "region" {
+    sql  = "select $1 as label, $1 as value"
+    args = [self.input.account.value]
+  }
+  input "unused" {
+    sql = "select 'b' as label, 'b' as value"
+  }
+  card "c1" {
+    sql  = "select $1 as value"
+    args = [self.input.region.value]
+  }
+  card "c2" {
+    sql = "select 1 as value"
+  }
+}`,
+		panel:            "card.c1",
+		expectedChildren: []string{"local.input.account", "local.input.region", "local.card.c1"},
+		expectedInputs:   []string{"input.account", "input.region"},
+	},
+	"panel using a with": {
+		source: `
+dashboard "d1" {
+  with "w1" {
+    sql  = "select $1 as name"
+    args = [self.input.account.value]
+  }
+  input "account" {
+    sql = "select 'a' as label, 'a' as value"
+  }
+  container {
+    card "c1" {
+      sql  = "select $1 as value"
+      args = [with.w1.rows[0].name]
+    }
+  }
+}`,
+		panel:            "card.c1",
+		expectedChildren: []string{"local.with.w1", "local.input.account", "local.card.c1"},
+		expectedInputs:   []string{"input.account"},
+	},
+	"panel with no dependencies": {
+		source: `
+dashboard "d1" {
+  input "account" {
+    sql = "select 'a' as label, 'a' as value"
+  }
+  card "c1" {
+    sql = "select 1 as value"
+  }
+}`,
+		panel:            "card.c1",
+		expectedChildren: []string{"local.card.c1"},
+		expectedInputs:   []string{},
+	},
+	"unknown panel": {
+		source: `
+dashboard "d1" {
+  card "c1" {
+    sql = "select 1 as value"
+  }
+}`,
+		panel:         "card.c2",
+		expectedError: "local.dashboard.d1 has no panel 'card.c2'",
+	},
+}
+
+func TestDashboardExtractPanel(t *testing.T) {
+	for name, test := range testCasesExtractPanel {
+		mod, res := parseTestMod(t, test.source)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			continue
+		}
+		dashboard := mod.ResourceMaps.Dashboards["local.dashboard.d1"]
+		if dashboard == nil {
+			t.Errorf("Test %s FAILED. Dashboard not found", name)
+			continue
+		}
+		extracted, err := dashboard.ExtractPanel(test.panel)
+		if test.expectedError != "" {
+			if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+				t.Errorf("Test %s FAILED. Expected error containing '%s', got %v", name, test.expectedError, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, err)
+			continue
+		}
+		var children []string
+		for _, child := range extracted.GetChildren() {
+			children = append(children, child.Name())
+		}
+		if !reflect.DeepEqual(children, test.expectedChildren) {
+			t.Errorf("Test %s FAILED. Expected children %v, got %v", name, test.expectedChildren, children)
+		}
+		inputs := maps.Keys(extracted.GetInputs())
+		sort.Strings(inputs)
+		if !reflect.DeepEqual(inputs, test.expectedInputs) {
+			t.Errorf("Test %s FAILED. Expected inputs %v, got %v", name, test.expectedInputs, inputs)
+		}
+		if extracted.Name() != "local.dashboard.d1_c1" {
+			t.Errorf("Test %s FAILED. Expected name local.dashboard.d1_c1, got %s", name, extracted.Name())
+		}
+	}
+}
+
+type controlPrimaryKeyTest struct {
+	source             string
+	expectedPrimaryKey *string
+	expectedError      string
+}
+
+var testCasesControlPrimaryKey = map[string]controlPrimaryKeyTest{
+	"no primary key": {
+		source: `
+control "c1" {
+  sql = "select 1"
+}`,
+	},
+	"primary key": {
+		source: `
+control "c1" {
+  sql         = "select 'arn:1' as arn, 'ok' as status, 'r1' as reason, 'r1' as resource"
+  primary_key = "arn"
+}`,
+		expectedPrimaryKey: utils.ToStringPointer("arn"),
+	},
+	"primary key inherited from base": {
+		source: `
+control "base" {
+  sql         = "select 1"
+  primary_key = "instance_id"
+}
+control "c1" {
+  base = control.base
+}`,
+		expectedPrimaryKey: utils.ToStringPointer("instance_id"),
+	},
+	"empty primary key": {
+		source: `
+control "c1" {
+  sql         = "select 1"
+  primary_key = ""
+}`,
+		expectedError: "local.control.c1 has invalid primary_key ''",
+	},
+	"primary key is not a column name": {
+		source: `
+control "c1" {
+  sql         = "select 1"
+  primary_key = "arn, region"
+}`,
+		expectedError: "local.control.c1 has invalid primary_key 'arn, region'",
+	},
+}
+
+func TestDecodeControlPrimaryKey(t *testing.T) {
+	for name, test := range testCasesControlPrimaryKey {
+		control, err := decodeSingleControl(t, test.source)
+		if !checkDecodeError(t, name, err, test.expectedError) {
+			continue
+		}
+		if !utils.SafeStringsEqual(control.PrimaryKey, test.expectedPrimaryKey) {
+			t.Errorf("Test %s FAILED. Expected primary key %v, got %v", name, typehelpers.SafeString(test.expectedPrimaryKey), typehelpers.SafeString(control.PrimaryKey))
+		}
+	}
+}
+
+type duplicateLocalTest struct {
+	source        string
+	expectedError string
+	// the range of the duplicate definition
+	expectedSubject string
+}
+
+var testCasesDuplicateLocal = map[string]duplicateLocalTest{
+	"unique locals in separate blocks": {
+		source: `
+locals {
+  a = "x"
+}
+locals {
+  b = local.a
+}
+control "c1" {
+  sql   = "select 1"
+  title = local.b
+}`,
+	},
+	"duplicate local in separate blocks": {
+		source: `
+locals {
+  a = "x"
+}
+locals {
+  a = "y"
+}`,
+		expectedError:   "Duplicate local value 'a': A local value named 'a' was already defined at /tmp/parse_test_mod/test.sp:3,3-4",
+		expectedSubject: "test.sp:6,3-4",
+	},
+	"duplicate local with dependency": {
+		source: `
+locals {
+  a = local.b
+  b = "x"
+}
+locals {
+  c = "z"
+  a = "y"
+}`,
+		expectedError:   "Duplicate local value 'a': A local value named 'a' was already defined at /tmp/parse_test_mod/test.sp:3,3-4",
+		expectedSubject: "test.sp:8,3-4",
+	},
+}
+
+func TestDecodeDuplicateLocals(t *testing.T) {
+	for name, test := range testCasesDuplicateLocal {
+		_, res := parseTestMod(t, test.source)
+		if test.expectedError == "" {
+			if res.Error != nil {
+				t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			}
+			continue
+		}
+		if res.Error == nil || !strings.Contains(res.Error.Error(), test.expectedError) {
+			t.Errorf("Test %s FAILED. Expected error containing '%s', got %v", name, test.expectedError, res.Error)
+			continue
+		}
+		if !strings.Contains(res.Error.Error(), test.expectedSubject) {
+			t.Errorf("Test %s FAILED. Expected error for range '%s', got %v", name, test.expectedSubject, res.Error)
+		}
+	}
+}
+
+type unsupportedNameSuggestionTest struct {
+	source        string
+	expectedError string
+}
+
+var testCasesUnsupportedNameSuggestion = map[string]unsupportedNameSuggestionTest{
+	"misspelt block": {
+		source: `
+dashboard "d1" {
+  chrt {
+    sql = "select 1"
+  }
+}`,
+		expectedError: `Unsupported block type: Blocks of type 'chrt' are not expected here.: Did you mean "chart"?`,
+	},
+	"misspelt nested block": {
+		source: `
+dashboard "d1" {
+  containr {
+    card {
+      sql = "select 1"
+    }
+  }
+}`,
+		expectedError: `Unsupported block type: Blocks of type 'containr' are not expected here.: Did you mean "container"?`,
+	},
+	"misspelt attribute": {
+		source: `
+control "c1" {
+  sql      = "select 1"
+  severty = "high"
+}`,
+		expectedError: `Unsupported attribute: 'severty' not expected here.: Did you mean "severity"?`,
+	},
+	"no close match": {
+		source: `
+dashboard "d1" {
+  histogram {
+    sql = "select 1"
+  }
+}`,
+		expectedError: `Unsupported block type: Blocks of type 'histogram' are not expected here.`,
+	},
+}
+
+func TestUnsupportedNameSuggestion(t *testing.T) {
+	for name, test := range testCasesUnsupportedNameSuggestion {
+		_, res := parseTestMod(t, test.source)
+		if res.Error == nil || !strings.Contains(res.Error.Error(), test.expectedError) {
+			t.Errorf("Test %s FAILED. Expected error containing '%s', got %v", name, test.expectedError, res.Error)
+			continue
+		}
+		if strings.HasSuffix(test.expectedError, "here.") && strings.Contains(res.Error.Error(), "Did you mean") {
+			t.Errorf("Test %s FAILED. Expected no suggestion, got %v", name, res.Error)
+		}
+	}
+}
+
+type unsupportedAttributeTest struct {
+	source        string
+	expectedError string
+}
+
+var testCasesUnsupportedAttribute = map[string]unsupportedAttributeTest{
+	"misspelt dashboard attribute": {
+		source: `
+dashboard "d1" {
+  widht = 4
+  card {
+    sql = "select 1"
+  }
+}`,
+		expectedError: "Unsupported attribute: 'widht' not expected here.",
+	},
+	"misspelt nested panel attribute": {
+		source: `
+dashboard "d1" {
+  card {
+    sql   = "select 1"
+    widht = 4
+  }
+}`,
+		expectedError: "Unsupported attribute: 'widht' not expected here.",
+	},
+	"label used as attribute": {
+		source: `
+control "c1" {
+  name = "c2"
+  sql  = "select 1"
+}`,
+		expectedError: "Unsupported attribute: 'name' not expected here.",
+	},
+	"optional attribute": {
+		source: `
+control "c1" {
+  sql          = "select 1"
+  column_types = { created = "timestamp" }
+}`,
+	},
+}
+
+func TestUnsupportedAttribute(t *testing.T) {
+	for name, test := range testCasesUnsupportedAttribute {
+		_, res := parseTestMod(t, test.source)
+		if test.expectedError == "" {
+			if res.Error != nil {
+				t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			}
+			continue
+		}
+		if res.Error == nil || !strings.Contains(res.Error.Error(), test.expectedError) {
+			t.Errorf("Test %s FAILED. Expected error containing '%s', got %v", name, test.expectedError, res.Error)
+		}
+	}
+}
+
+type runtimeDependenciesTest struct {
+	source        string
+	expectedError string
+}
+
+var testCasesRuntimeDependencies = map[string]runtimeDependenciesTest{
+	"cards depending on input and with": {
+		source: `
+dashboard "d1" {
+  input "i1" {
+    sql = "select 'a' as label, 'a' as value"
+  }
+  with "w1" {
+    sql  = "select $1 as name"
+    args = [self.input.i1.value]
+  }
+  card "c1" {
+    sql  = "select $1"
+    args = [self.input.i1.value]
+  }
+  card "c2" {
+    sql  = "select $1"
+    args = [with.w1.rows[0].name]
+  }
+}`,
+	},
+	"cycle between graph withs": {
+		source: `
+graph "g1" {
+  with "w1" {
+    sql  = "select $1 as id"
+    args = [with.w2.rows[0].id]
+  }
+  with "w2" {
+    sql  = "select $1 as id"
+    args = [with.w1.rows[0].id]
+  }
+  node "n1" {
+    sql  = "select $1 as id"
+    args = [with.w1.rows[0].id]
+  }
+}
+dashboard "d1" {
+  graph {
+    base = graph.g1
+  }
+}`,
+		expectedError: "runtime dependencies cannot be resolved - dependency cycle: ",
+	},
+	"unresolvable with": {
+		source: `
+dashboard "d1" {
+  card "c1" {
+    sql  = "select $1"
+    args = [with.missing.rows[0].name]
+  }
+}`,
+		expectedError: "cannot resolve runtime dependency 'with.missing.rows.0.name' of local.card.c1",
+	},
+	"with depending on param of base": {
+		source: `
+graph "g1" {
+  param "arn" {}
+  with "w1" {
+    sql  = "select $1 as id"
+    args = [param.arn]
+  }
+  node "n1" {
+    sql  = "select $1 as id"
+    args = [with.w1.rows[0].id]
+  }
+}
+dashboard "d1" {
+  input "i1" {
+    type = "text"
+  }
+  graph {
+    base = graph.g1
+    args = {
+      arn = self.input.i1.value
+    }
+  }
+}`,
+	},
+	"unresolvable param": {
+		source: `
+graph "g1" {
+  with "w1" {
+    sql  = "select $1 as id"
+    args = [param.missing]
+  }
+  node "n1" {
+    sql  = "select $1 as id"
+    args = [with.w1.rows[0].id]
+  }
+}
+dashboard "d1" {
+  graph {
+    base = graph.g1
+  }
+}`,
+		expectedError: "cannot resolve runtime dependency 'param.missing' of local.with.w1",
+	},
+	"cards whose args reference each other": {
+		source: `
+dashboard "d1" {
+  card "a" {
+    sql  = "select $1"
+    args = [card.b.value]
+  }
+  card "b" {
+    sql  = "select $1"
+    args = [card.a.value]
+  }
+}`,
+		expectedError: "cannot resolve runtime dependency 'card.b.value' of local.card.a",
+	},
+}
+
+func TestValidateRuntimeDependencies(t *testing.T) {
+	for name, test := range testCasesRuntimeDependencies {
+		mod, res := parseTestMod(t, test.source)
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+			continue
+		}
+		dashboard := mod.ResourceMaps.Dashboards["local.dashboard.d1"]
+		if dashboard == nil {
+			t.Errorf("Test %s FAILED. Dashboard not found", name)
+			continue
+		}
+		err := dashboard.ValidateRuntimeDependencies(mod)
+		if test.expectedError == "" {
+			if err != nil {
+				t.Errorf("Test %s FAILED with unexpected error: %v", name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+			t.Errorf("Test %s FAILED. Expected error containing '%s', got %v", name, test.expectedError, err)
+		}
+	}
+}
+
+type dashboardWidthTest struct {
+	source        string
+	expectedError string
+}
+
+var testCasesDashboardWidth = map[string]dashboardWidthTest{
+	"valid widths": {
+		source: `
+dashboard "d1" {
+  width = 12
+  container {
+    width = 6
+    card {
+      width = 1
+      sql   = "select 1"
+    }
+  }
+}`,
+	},
+	"dashboard width too large": {
+		source: `
+dashboard "d1" {
+  width = 13
+}`,
+		expectedError: "local.dashboard.d1 has invalid width 13",
+	},
+	"container width zero": {
+		source: `
+dashboard "d1" {
+  container {
+    width = 0
+  }
+}`,
+		expectedError: "has invalid width 0",
+	},
+	"nested chart width negative": {
+		source: `
+dashboard "d1" {
+  chart "c1" {
+    width = -1
+    sql   = "select 1"
+  }
+}`,
+		expectedError: "local.chart.c1 has invalid width -1",
+	},
+	"top level table width too large": {
+		source: `
+table "t1" {
+  width = 24
+  sql   = "select 1"
+}`,
+		expectedError: "local.table.t1 has invalid width 24",
+	},
+}
+
+func TestDecodeDashboardWidth(t *testing.T) {
+	for name, test := range testCasesDashboardWidth {
+		_, res := parseTestMod(t, test.source)
+		if test.expectedError != "" {
+			if res.Error == nil || !strings.Contains(res.Error.Error(), test.expectedError) {
+				t.Errorf("Test %s FAILED. Expected error containing '%s', got %v", name, test.expectedError, res.Error)
+			}
+			continue
+		}
+		if res.Error != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, res.Error)
+		}
+	}
+}
+
+type inputDefaultTest struct {
+	source        string
+	inputValues   map[string]any
+	expected      map[string]any
+	expectedError string
+}
+
+var testCasesInputDefault = map[string]inputDefaultTest{
+	"literal default": {
+		source: `
+dashboard "d1" {
+  input "region" {
+    type    = "text"
+    default = "us-east-1"
+  }
+}`,
+		expected: map[string]any{"input.region": "us-east-1"},
+	},
+	"number default": {
+		source: `
+dashboard "d1" {
+  input "limit" {
+    type    = "text"
+    default = 10
+  }
+}`,
+		expected: map[string]any{"input.limit": "10"},
+	},
+	"list default for multiselect": {
+		source: `
+dashboard "d1" {
+  input "regions" {
+    type    = "multiselect"
+    default = ["us-east-1", "eu-west-2"]
+    option "us-east-1" {}
+    option "eu-west-2" {}
+  }
+}`,
+		expected: map[string]any{"input.regions": []any{"us-east-1", "eu-west-2"}},
+	},
+	"list default for text": {
+		source: `
+dashboard "d1" {
+  input "region" {
+    type    = "text"
+    default = ["us-east-1"]
+  }
+}`,
+		expectedError: "a list default is only supported for inputs of type multiselect or multicombo",
+	},
+	"map default": {
+		source: `
+dashboard "d1" {
+  input "region" {
+    type    = "text"
+    default = { name = "us-east-1" }
+  }
+}`,
+		expectedError: "default must be a string or a list of strings",
+	},
+	"provided value takes precedence": {
+		source: `
+dashboard "d1" {
+  input "region" {
+    type    = "text"
+    default = "us-east-1"
+  }
+}`,
+		inputValues: map[string]any{"input.region": "eu-west-2"},
+		expected:    map[string]any{"input.region": "eu-west-2"},
+	},
+	"default references another input": {
+		source: `
+dashboard "d1" {
+  input "replica_region" {
+    type    = "text"
+    default = self.input.region.value
+  }
+  input "region" {
+    type    = "text"
+    default = "us-east-1"
+  }
+}`,
+		expected: map[string]any{"input.region": "us-east-1", "input.replica_region": "us-east-1"},
+	},
+	"default references provided input": {
+		source: `
+dashboard "d1" {
+  input "region" {
+    type = "text"
+  }
+  input "replica_region" {
+    type    = "text"
+    default = self.input.region.value
+  }
+}`,
+		inputValues: map[string]any{"input.region": "eu-west-2"},
+		expected:    map[string]any{"input.region": "eu-west-2", "input.replica_region": "eu-west-2"},
+	},
+	"default references missing input": {
+		source: `
+dashboard "d1" {
+  input "replica_region" {
+    type    = "text"
+    default = self.input.region.value
+  }
+}`,
+		expectedError: "default of input.replica_region references input 'input.region' which does not exist in dashboard 'local.dashboard.d1'",
+	},
+	"default dependency cycle": {
+		source: `
+dashboard "d1" {
+  input "a" {
+    type    = "text"
+    default = self.input.b.value
+  }
+  input "b" {
+    type    = "text"
+    default = self.input.a.value
+  }
+}`,
+		expectedError: "Failed to resolve input dependency order for dashboard 'local.dashboard.d1'",
+	},
+	"default references a with": {
+		source: `
+dashboard "d1" {
+  with "w1" {
+    sql = "select 1"
+  }
+  input "a" {
+    type    = "text"
+    default = self.with.w1.rows[0]
+  }
+}`,
+		expectedError: "local.input.a has invalid default",
+	},
+}
+
+func TestDecodeInputDefault(t *testing.T) {
+	for name, test := range testCasesInputDefault {
+		mod, res := parseTestMod(t, test.source)
+		if !checkDecodeError(t, name, res.Error, test.expectedError) {
+			continue
+		}
+		dashboard := mod.ResourceMaps.Dashboards["local.dashboard.d1"]
+		if values := dashboard.ResolveDefaultInputValues(test.inputValues); !reflect.DeepEqual(values, test.expected) {
+			t.Errorf("Test %s FAILED. Expected %v, got %v", name, test.expected, values)
+		}
+	}
+}
+
+func TestModChildrenDeclarationOrder(t *testing.T) {
+	source := `
+benchmark "b3" {
+  children = [control.c3]
+}
+control "c3" {
+  sql = "select 3"
+}
+benchmark "b1" {
+  children = [control.c1]
+}
+control "c1" {
+  sql = "select 1"
+}
+control "c2" {
+  sql = "select 2"
+}
+`
+	expected := []string{"local.benchmark.b3", "local.benchmark.b1", "local.control.c2"}
+	// the resources are walked in map order, so parse several times to ensure the order is stable
+	for i := 0; i < 10; i++ {
+		mod, res := parseTestMod(t, source)
+		if res.Error != nil {
+			t.Fatalf("Test TestModChildrenDeclarationOrder FAILED with unexpected error: %v", res.Error)
+		}
+		var children []string
+		for _, child := range mod.GetChildren() {
+			children = append(children, child.Name())
+		}
+		if !reflect.DeepEqual(children, expected) {
+			t.Fatalf("Test TestModChildrenDeclarationOrder FAILED. Expected %v, got %v", expected, children)
+		}
+	}
+}
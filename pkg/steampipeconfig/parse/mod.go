@@ -166,6 +166,14 @@ func ParseMod(ctx context.Context, fileData map[string][]byte, pseudoResources [
 		}
 		// if the number of unresolved blocks has NOT reduced, fail
 		if prevUnresolvedBlocks != 0 && unresolvedBlocks >= prevUnresolvedBlocks {
+			// if the failure is due to an unresolvable base, report that
+			if diags := parseCtx.unresolvedBaseDiags(); diags.HasErrors() {
+				return nil, error_helpers.NewErrorsAndWarning(plugin.DiagsToError("Failed to resolve base", diags))
+			}
+			// if the failure is due to an unresolvable child in a dependency mod, report that
+			if diags := parseCtx.unresolvedChildDiags(); diags.HasErrors() {
+				return nil, error_helpers.NewErrorsAndWarning(plugin.DiagsToError("Failed to resolve children", diags))
+			}
 			str := parseCtx.FormatDependencies()
 			return nil, error_helpers.NewErrorsAndWarning(fmt.Errorf("failed to resolve dependencies for mod '%s' after %d attempts\nDependencies:\n%s", mod.FullName, attempts+1, str))
 		}
@@ -173,8 +181,22 @@ func ParseMod(ctx context.Context, fileData map[string][]byte, pseudoResources [
 		prevUnresolvedBlocks = unresolvedBlocks
 	}
 
+	// remove the descendants of any benchmarks whose 'if' condition evaluated false
+	removeDisabledBenchmarkDescendants(mod, parseCtx)
+
 	// now tell mod to build tree of resources
 	res.Error = mod.BuildResourceTree(parseCtx.GetTopLevelDependencyMods())
 
+	// warn about any references to deprecated resources
+	res.AddWarning(plugin.DiagsToWarnings(validateDeprecatedReferences(mod))...)
+	// warn about any benchmarks which contain no controls
+	res.AddWarning(plugin.DiagsToWarnings(validateEmptyBenchmarks(mod))...)
+	// warn about any controls which do not meet the severity expectations of their benchmarks
+	res.AddWarning(plugin.DiagsToWarnings(validateBenchmarkSeverities(mod))...)
+	// warn about any controls which have the same sql as another control
+	res.AddWarning(plugin.DiagsToWarnings(validateDuplicateControlSql(mod))...)
+	// warn about any malformed (or, if the flag is set, unreachable) documentation links
+	res.AddWarning(plugin.DiagsToWarnings(validateDocumentationLinks(mod, parseCtx.CheckDocumentationLinks()))...)
+
 	return mod, res
 }
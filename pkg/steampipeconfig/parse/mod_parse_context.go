@@ -2,7 +2,9 @@ package parse
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -23,6 +25,14 @@ type ParseModFlag uint32
 const (
 	CreateDefaultMod ParseModFlag = 1 << iota
 	CreatePseudoResources
+	// ParallelDecode decodes blocks with no interdependencies concurrently
+	ParallelDecode
+	// IncrementalDecode releases the parsed hcl of each block as soon as its resources have been added to the mod
+	// this reduces the peak memory used when parsing very large (e.g. generated) files
+	IncrementalDecode
+	// CheckDocumentationLinks checks that documentation links are reachable (as well as well formed)
+	// NOTE: this makes a network request for each link
+	CheckDocumentationLinks
 )
 
 /*
@@ -37,6 +47,9 @@ ReferenceTypeValueMap is keyed  by resource type, then by resource name
 */
 type ReferenceTypeValueMap map[string]map[string]cty.Value
 
+// TitleResolverFunc returns the title for the resource with the given (full) name, if one is available
+type TitleResolverFunc func(resourceName string) (string, bool)
+
 type ModParseContext struct {
 	ParseContext
 	// the mod which is currently being parsed
@@ -64,6 +77,15 @@ type ModParseContext struct {
 
 	// map of top  level blocks, for easy checking
 	topLevelBlocks map[*hcl.Block]struct{}
+	// sorted names of the top level controls declared in the current mod
+	// - used to resolve benchmark include/exclude patterns
+	controlNames []string
+	// the benchmarks whose 'if' condition evaluated false, keyed by full name
+	// - these are not added to the mod and are excluded from the children of their parents
+	disabledBenchmarks map[string]*modconfig.Benchmark
+	// the name ranges of the locals decoded so far, keyed by local name
+	// - used to report a local which is defined in more than one locals block
+	localDefinitions map[string]hcl.Range
 	// map of block names, keyed by a hash of the blopck
 	blockNameMap map[string]string
 	// map of ReferenceTypeValueMaps keyed by mod name
@@ -74,6 +96,15 @@ type ModParseContext struct {
 	topLevelDependencyMods modconfig.ModMap
 	// if we are loading dependency mod, this contains the details
 	DependencyConfig *ModDependencyConfig
+	// if set, this is used to resolve the titles of benchmarks and controls
+	// a title returned by the resolver overrides the title defined in HCL
+	TitleResolver TitleResolverFunc
+
+	// lock used to synchronise updates to the run context when decoding blocks concurrently
+	lock sync.Mutex
+	// if set, resources added to the run context are not added to the eval context until the deferral is ended
+	// (this ensures the eval context is not mutated while blocks are being decoded concurrently)
+	deferEvalContextRebuild bool
 }
 
 func NewModParseContext(workspaceLock *versionmap.WorkspaceLock, rootEvalPath string, flags ParseModFlag, listOptions *filehelpers.ListOptions) *ModParseContext {
@@ -87,6 +118,8 @@ func NewModParseContext(workspaceLock *versionmap.WorkspaceLock, rootEvalPath st
 		topLevelDependencyMods: make(modconfig.ModMap),
 		blockChildMap:          make(map[string][]string),
 		blockNameMap:           make(map[string]string),
+		disabledBenchmarks:     make(map[string]*modconfig.Benchmark),
+		localDefinitions:       make(map[string]hcl.Range),
 		// initialise reference maps - even though we later overwrite them
 		referenceValues: map[string]ReferenceTypeValueMap{
 			"local": make(ReferenceTypeValueMap),
@@ -108,6 +141,8 @@ func NewChildModParseContext(parent *ModParseContext, modVersion *versionmap.Res
 		parent.ListOptions)
 	// copy our block tpyes
 	child.BlockTypes = parent.BlockTypes
+	// copy the title resolver
+	child.TitleResolver = parent.TitleResolver
 	// set the child's parent
 	child.ParentParseCtx = parent
 	// set the dependency config
@@ -244,9 +279,14 @@ func (m *ModParseContext) AddModResources(mod *modconfig.Mod) hcl.Diagnostics {
 func (m *ModParseContext) SetDecodeContent(content *hcl.BodyContent, fileData map[string][]byte) {
 	// put blocks into map as well
 	m.topLevelBlocks = make(map[*hcl.Block]struct{}, len(m.blocks))
+	m.controlNames = nil
 	for _, b := range content.Blocks {
 		m.topLevelBlocks[b] = struct{}{}
+		if b.Type == modconfig.BlockTypeControl && len(b.Labels) > 0 {
+			m.controlNames = append(m.controlNames, b.Labels[0])
+		}
 	}
+	sort.Strings(m.controlNames)
 	m.ParseContext.SetDecodeContent(content, fileData)
 }
 
@@ -254,6 +294,9 @@ func (m *ModParseContext) SetDecodeContent(content *hcl.BodyContent, fileData ma
 // 1) store block as unresolved
 // 2) add dependencies to our tree of dependencies
 func (m *ModParseContext) AddDependencies(block *hcl.Block, name string, dependencies map[string]*modconfig.ResourceDependency) hcl.Diagnostics {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
 	// TACTICAL if this is NOT a top level block, add a suffix to the block name
 	// this is needed to avoid circular dependency errors if a nested block references
 	// a top level block with the same name
@@ -263,6 +306,22 @@ func (m *ModParseContext) AddDependencies(block *hcl.Block, name string, depende
 	return m.ParseContext.AddDependencies(block, name, dependencies)
 }
 
+// DependencyGraph returns the dependency edges between blocks found while decoding,
+// as a map of block name to the (sorted) names of the resources it depends on
+// this is intended as a diagnostic aid, to explain the order in which blocks are decoded
+func (m *ModParseContext) DependencyGraph() map[string][]string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	res := make(map[string][]string, len(m.dependencyEdges))
+	for name, deps := range m.dependencyEdges {
+		sortedDeps := append([]string{}, deps...)
+		sort.Strings(sortedDeps)
+		res[name] = sortedDeps
+	}
+	return res
+}
+
 // ShouldCreateDefaultMod returns whether the flag is set to create a default mod if no mod definition exists
 func (m *ModParseContext) ShouldCreateDefaultMod() bool {
 	return m.Flags&CreateDefaultMod == CreateDefaultMod
@@ -273,19 +332,103 @@ func (m *ModParseContext) CreatePseudoResources() bool {
 	return m.Flags&CreatePseudoResources == CreatePseudoResources
 }
 
+// ParallelDecode returns whether the flag is set to decode independent blocks concurrently
+func (m *ModParseContext) ParallelDecode() bool {
+	return m.Flags&ParallelDecode == ParallelDecode
+}
+
+// IncrementalDecode returns whether the flag is set to release the parsed hcl of each block once it is decoded
+func (m *ModParseContext) IncrementalDecode() bool {
+	return m.Flags&IncrementalDecode == IncrementalDecode
+}
+
+// CheckDocumentationLinks returns whether the flag is set to check documentation links are reachable
+func (m *ModParseContext) CheckDocumentationLinks() bool {
+	return m.Flags&CheckDocumentationLinks == CheckDocumentationLinks
+}
+
+// releaseDecodeContent removes the references the run context holds to the top level blocks to decode
+// the caller takes ownership of the blocks and is responsible for releasing each block once it is decoded
+// (see releaseBlock)
+func (m *ModParseContext) releaseDecodeContent() {
+	m.blocks = nil
+}
+
+// releaseBlock removes all references the run context holds to a block which has been successfully decoded,
+// and clears the hcl body retained by the decoded resources, so the parsed hcl of the block may be garbage collected
+// NOTE: this must only be called for blocks which have been fully resolved, as they will never be decoded again
+func (m *ModParseContext) releaseBlock(block *hcl.Block, resources []modconfig.HclResource) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.topLevelBlocks, block)
+	for _, resource := range resources {
+		releaseRemain(resource)
+	}
+}
+
 // AddResource stores this resource as a variable to be added to the eval context.
 func (m *ModParseContext) AddResource(resource modconfig.HclResource) hcl.Diagnostics {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
 	diagnostics := m.storeResourceInReferenceValueMap(resource)
 	if diagnostics.HasErrors() {
 		return diagnostics
 	}
 
-	// rebuild the eval context
-	m.buildEvalContext()
+	// rebuild the eval context (unless rebuilding is deferred)
+	if !m.deferEvalContextRebuild {
+		m.buildEvalContext()
+	}
 
 	return nil
 }
 
+// addDisabledBenchmark records that the benchmark is disabled
+func (m *ModParseContext) addDisabledBenchmark(benchmark *modconfig.Benchmark) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.disabledBenchmarks[benchmark.Name()] = benchmark
+}
+
+// isDisabledBenchmark returns whether the benchmark with the given full name is disabled
+func (m *ModParseContext) isDisabledBenchmark(name string) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	_, disabled := m.disabledBenchmarks[name]
+	return disabled
+}
+
+// addLocalDefinition records the name range of a decoded local
+// if a local with the same name has already been defined elsewhere, the range of that definition is returned
+// (a locals block may be decoded more than once, so a definition with the same range is not a duplicate)
+func (m *ModParseContext) addLocalDefinition(name string, nameRange hcl.Range) (hcl.Range, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if existing, ok := m.localDefinitions[name]; ok && existing.String() != nameRange.String() {
+		return existing, true
+	}
+	m.localDefinitions[name] = nameRange
+	return hcl.Range{}, false
+}
+
+// beginConcurrentDecode stops resources added to the run context being added to the eval context
+// this must be called before decoding blocks concurrently, as the eval context must not be mutated while it is in use
+func (m *ModParseContext) beginConcurrentDecode() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.deferEvalContextRebuild = true
+}
+
+// endConcurrentDecode rebuilds the eval context to include all resources added since beginConcurrentDecode was called
+func (m *ModParseContext) endConcurrentDecode() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.deferEvalContextRebuild = false
+	m.buildEvalContext()
+}
+
 // GetMod finds the mod with given short name, looking only in first level dependencies
 // this is used to resolve resource references
 // specifically when the 'children' property of dashboards and benchmarks refers to resource in a dependency mod
@@ -302,7 +445,13 @@ func (m *ModParseContext) GetMod(modShortName string) *modconfig.Mod {
 			return depMod
 		}
 	}
-	return nil
+	// the mod may have been loaded as a dependency without being recorded in the install cache
+	// - only use it if it is a declared dependency of the current mod
+	depMod := m.getDependencyModByShortName(modShortName)
+	if depMod == nil || m.CurrentMod.Require == nil || m.CurrentMod.Require.GetModDependency(depMod.DependencyName) == nil {
+		return nil
+	}
+	return depMod
 }
 
 func (m *ModParseContext) GetResourceMaps() *modconfig.ResourceMaps {
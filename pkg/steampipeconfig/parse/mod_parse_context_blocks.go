@@ -11,10 +11,13 @@ import (
 )
 
 func (m *ModParseContext) DetermineBlockName(block *hcl.Block) string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
 	var shortName string
 
 	// have we cached a name for this block (i.e. is this the second decode pass)
-	if name, ok := m.GetCachedBlockShortName(block); ok {
+	if name, ok := m.getCachedBlockShortName(block); ok {
 		return name
 	}
 
@@ -36,11 +39,21 @@ func (m *ModParseContext) DetermineBlockName(block *hcl.Block) string {
 }
 
 func (m *ModParseContext) GetCachedBlockName(block *hcl.Block) (string, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
 	name, ok := m.blockNameMap[m.blockHash(block)]
 	return name, ok
 }
 
 func (m *ModParseContext) GetCachedBlockShortName(block *hcl.Block) (string, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.getCachedBlockShortName(block)
+}
+
+func (m *ModParseContext) getCachedBlockShortName(block *hcl.Block) (string, bool) {
 	unqualifiedName, ok := m.blockNameMap[m.blockHash(block)]
 	if ok {
 		parsedName, err := modconfig.ParseResourceName(unqualifiedName)
@@ -0,0 +1,113 @@
+package parse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	filehelpers "github.com/turbot/go-kit/files"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/versionmap"
+)
+
+const testModPath = "/tmp/parse_test_mod"
+
+// parseTestMod parses the given hcl source into a default mod
+// variables are loaded in an initial pass and their default values added to the eval context,
+// mirroring the way LoadMod parses a workspace
+func parseTestMod(t *testing.T, src string) (*modconfig.Mod, error_helpers.ErrorAndWarnings) {
+	t.Helper()
+	return parseTestModWithFlags(t, src, CreateDefaultMod)
+}
+
+// parseTestModWithFlags parses the given hcl source into a default mod, using the given parse flags
+func parseTestModWithFlags(t *testing.T, src string, flags ParseModFlag) (*modconfig.Mod, error_helpers.ErrorAndWarnings) {
+	t.Helper()
+	fileData := map[string][]byte{testModPath + "/test.sp": []byte(src)}
+
+	// first pass - load variable definitions only
+	variableParseCtx := newTestModParseContext(t)
+	variableParseCtx.BlockTypes = []string{modconfig.BlockTypeVariable}
+	variableMod, res := ParseMod(context.Background(), fileData, nil, variableParseCtx)
+	if res.Error != nil {
+		return nil, res
+	}
+
+	// second pass - parse everything using the variable values
+	parseCtx := newTestModParseContextWithFlags(t, flags)
+	parseCtx.AddInputVariableValues(modconfig.NewModVariableMap(variableMod))
+	return ParseMod(context.Background(), fileData, nil, parseCtx)
+}
+
+// decodeSingleControl parses the given hcl source and returns the control named c1
+// an error is returned if the source fails to parse or does not define the control
+func decodeSingleControl(t *testing.T, src string) (*modconfig.Control, error) {
+	t.Helper()
+	mod, res := parseTestMod(t, src)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	control := mod.ResourceMaps.Controls["local.control.c1"]
+	if control == nil {
+		return nil, fmt.Errorf("control local.control.c1 not found")
+	}
+	return control, nil
+}
+
+// checkDecodeError reports a failure of the named test if err does not match expectedError
+// (an empty expectedError meaning no error is expected)
+// it returns whether the decode succeeded as expected, i.e. whether the decoded resource should be checked
+func checkDecodeError(t *testing.T, name string, err error, expectedError string) bool {
+	t.Helper()
+	if expectedError != "" {
+		if err == nil || !strings.Contains(err.Error(), expectedError) {
+			t.Errorf("Test %s FAILED. Expected error containing '%s', got %v", name, expectedError, err)
+		}
+		return false
+	}
+	if err != nil {
+		t.Errorf("Test %s FAILED with unexpected error: %v", name, err)
+		return false
+	}
+	return true
+}
+
+func newTestModParseContext(t *testing.T) *ModParseContext {
+	t.Helper()
+	return newTestModParseContextWithFlags(t, CreateDefaultMod)
+}
+
+func newTestModParseContextWithFlags(t *testing.T, flags ParseModFlag) *ModParseContext {
+	t.Helper()
+	workspaceLock := versionmap.EmptyWorkspaceLock(&versionmap.WorkspaceLock{WorkspacePath: testModPath})
+	parseCtx := NewModParseContext(workspaceLock, testModPath, flags, &filehelpers.ListOptions{})
+	if err := parseCtx.SetCurrentMod(modconfig.CreateDefaultMod(testModPath)); err != nil {
+		t.Fatal(err)
+	}
+	return parseCtx
+}
+
+// parseTestDependencyMod parses the given hcl source into a mod with the given short name,
+// which may be added to a ModParseContext as a loaded dependency mod
+func parseTestDependencyMod(t *testing.T, shortName, src string) *modconfig.Mod {
+	t.Helper()
+	modPath := testModPath + "/.steampipe/mods/" + shortName
+	mod := modconfig.NewMod(shortName, modPath, hcl.Range{})
+	mod.DependencyName = "github.com/test/" + shortName
+
+	// as for a child mod parse context, the dependency mod is the root of its own eval path
+	workspaceLock := versionmap.EmptyWorkspaceLock(&versionmap.WorkspaceLock{WorkspacePath: modPath})
+	parseCtx := NewModParseContext(workspaceLock, modPath, CreateDefaultMod, &filehelpers.ListOptions{})
+	if err := parseCtx.SetCurrentMod(mod); err != nil {
+		t.Fatal(err)
+	}
+	fileData := map[string][]byte{modPath + "/test.sp": []byte(src)}
+	mod, res := ParseMod(context.Background(), fileData, nil, parseCtx)
+	if res.Error != nil {
+		t.Fatal(res.Error)
+	}
+	return mod
+}
@@ -26,6 +26,9 @@ type ParseContext struct {
 	BlockTypeExclusions []string
 
 	dependencyGraph *topsort.Graph
+	// all dependency edges found while decoding, keyed by the name of the dependent block
+	// NOTE: unlike dependencyGraph, this is not cleared between decode passes
+	dependencyEdges map[string][]string
 	blocks          hcl.Blocks
 }
 
@@ -33,6 +36,7 @@ func NewParseContext(rootEvalPath string) ParseContext {
 	c := ParseContext{
 		UnresolvedBlocks: make(map[string]*unresolvedBlock),
 		RootEvalPath:     rootEvalPath,
+		dependencyEdges:  make(map[string][]string),
 	}
 	// add root node - this will depend on all other nodes
 	c.dependencyGraph = c.newDependencyGraph()
@@ -95,6 +99,9 @@ func (r *ParseContext) AddDependencies(block *hcl.Block, name string, dependenci
 					Summary:  "failed to add dependency to graph",
 					Detail:   err.Error()})
 			}
+			if !helpers.StringSliceContains(r.dependencyEdges[name], dependencyResourceName) {
+				r.dependencyEdges[name] = append(r.dependencyEdges[name], dependencyResourceName)
+			}
 		}
 	}
 	return diags
@@ -129,6 +136,50 @@ func (r *ParseContext) BlocksToDecode() (hcl.Blocks, error) {
 	return blocksToDecode, nil
 }
 
+// DecodeBatches groups the given blocks (as returned by BlocksToDecode) into batches which may be decoded concurrently.
+// No block depends on a block in the same or a later batch.
+// NOTE: this uses the current unresolved blocks so must be called before dependencies are cleared
+func (r *ParseContext) DecodeBatches(blocks hcl.Blocks) ([]hcl.Blocks, error) {
+	// build map of the names of the unresolved resources defined by each block
+	// (a block may define more than one unresolved resource, e.g a locals block)
+	blockResources := make(map[*hcl.Block][]*unresolvedBlock)
+	for _, b := range r.UnresolvedBlocks {
+		blockResources[b.Block] = append(blockResources[b.Block], b)
+	}
+
+	// the batch index of each resolved resource, keyed by resource name
+	resourceBatch := make(map[string]int)
+	var batches []hcl.Blocks
+	for _, block := range blocks {
+		// blocks are in dependency order, so all dependencies of this block have already been assigned a batch
+		// - this block must go in the batch following the latest batch of any of its dependencies
+		batchIdx := 0
+		for _, b := range blockResources[block] {
+			for _, dep := range b.Dependencies {
+				for _, t := range dep.Traversals {
+					propertyPath, err := modconfig.ParseResourcePropertyPath(hclhelpers.TraversalAsString(t))
+					if err != nil {
+						return nil, err
+					}
+					depName := modconfig.BuildModResourceName(propertyPath.ItemType, propertyPath.Name)
+					if depBatch, ok := resourceBatch[depName]; ok && depBatch >= batchIdx {
+						batchIdx = depBatch + 1
+					}
+				}
+			}
+		}
+		for _, b := range blockResources[block] {
+			resourceBatch[b.Name] = batchIdx
+		}
+
+		if batchIdx == len(batches) {
+			batches = append(batches, hcl.Blocks{})
+		}
+		batches[batchIdx] = append(batches[batchIdx], block)
+	}
+	return batches, nil
+}
+
 // EvalComplete returns whether all elements in the dependency tree fully evaluated
 func (r *ParseContext) EvalComplete() bool {
 	return len(r.UnresolvedBlocks) == 0
@@ -202,6 +202,10 @@ var DashboardBlockSchema = &hcl.BodySchema{
 		{
 			Type: modconfig.BlockTypeText,
 		},
+		{
+			Type:       modconfig.BlockTypeLabels,
+			LabelNames: []string{"locale"},
+		},
 	},
 }
 
@@ -255,10 +259,18 @@ var DashboardContainerBlockSchema = &hcl.BodySchema{
 var BenchmarkBlockSchema = &hcl.BodySchema{
 	Attributes: []hcl.AttributeSchema{
 		{Name: "children"},
+		{Name: "child_order"},
+		{Name: "deprecated"},
 		{Name: "description"},
 		{Name: "documentation"},
+		{Name: "exclude"},
+		{Name: "if"},
+		{Name: "include"},
+		{Name: "min_severity"},
+		{Name: "required_severity"},
 		{Name: "tags"},
 		{Name: "title"},
+		{Name: "weight"},
 		// for report benchmark blocks
 		{Name: "width"},
 		{Name: "base"},
@@ -318,6 +330,7 @@ var ParamDefBlockSchema = &hcl.BodySchema{
 	Attributes: []hcl.AttributeSchema{
 		{Name: "description"},
 		{Name: "default"},
+		{Name: "type"},
 	},
 }
 
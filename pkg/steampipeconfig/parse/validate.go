@@ -2,8 +2,15 @@ package parse
 
 import (
 	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/utils"
 )
 
 // validate the resource
@@ -122,3 +129,240 @@ func validateSqlAndQueryNotBothSet(resource modconfig.QueryProvider) hcl.Diagnos
 	}
 	return diags
 }
+
+// validateVariableReferences verifies that every 'var' reference in the unresolved blocks
+// refers to a declared variable of the current mod
+// (a reference to an undeclared variable can never be resolved, so report it precisely
+// rather than as a generic unresolved dependency)
+func validateVariableReferences(parseCtx *ModParseContext) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	declaredVariables := make(map[string]struct{})
+	if parseCtx.Variables != nil {
+		for name := range parseCtx.Variables.RootVariables {
+			declaredVariables[name] = struct{}{}
+		}
+	}
+	for _, v := range parseCtx.CurrentMod.ResourceMaps.Variables {
+		declaredVariables[v.ShortName] = struct{}{}
+	}
+
+	// only report each undeclared reference once
+	reported := make(map[string]struct{})
+	for _, block := range parseCtx.UnresolvedBlocks {
+		for _, dep := range block.Dependencies {
+			for _, traversal := range dep.Traversals {
+				varName, ok := variableNameFromTraversal(traversal)
+				if !ok {
+					continue
+				}
+				if _, declared := declaredVariables[varName]; declared {
+					continue
+				}
+				subject := traversal.SourceRange()
+				key := subject.String()
+				if _, ok := reported[key]; ok {
+					continue
+				}
+				reported[key] = struct{}{}
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("variable '%s' is not declared", varName),
+					Detail:   fmt.Sprintf("A variable named '%s' must be declared in the mod before it can be referenced.", varName),
+					Subject:  &subject,
+				})
+			}
+		}
+	}
+	return diags
+}
+
+// variableNameFromTraversal returns the variable name if the traversal is a root mod variable reference (var.<name>)
+func variableNameFromTraversal(traversal hcl.Traversal) (string, bool) {
+	if len(traversal) < 2 || traversal.RootName() != "var" {
+		return "", false
+	}
+	attr, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", false
+	}
+	return attr.Name, true
+}
+
+// return a warning for each reference from a resource which is not deprecated to a child which is deprecated
+func validateDeprecatedReferences(mod *modconfig.Mod) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	resourceFunc := func(resource modconfig.HclResource) (bool, error) {
+		treeItem, ok := resource.(modconfig.ModTreeItem)
+		if !ok || resource.GetHclResourceImpl().IsDeprecated() {
+			return true, nil
+		}
+		for _, child := range treeItem.GetChildren() {
+			childImpl := child.(modconfig.HclResource).GetHclResourceImpl()
+			if !childImpl.IsDeprecated() {
+				continue
+			}
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  fmt.Sprintf("%s references deprecated resource %s", resource.Name(), child.Name()),
+				Detail:   childImpl.GetDeprecationMessage(),
+				Subject:  resource.GetDeclRange(),
+			})
+		}
+		return true, nil
+	}
+	// resourceFunc does not return an error
+	_ = mod.ResourceMaps.WalkResources(resourceFunc)
+	return diags
+}
+
+// return a warning for each benchmark in the mod which (recursively) contains no controls
+// such benchmarks are dropped from the execution tree at runtime so would otherwise be silently ignored
+func validateEmptyBenchmarks(mod *modconfig.Mod) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, benchmark := range mod.ResourceMaps.Benchmarks {
+		// only validate benchmarks defined in this mod
+		if benchmark.Mod != mod {
+			continue
+		}
+		if len(benchmark.GetChildControls()) > 0 {
+			continue
+		}
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  fmt.Sprintf("%s contains no controls", benchmark.Name()),
+			Subject:  benchmark.GetDeclRange(),
+		})
+	}
+	sortDiagnostics(diags)
+	return diags
+}
+
+// validateDuplicateControlSql returns a warning for each control of the mod whose query content hash (normalised sql,
+// args and params) is identical to that of another control - this is usually the result of a copy-paste error
+// controls which inherit their sql from a base (or reference a named query) are excluded, as sharing is intentional
+// for each set of duplicates, the control which sorts first by name is not reported
+func validateDuplicateControlSql(mod *modconfig.Mod) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	controlsBySqlHash := make(map[string][]*modconfig.Control)
+	for _, control := range mod.ResourceMaps.Controls {
+		// only validate controls defined in this mod
+		if control.Mod != mod || control.SQL == nil {
+			continue
+		}
+		if control.Base != nil && utils.SafeStringsEqual(control.SQL, control.Base.SQL) {
+			continue
+		}
+		hash := modconfig.QueryContentHash(control)
+		controlsBySqlHash[hash] = append(controlsBySqlHash[hash], control)
+	}
+	for _, controls := range controlsBySqlHash {
+		if len(controls) < 2 {
+			continue
+		}
+		sort.Slice(controls, func(i, j int) bool {
+			return controls[i].Name() < controls[j].Name()
+		})
+		for _, control := range controls[1:] {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  fmt.Sprintf("%s has the same sql as %s (possible duplication)", control.Name(), controls[0].Name()),
+				Subject:  control.GetDeclRange(),
+			})
+		}
+	}
+	sortDiagnostics(diags)
+	return diags
+}
+
+// return a warning for each control whose severity does not meet the min_severity or required_severity
+// of a benchmark (defined in this mod) which contains it
+func validateBenchmarkSeverities(mod *modconfig.Mod) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	for _, benchmark := range mod.ResourceMaps.Benchmarks {
+		// only validate benchmarks defined in this mod
+		if benchmark.Mod != mod {
+			continue
+		}
+		diags = append(diags, benchmark.ValidateControlSeverities()...)
+	}
+	sortDiagnostics(diags)
+	return diags
+}
+
+// return a warning for each resource in the mod whose documentation is a malformed link
+// documentation is only validated if it is URL-shaped, i.e. a single http(s) token - markdown documentation is ignored
+// if checkReachable is set, a warning is also returned for each well formed link which cannot be reached
+func validateDocumentationLinks(mod *modconfig.Mod, checkReachable bool) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	resourceFunc := func(resource modconfig.HclResource) (bool, error) {
+		treeItem, ok := resource.(modconfig.ModTreeItem)
+		// only validate resources defined in this mod
+		if !ok || treeItem.GetMod() != mod {
+			return true, nil
+		}
+		link := strings.TrimSpace(treeItem.GetDocumentation())
+		if !isDocumentationLink(link) {
+			return true, nil
+		}
+		if err := validateDocumentationLink(link); err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  fmt.Sprintf("%s has a malformed documentation link '%s'", resource.Name(), link),
+				Detail:   err.Error(),
+				Subject:  resource.GetDeclRange(),
+			})
+			return true, nil
+		}
+		if !checkReachable {
+			return true, nil
+		}
+		if err := checkDocumentationLinkReachable(link); err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  fmt.Sprintf("%s has an unreachable documentation link '%s'", resource.Name(), link),
+				Detail:   err.Error(),
+				Subject:  resource.GetDeclRange(),
+			})
+		}
+		return true, nil
+	}
+	// resourceFunc does not return an error
+	_ = mod.ResourceMaps.WalkResources(resourceFunc)
+	sortDiagnostics(diags)
+	return diags
+}
+
+// isDocumentationLink returns whether the documentation is URL-shaped, i.e. a single token starting with 'http'
+func isDocumentationLink(documentation string) bool {
+	return strings.HasPrefix(strings.ToLower(documentation), "http") && !strings.ContainsAny(documentation, " \t\n")
+}
+
+// validateDocumentationLink checks the syntax of the link - it must be an absolute http(s) url with a host
+func validateDocumentationLink(link string) error {
+	u, err := url.ParseRequestURI(link)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme '%s' - scheme must be http or https", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("link has no host")
+	}
+	return nil
+}
+
+// checkDocumentationLinkReachable makes a HEAD request to the link and returns an error if it fails
+func checkDocumentationLinkReachable(link string) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Head(link)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("request returned status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package steampipeconfig
+
+import (
+	"sort"
+
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// ReferencedConnections returns the sorted, distinct names of the connections declared by the controls of the mod
+// this may be used to validate the required connections exist before executing the mod
+func ReferencedConnections(mod *modconfig.Mod) []string {
+	res := []string{}
+	if mod == nil || mod.ResourceMaps == nil {
+		return res
+	}
+	connections := make(map[string]struct{})
+	for _, control := range mod.ResourceMaps.Controls {
+		if control.Connection == nil {
+			continue
+		}
+		if _, ok := connections[*control.Connection]; ok {
+			continue
+		}
+		connections[*control.Connection] = struct{}{}
+		res = append(res, *control.Connection)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,46 @@
+package steampipeconfig
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+type referencedConnectionsTest struct {
+	connections []*string
+	expected    []string
+}
+
+var testCasesReferencedConnections = map[string]referencedConnectionsTest{
+	"distinct connections": {
+		connections: []*string{utils.ToStringPointer("aws_prod"), utils.ToStringPointer("aws_dev"), utils.ToStringPointer("aws_prod"), nil},
+		expected:    []string{"aws_dev", "aws_prod"},
+	},
+	"no connections": {
+		connections: []*string{nil, nil},
+		expected:    []string{},
+	},
+	"no controls": {
+		expected: []string{},
+	},
+}
+
+func TestReferencedConnections(t *testing.T) {
+	for name, test := range testCasesReferencedConnections {
+		mod := modconfig.NewMod("test", "", hcl.Range{})
+		for i, connection := range test.connections {
+			shortName := string(rune('a' + i))
+			control := modconfig.NewControl(&hcl.Block{Type: modconfig.BlockTypeControl, Labels: []string{shortName}}, mod, shortName).(*modconfig.Control)
+			control.Connection = connection
+			if diags := mod.AddResource(control); diags.HasErrors() {
+				t.Fatalf("failed to add resource %s: %s", control.Name(), diags.Error())
+			}
+		}
+		if connections := ReferencedConnections(mod); !reflect.DeepEqual(connections, test.expected) {
+			t.Errorf("Test %s FAILED. Expected %v, got %v", name, test.expected, connections)
+		}
+	}
+}
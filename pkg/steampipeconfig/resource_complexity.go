@@ -0,0 +1,100 @@
+package steampipeconfig
+
+import (
+	"fmt"
+	"sort"
+
+	typehelpers "github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+const (
+	ComplexityMetricPanelCount = "panel count"
+	ComplexityMetricSqlLength  = "sql length"
+	ComplexityMetricParamCount = "param count"
+)
+
+// ComplexityThresholds defines the limits above which a resource is reported as overly complex
+// a threshold of zero is not checked
+type ComplexityThresholds struct {
+	// the maximum number of panels in a dashboard, including panels nested in containers
+	MaxPanels int
+	// the maximum length of the SQL of a query provider
+	MaxSqlLength int
+	// the maximum number of params of a query provider
+	MaxParams int
+}
+
+// DefaultComplexityThresholds are the thresholds used if none are configured
+var DefaultComplexityThresholds = ComplexityThresholds{
+	MaxPanels:    50,
+	MaxSqlLength: 10000,
+	MaxParams:    20,
+}
+
+// ComplexityViolation describes a resource which exceeds a complexity threshold
+type ComplexityViolation struct {
+	Resource  string
+	Metric    string
+	Value     int
+	Threshold int
+}
+
+func (v ComplexityViolation) String() string {
+	return fmt.Sprintf("%s has %s %d which exceeds the threshold of %d", v.Resource, v.Metric, v.Value, v.Threshold)
+}
+
+// FindComplexResources returns a violation for each threshold exceeded by a resource of the mod,
+// sorted by resource name and metric
+func FindComplexResources(mod *modconfig.Mod, thresholds ComplexityThresholds) []ComplexityViolation {
+	var res []ComplexityViolation
+	if mod == nil || mod.ResourceMaps == nil {
+		return res
+	}
+	check := func(resource modconfig.HclResource, metric string, value, threshold int) {
+		if threshold > 0 && value > threshold {
+			res = append(res, ComplexityViolation{
+				Resource:  resource.Name(),
+				Metric:    metric,
+				Value:     value,
+				Threshold: threshold,
+			})
+		}
+	}
+
+	// the walk function never returns an error
+	_ = mod.ResourceMaps.WalkResources(func(item modconfig.HclResource) (bool, error) {
+		if dashboard, ok := item.(*modconfig.Dashboard); ok {
+			check(item, ComplexityMetricPanelCount, dashboardPanelCount(dashboard), thresholds.MaxPanels)
+		}
+		if qp, ok := item.(modconfig.QueryProvider); ok {
+			check(item, ComplexityMetricSqlLength, len(typehelpers.SafeString(qp.GetSQL())), thresholds.MaxSqlLength)
+			check(item, ComplexityMetricParamCount, len(qp.GetParams()), thresholds.MaxParams)
+		}
+		return true, nil
+	})
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Resource != res[j].Resource {
+			return res[i].Resource < res[j].Resource
+		}
+		return res[i].Metric < res[j].Metric
+	})
+	return res
+}
+
+// dashboardPanelCount returns the number of panels in the dashboard, including containers and nested panels
+// inputs and 'with' blocks are not panels
+func dashboardPanelCount(dashboard *modconfig.Dashboard) int {
+	count := 0
+	// the walk function never returns an error
+	_ = dashboard.WalkResources(func(resource modconfig.HclResource) (bool, error) {
+		switch resource.(type) {
+		case *modconfig.DashboardInput, *modconfig.DashboardWith:
+		default:
+			count++
+		}
+		return true, nil
+	})
+	return count
+}
@@ -0,0 +1,81 @@
+package steampipeconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+type resourceComplexityTest struct {
+	thresholds ComplexityThresholds
+	expected   []string
+}
+
+var testCasesResourceComplexity = map[string]resourceComplexityTest{
+	"default thresholds": {
+		thresholds: DefaultComplexityThresholds,
+		expected:   nil,
+	},
+	"panel count exceeded": {
+		thresholds: ComplexityThresholds{MaxPanels: 2},
+		expected:   []string{"test.dashboard.d1 has panel count 3 which exceeds the threshold of 2"},
+	},
+	"sql length and param count exceeded": {
+		thresholds: ComplexityThresholds{MaxSqlLength: 20, MaxParams: 1},
+		expected: []string{
+			"test.control.c2 has param count 2 which exceeds the threshold of 1",
+			"test.control.c2 has sql length 40 which exceeds the threshold of 20",
+		},
+	},
+	"thresholds equal to values": {
+		thresholds: ComplexityThresholds{MaxPanels: 3, MaxSqlLength: 40, MaxParams: 2},
+		expected:   nil,
+	},
+}
+
+func TestFindComplexResources(t *testing.T) {
+	mod := newComplexityTestMod(t)
+	for name, test := range testCasesResourceComplexity {
+		var violations []string
+		for _, v := range FindComplexResources(mod, test.thresholds) {
+			violations = append(violations, v.String())
+		}
+		if !reflect.DeepEqual(violations, test.expected) {
+			t.Errorf("Test %s FAILED. Expected %v, got %v", name, test.expected, violations)
+		}
+	}
+}
+
+// newComplexityTestMod creates a mod with a dashboard containing 3 panels (and an input),
+// a simple control and a control with 2 params and 40 characters of SQL
+func newComplexityTestMod(t *testing.T) *modconfig.Mod {
+	mod := modconfig.NewMod("test", "", hcl.Range{})
+
+	dashboard := modconfig.NewDashboard(&hcl.Block{Type: modconfig.BlockTypeDashboard, Labels: []string{"d1"}}, mod, "d1").(*modconfig.Dashboard)
+	dashboard.AddChild(modconfig.NewDashboardInput(&hcl.Block{Type: modconfig.BlockTypeInput, Labels: []string{"i1"}}, mod, "i1").(*modconfig.DashboardInput))
+	container := modconfig.NewDashboardContainer(&hcl.Block{Type: modconfig.BlockTypeContainer}, mod, "container_1").(*modconfig.DashboardContainer)
+	container.AddChild(modconfig.NewDashboardCard(&hcl.Block{Type: modconfig.BlockTypeCard, Labels: []string{"card1"}}, mod, "card1").(*modconfig.DashboardCard))
+	container.AddChild(modconfig.NewDashboardCard(&hcl.Block{Type: modconfig.BlockTypeCard, Labels: []string{"card2"}}, mod, "card2").(*modconfig.DashboardCard))
+	dashboard.AddChild(container)
+
+	c1 := modconfig.NewControl(&hcl.Block{Type: modconfig.BlockTypeControl, Labels: []string{"c1"}}, mod, "c1").(*modconfig.Control)
+	c1.SQL = utils.ToStringPointer("select 1")
+
+	c2 := modconfig.NewControl(&hcl.Block{Type: modconfig.BlockTypeControl, Labels: []string{"c2"}}, mod, "c2").(*modconfig.Control)
+	c2.SQL = utils.ToStringPointer("select $1 as a, $2 as b " + strings.Repeat("-", 16))
+	c2.Params = []*modconfig.ParamDef{
+		modconfig.NewParamDef(&hcl.Block{Type: modconfig.BlockTypeParam, Labels: []string{"p1"}}),
+		modconfig.NewParamDef(&hcl.Block{Type: modconfig.BlockTypeParam, Labels: []string{"p2"}}),
+	}
+
+	for _, r := range []modconfig.HclResource{dashboard, c1, c2} {
+		if diags := mod.AddResource(r); diags.HasErrors() {
+			t.Fatalf("failed to add resource %s: %s", r.Name(), diags.Error())
+		}
+	}
+	return mod
+}
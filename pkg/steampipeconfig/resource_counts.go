@@ -0,0 +1,20 @@
+package steampipeconfig
+
+import (
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// ResourceCounts returns the number of resources of each type defined by the mod, keyed by block type
+// NOTE: this includes nested (e.g. dashboard child) resources
+func ResourceCounts(mod *modconfig.Mod) map[string]int {
+	res := make(map[string]int)
+	if mod == nil || mod.ResourceMaps == nil {
+		return res
+	}
+	// the walk function never returns an error
+	_ = mod.ResourceMaps.WalkResources(func(item modconfig.HclResource) (bool, error) {
+		res[item.BlockType()]++
+		return true, nil
+	})
+	return res
+}
@@ -0,0 +1,35 @@
+package steampipeconfig
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+func TestResourceCounts(t *testing.T) {
+	mod := modconfig.NewMod("test", "", hcl.Range{})
+	resources := []modconfig.HclResource{
+		modconfig.NewControl(&hcl.Block{Type: modconfig.BlockTypeControl, Labels: []string{"c1"}}, mod, "c1"),
+		modconfig.NewControl(&hcl.Block{Type: modconfig.BlockTypeControl, Labels: []string{"c2"}}, mod, "c2"),
+		modconfig.NewBenchmark(&hcl.Block{Type: modconfig.BlockTypeBenchmark, Labels: []string{"b1"}}, mod, "b1"),
+		modconfig.NewDashboard(&hcl.Block{Type: modconfig.BlockTypeDashboard, Labels: []string{"d1"}}, mod, "d1"),
+		modconfig.NewQuery(&hcl.Block{Type: modconfig.BlockTypeQuery, Labels: []string{"q1"}}, mod, "q1"),
+	}
+	for _, r := range resources {
+		if diags := mod.AddResource(r); diags.HasErrors() {
+			t.Fatalf("failed to add resource %s: %s", r.Name(), diags.Error())
+		}
+	}
+
+	expected := map[string]int{
+		modconfig.BlockTypeControl:   2,
+		modconfig.BlockTypeBenchmark: 1,
+		modconfig.BlockTypeDashboard: 1,
+		modconfig.BlockTypeQuery:     1,
+	}
+	if counts := ResourceCounts(mod); !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected %v, got %v", expected, counts)
+	}
+}
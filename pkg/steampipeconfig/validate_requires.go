@@ -0,0 +1,76 @@
+package steampipeconfig
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// ValidateRequires validates the plugin version constraints in the mod require block against the installed plugin versions,
+// returning a diagnostic for each requirement which is not satisfied
+func ValidateRequires(mod *modconfig.Mod, installed map[string]*versionfile.InstalledVersion) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	if mod == nil || mod.Require == nil {
+		return nil
+	}
+
+	installedPlugins := installedPluginVersions(installed)
+	for _, requirement := range mod.Require.Plugins {
+		if err := populatePluginRequirementConstraint(requirement); err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("Invalid version constraint '%s' for plugin %s", requirement.MinVersionString, requirement.RawName),
+				Detail:   err.Error(),
+				Subject:  &requirement.DeclRange,
+			})
+			continue
+		}
+
+		if !requirement.SatisfiedBy(installedPlugins) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  fmt.Sprintf("could not find plugin which satisfies requirement '%s@%s' - required by '%s'", requirement.RawName, requirement.MinVersionString, mod.Name()),
+				Subject:  &requirement.DeclRange,
+			})
+		}
+	}
+	return diags
+}
+
+// populate the constraint of the requirement - this will already be populated if the requirement was decoded,
+// otherwise parse the min version string as a constraint
+func populatePluginRequirementConstraint(requirement *modconfig.PluginVersion) error {
+	if requirement.Constraint != nil {
+		return nil
+	}
+	constraintString := requirement.MinVersionString
+	if constraintString == "" {
+		// no version constraint - any version is acceptable
+		constraintString = "*"
+	}
+	constraint, err := semver.NewConstraint(constraintString)
+	if err != nil {
+		return err
+	}
+	requirement.Constraint = constraint
+	return nil
+}
+
+// convert the installed versions into plugin version strings, ignoring nil entries and invalid versions
+func installedPluginVersions(installed map[string]*versionfile.InstalledVersion) map[string]*modconfig.PluginVersionString {
+	res := make(map[string]*modconfig.PluginVersionString, len(installed))
+	for installedName, installedVersion := range installed {
+		if installedVersion == nil {
+			continue
+		}
+		version, err := modconfig.NewPluginVersionString(installedVersion.Version)
+		if err != nil {
+			continue
+		}
+		res[installedName] = version
+	}
+	return res
+}
@@ -0,0 +1,93 @@
+package steampipeconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/pkg/ociinstaller/versionfile"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+type validateRequiresTest struct {
+	requirements  []*modconfig.PluginVersion
+	installed     map[string]*versionfile.InstalledVersion
+	expectedError string
+}
+
+var testInstalledPlugins = map[string]*versionfile.InstalledVersion{
+	"hub.steampipe.io/plugins/turbot/aws@latest": {Name: "hub.steampipe.io/plugins/turbot/aws@latest", Version: "0.85.0"},
+	"hub.steampipe.io/plugins/turbot/gcp@latest": {Name: "hub.steampipe.io/plugins/turbot/gcp@latest", Version: "0.30.1"},
+	"hub.steampipe.io/plugins/turbot/csv@latest": {Name: "hub.steampipe.io/plugins/turbot/csv@latest", Version: "local"},
+}
+
+var testCasesValidateRequires = map[string]validateRequiresTest{
+	"satisfied": {
+		requirements: []*modconfig.PluginVersion{
+			{RawName: "aws", Org: "turbot", Name: "aws", MinVersionString: ">= 0.80"},
+			{RawName: "gcp", Org: "turbot", Name: "gcp", MinVersionString: "~0.30"},
+		},
+		installed: testInstalledPlugins,
+	},
+	"no version constraint": {
+		requirements: []*modconfig.PluginVersion{
+			{RawName: "gcp", Org: "turbot", Name: "gcp"},
+		},
+		installed: testInstalledPlugins,
+	},
+	"local plugin": {
+		requirements: []*modconfig.PluginVersion{
+			{RawName: "csv", Org: "turbot", Name: "csv", MinVersionString: ">= 1.0"},
+		},
+		installed: testInstalledPlugins,
+	},
+	"version not satisfied": {
+		requirements: []*modconfig.PluginVersion{
+			{RawName: "aws", Org: "turbot", Name: "aws", MinVersionString: ">= 0.90"},
+		},
+		installed:     testInstalledPlugins,
+		expectedError: "could not find plugin which satisfies requirement 'aws@>= 0.90'",
+	},
+	"plugin not installed": {
+		requirements: []*modconfig.PluginVersion{
+			{RawName: "azure", Org: "turbot", Name: "azure", MinVersionString: ">= 0.1"},
+		},
+		installed:     testInstalledPlugins,
+		expectedError: "could not find plugin which satisfies requirement 'azure@>= 0.1'",
+	},
+	"nil installed version": {
+		requirements: []*modconfig.PluginVersion{
+			{RawName: "aws", Org: "turbot", Name: "aws", MinVersionString: ">= 0.80"},
+		},
+		installed: map[string]*versionfile.InstalledVersion{
+			"hub.steampipe.io/plugins/turbot/aws@0.70":   nil,
+			"hub.steampipe.io/plugins/turbot/aws@latest": {Name: "hub.steampipe.io/plugins/turbot/aws@latest", Version: "0.85.0"},
+		},
+	},
+	"invalid constraint": {
+		requirements: []*modconfig.PluginVersion{
+			{RawName: "aws", Org: "turbot", Name: "aws", MinVersionString: "not a version"},
+		},
+		installed:     testInstalledPlugins,
+		expectedError: "Invalid version constraint 'not a version' for plugin aws",
+	},
+}
+
+func TestValidateRequires(t *testing.T) {
+	for name, test := range testCasesValidateRequires {
+		mod := modconfig.NewMod("test", "", hcl.Range{})
+		mod.Require = modconfig.NewRequire()
+		mod.Require.Plugins = test.requirements
+
+		diags := ValidateRequires(mod, test.installed)
+		if test.expectedError == "" {
+			if diags.HasErrors() {
+				t.Errorf("Test %s FAILED with unexpected error: %s", name, diags.Error())
+			}
+			continue
+		}
+		if !diags.HasErrors() || !strings.Contains(diags.Error(), test.expectedError) {
+			t.Errorf("Test %s FAILED. Expected error containing '%s', got '%s'", name, test.expectedError, diags.Error())
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package utils
+
+import "strings"
+
+// NormaliseSqlWhitespace collapses each run of whitespace which is not inside a string literal, quoted identifier,
+// dollar-quoted string or comment into a single space, and removes any trailing semicolons
+// A run of whitespace which ends a line comment is collapsed to a single newline, so the comment is not extended
+// If the sql cannot be tokenised it is returned trimmed but otherwise unchanged
+func NormaliseSqlWhitespace(sql string) string {
+	var normalised strings.Builder
+	pendingWhitespace := ""
+	for i := 0; i < len(sql); {
+		if isSqlWhitespace(sql[i]) {
+			if pendingWhitespace == "" {
+				pendingWhitespace = " "
+			}
+			i++
+			continue
+		}
+		end, err := skipSqlSpan(sql, i)
+		if err != nil {
+			return strings.TrimSpace(sql)
+		}
+		if end == i {
+			end++
+		}
+		if normalised.Len() > 0 {
+			normalised.WriteString(pendingWhitespace)
+		}
+		pendingWhitespace = ""
+		normalised.WriteString(sql[i:end])
+		if strings.HasPrefix(sql[i:], "--") {
+			pendingWhitespace = "\n"
+		}
+		i = end
+	}
+	return strings.TrimRight(normalised.String(), "; \n")
+}
+
+func isSqlWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
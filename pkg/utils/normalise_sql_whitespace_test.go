@@ -0,0 +1,52 @@
+package utils
+
+import "testing"
+
+type normaliseSqlWhitespaceTest struct {
+	sql      string
+	expected string
+}
+
+var testCasesNormaliseSqlWhitespace = map[string]normaliseSqlWhitespaceTest{
+	"collapse whitespace": {
+		sql:      "\n  select\n\ta,  b\r\n  from t\n",
+		expected: "select a, b from t",
+	},
+	"trailing semicolon": {
+		sql:      "select 1 ;\n",
+		expected: "select 1",
+	},
+	"whitespace in string literal": {
+		sql:      "select  'a  b',  \"c  d\"",
+		expected: "select 'a  b', \"c  d\"",
+	},
+	"whitespace in dollar quote": {
+		sql:      "select  $$a  b$$",
+		expected: "select $$a  b$$",
+	},
+	"whitespace in escape string": {
+		sql:      `select  E'a\'  b'`,
+		expected: `select E'a\'  b'`,
+	},
+	"line comment": {
+		sql:      "select 1 -- a  comment\n\n  , 2",
+		expected: "select 1 -- a  comment\n, 2",
+	},
+	"block comment": {
+		sql:      "select /* a\n  b */  1",
+		expected: "select /* a\n  b */ 1",
+	},
+	"unterminated string": {
+		sql:      "  select  'a ",
+		expected: "select  'a",
+	},
+}
+
+func TestNormaliseSqlWhitespace(t *testing.T) {
+	for name, test := range testCasesNormaliseSqlWhitespace {
+		normalised := NormaliseSqlWhitespace(test.sql)
+		if normalised != test.expected {
+			t.Errorf("Test %s FAILED. Expected %q, got %q", name, test.expected, normalised)
+		}
+	}
+}
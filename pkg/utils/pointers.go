@@ -9,3 +9,8 @@ func ToStringPointer(s string) *string {
 func ToIntegerPointer(i int) *int {
 	return &i
 }
+
+// ToFloat64Pointer converts a float64 into its pointer
+func ToFloat64Pointer(f float64) *float64 {
+	return &f
+}
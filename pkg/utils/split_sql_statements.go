@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// a dollar quote tag is $$ or $tag$ where tag follows the rules for an unquoted identifier
+var dollarQuoteTagRegex = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)?\$`)
+
+// SplitSqlStatements splits the given sql into its constituent statements, splitting on semicolons
+// which are not inside string literals, quoted identifiers, dollar-quoted strings or comments
+// Empty statements are discarded
+func SplitSqlStatements(sql string) ([]string, error) {
+	var statements []string
+	var current strings.Builder
+
+	addStatement := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(sql); {
+		if sql[i] == ';' {
+			addStatement()
+			i++
+			continue
+		}
+		end, err := skipSqlSpan(sql, i)
+		if err != nil {
+			return nil, err
+		}
+		if end == i {
+			end++
+		}
+		current.WriteString(sql[i:end])
+		i = end
+	}
+	addStatement()
+
+	return statements, nil
+}
+
+// skipSqlSpan returns the end position of the string literal, quoted identifier, dollar-quoted string
+// or comment starting at position i
+// If no such span starts at position i, i is returned
+func skipSqlSpan(sql string, i int) (int, error) {
+	c := sql[i]
+	switch {
+	case c == '\'' || c == '"':
+		// string literal or quoted identifier - a doubled quote is an escaped quote
+		// in an escape string constant (E'...') a backslash also escapes the following character
+		escapeString := c == '\'' && isEscapeStringPrefix(sql, i)
+		end := i + 1
+		for {
+			idx := strings.IndexByte(sql[end:], c)
+			if escapeString {
+				if backslashIdx := strings.IndexByte(sql[end:], '\\'); backslashIdx != -1 && (idx == -1 || backslashIdx < idx) {
+					// skip the backslash and the character it escapes
+					end += backslashIdx + 2
+					continue
+				}
+			}
+			if idx == -1 {
+				return 0, fmt.Errorf("unterminated quoted string starting at position %d", i)
+			}
+			end += idx + 1
+			if end < len(sql) && sql[end] == c {
+				end++
+				continue
+			}
+			return end, nil
+		}
+	case c == '$':
+		if i > 0 && isIdentifierChar(sql[i-1]) {
+			// a dollar sign within an identifier does not start a dollar quote
+			return i, nil
+		}
+		tag := dollarQuoteTagRegex.FindString(sql[i:])
+		if tag == "" {
+			// positional parameter
+			return i, nil
+		}
+		idx := strings.Index(sql[i+len(tag):], tag)
+		if idx == -1 {
+			return 0, fmt.Errorf("unterminated dollar-quoted string %s starting at position %d", tag, i)
+		}
+		return i + len(tag) + idx + len(tag), nil
+	case strings.HasPrefix(sql[i:], "--"):
+		if idx := strings.IndexByte(sql[i:], '\n'); idx != -1 {
+			return i + idx, nil
+		}
+		return len(sql), nil
+	case strings.HasPrefix(sql[i:], "/*"):
+		idx := strings.Index(sql[i+2:], "*/")
+		if idx == -1 {
+			return 0, fmt.Errorf("unterminated block comment starting at position %d", i)
+		}
+		return i + 2 + idx + 2, nil
+	}
+	return i, nil
+}
+
+// isEscapeStringPrefix returns whether the quote at position i is preceded by the E prefix of an escape string constant
+func isEscapeStringPrefix(sql string, i int) bool {
+	if i == 0 || (sql[i-1] != 'E' && sql[i-1] != 'e') {
+		return false
+	}
+	// the E must not be the end of a longer identifier
+	return i == 1 || !isIdentifierChar(sql[i-2])
+}
+
+// isIdentifierChar returns whether c may appear in an unquoted identifier after the first character
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c >= 0x80
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type splitSqlStatementsTest struct {
+	sql           string
+	expected      []string
+	expectedError string
+}
+
+var testCasesSplitSqlStatements = map[string]splitSqlStatementsTest{
+	"single statement": {
+		sql:      "select 1",
+		expected: []string{"select 1"},
+	},
+	"trailing semicolon": {
+		sql:      "select 1;",
+		expected: []string{"select 1"},
+	},
+	"multiple statements": {
+		sql:      "create temp table t as select 1 as a; select a from t;",
+		expected: []string{"create temp table t as select 1 as a", "select a from t"},
+	},
+	"semicolon in string literal": {
+		sql:      "set local my.var = 'a;b'; select 'it''s; ok' as val",
+		expected: []string{"set local my.var = 'a;b'", "select 'it''s; ok' as val"},
+	},
+	"semicolon in quoted identifier": {
+		sql:      `select 1 as "a;b"; select 2`,
+		expected: []string{`select 1 as "a;b"`, "select 2"},
+	},
+	"dollar quoted function body": {
+		sql: `create or replace function pg_temp.f() returns int as $$
+begin
+  return 1;
+end;
+$$ language plpgsql; select pg_temp.f()`,
+		expected: []string{`create or replace function pg_temp.f() returns int as $$
+begin
+  return 1;
+end;
+$$ language plpgsql`, "select pg_temp.f()"},
+	},
+	"tagged dollar quote": {
+		sql:      "select $body$ a; $$ b $body$; select $1",
+		expected: []string{"select $body$ a; $$ b $body$", "select $1"},
+	},
+	"escape string with escaped quote": {
+		sql:      `select E'it\'s; ok', e'a\\'; select 2`,
+		expected: []string{`select E'it\'s; ok', e'a\\'`, "select 2"},
+	},
+	"backslash in standard string": {
+		sql:      `select 'a\'; select 2`,
+		expected: []string{`select 'a\'`, "select 2"},
+	},
+	"identifier ending in e": {
+		sql:      `select name'a\'; select 2`,
+		expected: []string{`select name'a\'`, "select 2"},
+	},
+	"dollar in identifier": {
+		sql:      "select a$b$c from t$x$; select 2",
+		expected: []string{"select a$b$c from t$x$", "select 2"},
+	},
+	"comments": {
+		sql:      "select 1; -- a; comment\nselect /* b; */ 2",
+		expected: []string{"select 1", "-- a; comment\nselect /* b; */ 2"},
+	},
+	"unterminated string": {
+		sql:           "select 'a; select 1",
+		expectedError: "unterminated quoted string",
+	},
+	"unterminated dollar quote": {
+		sql:           "select $$ a; select 1",
+		expectedError: "unterminated dollar-quoted string $$",
+	},
+	"unterminated block comment": {
+		sql:           "select 1; /* select 2",
+		expectedError: "unterminated block comment",
+	},
+}
+
+func TestSplitSqlStatements(t *testing.T) {
+	for name, test := range testCasesSplitSqlStatements {
+		statements, err := SplitSqlStatements(test.sql)
+		if test.expectedError != "" {
+			if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+				t.Errorf("Test %s FAILED. Expected error containing '%s', got %v", name, test.expectedError, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %s FAILED with unexpected error: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(statements, test.expected) {
+			t.Errorf("Test %s FAILED. Expected %q, got %q", name, test.expected, statements)
+		}
+	}
+}